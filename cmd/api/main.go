@@ -8,10 +8,19 @@ import (
 	"time"
 
 	apiv1 "go-chatty/cmd/api/router/v1"
+	"go-chatty/internal/infrastructure/auth"
+	authAdapter "go-chatty/internal/infrastructure/auth/adapter"
+	cacheAdapter "go-chatty/internal/infrastructure/cache/adapter"
 	"go-chatty/internal/infrastructure/database"
+	mediaAdapter "go-chatty/internal/infrastructure/media/adapter"
+	pushAdapter "go-chatty/internal/infrastructure/push/adapter"
 	queueAdapter "go-chatty/internal/infrastructure/queue/adapter"
 	queueport "go-chatty/internal/infrastructure/queue/port"
 	"go-chatty/internal/infrastructure/realtime"
+	realtimeAdapter "go-chatty/internal/infrastructure/realtime/adapter"
+	storageAdapter "go-chatty/internal/infrastructure/storage/adapter"
+	webhookAdapter "go-chatty/internal/infrastructure/webhook/adapter"
+	chatController "go-chatty/internal/pkg/chat/presentation/controller"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -41,7 +50,9 @@ func main() {
 	}
 	defer func() { _ = qClient.Close() }()
 
-	r := gin.Default()
+	// gin.Default's middleware, with websocket handshake tokens kept out of the request log
+	r := gin.New()
+	r.Use(gin.LoggerWithFormatter(auth.LogFormatter), gin.Recovery())
 
 	r.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -49,11 +60,78 @@ func main() {
 		})
 	})
 
+	// Cluster bus relays websocket fan-out between API replicas
+	bus, err := realtimeAdapter.NewRedisBusFromEnv()
+	if err != nil {
+		log.Fatalf("failed to initialize realtime bus: %v", err)
+	}
+	defer func() { _ = bus.Close() }()
+
+	// Delivery receipts observed during fan-out are batched into queue tasks
+	receiptBatcher := chatTask.NewReceiptBatcher(qClient)
+	receiptBatcher.Start()
+	defer receiptBatcher.Close()
+
+	// Presence and last-seen live in the cache; changes are announced to contacts through queue tasks
+	cache, err := cacheAdapter.NewRedisAdapter()
+	if err != nil {
+		log.Fatalf("failed to initialize cache: %v", err)
+	}
+	defer func() { _ = cache.Close() }()
+
+	presenceNotifier := chatTask.NewPresenceNotifier(qClient)
+	presenceNotifier.Start()
+	defer presenceNotifier.Close()
+
 	// Router manages websocket fan-out per user/session
-	realtimeRouter := realtime.NewRouter()
+	realtimeRouter, err := realtime.NewRouter(
+		realtime.WithBus(bus),
+		realtime.WithDeliveryRecorder(receiptBatcher),
+		realtime.WithMaxSessionsPerUser(realtime.MaxSessionsPerUserFromEnv()),
+		realtime.WithPresence(cache, presenceNotifier),
+		realtime.WithAwayAfter(realtime.AwayAfterFromEnv()),
+	)
+	if err != nil {
+		log.Fatalf("failed to initialize realtime router: %v", err)
+	}
 	defer realtimeRouter.Close()
 
-	apiv1.RegisterRoutes(r, pool, qClient, realtimeRouter)
+	// Stored messages and conversations are published from the outbox, in order per conversation,
+	// by whichever replica locks the conversation first
+	messageBroadcaster := chatController.NewMessageBroadcaster(pool, realtimeRouter)
+	outboxRelay := chatTask.NewOutboxRelay(pool, qClient, messageBroadcaster)
+	outboxRelay.Start()
+	defer outboxRelay.Close()
+
+	// Webhook deliveries whose next attempt got lost on the way to the queue are queued again
+	webhookSweeper := chatTask.NewWebhookSweeper(pool, qClient)
+	webhookSweeper.Start()
+	defer webhookSweeper.Close()
+
+	// Authenticator verifies bearer credentials for every v1 route
+	authn, err := authAdapter.NewAuthenticatorFromEnv()
+	if err != nil {
+		log.Fatalf("failed to initialize authenticator: %v", err)
+	}
+
+	// Attachments go straight from clients to object storage through presigned requests
+	storage, err := storageAdapter.NewStorageFromEnv()
+	if err != nil {
+		log.Fatalf("failed to initialize attachment storage: %v", err)
+	}
+	attachmentPolicy := storageAdapter.PolicyFromEnv()
+	if local, ok := storage.(*storageAdapter.LocalStorage); ok {
+		// The local store serves its presigned URLs itself; they carry their own signature instead of a bearer token
+		r.Any(local.MountPath()+"/*key", gin.WrapH(local))
+	}
+
+	apiv1.RegisterRoutes(r, pool, qClient, realtimeRouter, authn, auth.NewOriginCheckerFromEnv(), auth.NewAdminCheckerFromEnv(), storage, attachmentPolicy)
+
+	// Offline recipients are reached through a push provider
+	pushProvider, err := pushAdapter.NewProviderFromEnv()
+	if err != nil {
+		log.Fatalf("failed to initialize push provider: %v", err)
+	}
 
 	// Initialize Asynq server (worker) and launch in a goroutine
 	srv, err := queueAdapter.NewAsynqServer()
@@ -62,7 +140,14 @@ func main() {
 	}
 
 	// Register chat tasks
-	chatTask.RegisterSendMessageTask(srv, pool)
+	chatTask.RegisterSendMessageTask(srv, pool, storage, attachmentPolicy)
+	chatTask.RegisterProcessAttachmentTask(srv, pool, storage, mediaAdapter.NewImageAnalyzerFromEnv(), attachmentPolicy, messageBroadcaster)
+	chatTask.RegisterRecordReceiptTask(srv, pool, realtimeRouter)
+	chatTask.RegisterPresenceTask(srv, pool, realtimeRouter)
+	chatTask.RegisterNotifyMessageTask(srv, pool, qClient, pushAdapter.CollapseWindowFromEnv())
+	chatTask.RegisterPushNotificationTask(srv, pool, pushProvider)
+	chatTask.RegisterDispatchEventTask(srv, pool, qClient)
+	chatTask.RegisterDeliverWebhookTask(srv, pool, qClient, webhookAdapter.NewHTTPSenderFromEnv())
 
 	go func() {
 		if err := srv.Run(context.Background()); err != nil {
@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"go-chatty/internal/infrastructure/auth"
+	authport "go-chatty/internal/infrastructure/auth/port"
 	qport "go-chatty/internal/infrastructure/queue/port"
 	"go-chatty/internal/infrastructure/realtime"
+	storageport "go-chatty/internal/infrastructure/storage/port"
 	httpHandler "go-chatty/internal/pkg/chat/presentation/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,8 +13,11 @@ import (
 )
 
 // RegisterRoutes mounts all version 1 API routes under /api/v1
-func RegisterRoutes(r *gin.Engine, pool *pgxpool.Pool, client qport.Client, router *realtime.Router) {
+// Every v1 route requires an authenticated principal.
+func RegisterRoutes(r *gin.Engine, pool *pgxpool.Pool, client qport.Client, router *realtime.Router, authn authport.Authenticator, checkOrigin auth.OriginChecker,
+	isAdmin auth.AdminChecker, storage storageport.Storage, policy storageport.Policy) {
 	v1 := r.Group("/api/v1")
-	// Pass the DB connection and queue client down to the HTTP layer
-	httpHandler.RegisterRoutes(v1, pool, client, router)
+	v1.Use(auth.Middleware(authn))
+	// Pass the DB connection, queue client and attachment storage down to the HTTP layer
+	httpHandler.RegisterRoutes(v1, pool, client, router, checkOrigin, isAdmin, storage, policy)
 }
@@ -0,0 +1,24 @@
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go-chatty/internal/infrastructure/auth/port"
+)
+
+// NewAuthenticatorFromEnv picks an authenticator according to AUTH_MODE:
+// - "jwt" (default): see NewJWTAuthenticatorFromEnv
+// - "static": see NewStaticAuthenticatorFromEnv (development only)
+func NewAuthenticatorFromEnv() (port.Authenticator, error) {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("AUTH_MODE")))
+	switch mode {
+	case "", "jwt":
+		return NewJWTAuthenticatorFromEnv()
+	case "static":
+		return NewStaticAuthenticatorFromEnv()
+	default:
+		return nil, fmt.Errorf("auth: unsupported AUTH_MODE %q", mode)
+	}
+}
@@ -0,0 +1,77 @@
+package adapter
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// LoadJWKSFile reads a JWKS document from disk and returns its RSA signing keys keyed by "kid".
+// Non-RSA keys and keys reserved for encryption are skipped.
+func LoadJWKSFile(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth: read jwks: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS decodes a JWKS document and returns its RSA signing keys keyed by "kid".
+func ParseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set jwkSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("auth: parse jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		if k.Alg != "" && k.Alg != AlgRS256 {
+			continue
+		}
+		pub, err := rsaKeyFromJWK(k)
+		if err != nil {
+			return nil, fmt.Errorf("auth: jwks key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("auth: jwks contains no RSA signing keys")
+	}
+	return keys, nil
+}
+
+func rsaKeyFromJWK(k jwk) (*rsa.PublicKey, error) {
+	nb, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eb, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+	e := new(big.Int).SetBytes(eb)
+	if !e.IsInt64() || e.Int64() < 3 {
+		return nil, errors.New("invalid exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nb), E: int(e.Int64())}, nil
+}
@@ -0,0 +1,280 @@
+package adapter
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth/port"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+
+	defaultTenantClaim = "tenant_id"
+	defaultLeeway      = 30 * time.Second
+)
+
+// JWTConfig configures a JWTAuthenticator. Exactly one key source must match Algorithm:
+// Secret for HS256, PublicKeys for RS256 (keyed by "kid"; the "" entry is used when tokens carry no kid).
+type JWTConfig struct {
+	Algorithm   string
+	Secret      []byte
+	PublicKeys  map[string]*rsa.PublicKey
+	Issuer      string // optional; enforced when non-empty
+	Audience    string // optional; enforced when non-empty
+	TenantClaim string // claim holding the tenant id (default "tenant_id")
+	Leeway      time.Duration
+}
+
+// JWTAuthenticator verifies compact JWS tokens signed with HS256 or RS256.
+type JWTAuthenticator struct {
+	cfg JWTConfig
+	now func() time.Time
+}
+
+// NewJWTAuthenticator validates cfg and constructs a JWTAuthenticator.
+func NewJWTAuthenticator(cfg JWTConfig) (*JWTAuthenticator, error) {
+	switch cfg.Algorithm {
+	case AlgHS256:
+		if len(cfg.Secret) == 0 {
+			return nil, errors.New("auth: HS256 requires a secret")
+		}
+	case AlgRS256:
+		if len(cfg.PublicKeys) == 0 {
+			return nil, errors.New("auth: RS256 requires at least one public key")
+		}
+	default:
+		return nil, fmt.Errorf("auth: unsupported jwt algorithm %q", cfg.Algorithm)
+	}
+	if cfg.TenantClaim == "" {
+		cfg.TenantClaim = defaultTenantClaim
+	}
+	if cfg.Leeway <= 0 {
+		cfg.Leeway = defaultLeeway
+	}
+	return &JWTAuthenticator{cfg: cfg, now: time.Now}, nil
+}
+
+// NewJWTAuthenticatorFromEnv constructs a JWTAuthenticator using:
+// - JWT_ALGORITHM: "HS256" (default) or "RS256"
+// - JWT_SECRET: shared secret for HS256
+// - JWT_PUBLIC_KEY_FILE: PEM-encoded RSA public key for RS256
+// - JWT_JWKS_FILE: JWKS document with RSA keys for RS256 (alternative to JWT_PUBLIC_KEY_FILE)
+// - JWT_ISSUER / JWT_AUDIENCE: optional expected "iss" / "aud"
+// - JWT_TENANT_CLAIM: optional claim name for the tenant id (default "tenant_id")
+func NewJWTAuthenticatorFromEnv() (*JWTAuthenticator, error) {
+	alg := strings.ToUpper(strings.TrimSpace(os.Getenv("JWT_ALGORITHM")))
+	if alg == "" {
+		alg = AlgHS256
+	}
+	cfg := JWTConfig{
+		Algorithm:   alg,
+		Issuer:      strings.TrimSpace(os.Getenv("JWT_ISSUER")),
+		Audience:    strings.TrimSpace(os.Getenv("JWT_AUDIENCE")),
+		TenantClaim: strings.TrimSpace(os.Getenv("JWT_TENANT_CLAIM")),
+	}
+
+	switch alg {
+	case AlgHS256:
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, errors.New("auth: JWT_SECRET environment variable is not set")
+		}
+		cfg.Secret = []byte(secret)
+	case AlgRS256:
+		keys := make(map[string]*rsa.PublicKey)
+		if path := strings.TrimSpace(os.Getenv("JWT_JWKS_FILE")); path != "" {
+			loaded, err := LoadJWKSFile(path)
+			if err != nil {
+				return nil, err
+			}
+			for kid, k := range loaded {
+				keys[kid] = k
+			}
+		}
+		if path := strings.TrimSpace(os.Getenv("JWT_PUBLIC_KEY_FILE")); path != "" {
+			k, err := loadRSAPublicKeyFile(path)
+			if err != nil {
+				return nil, err
+			}
+			keys[""] = k
+		}
+		if len(keys) == 0 {
+			return nil, errors.New("auth: RS256 requires JWT_JWKS_FILE or JWT_PUBLIC_KEY_FILE")
+		}
+		cfg.PublicKeys = keys
+	}
+	return NewJWTAuthenticator(cfg)
+}
+
+// Ensure interface compliance at compile time
+var _ port.Authenticator = (*JWTAuthenticator)(nil)
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (port.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return port.Principal{}, fmt.Errorf("%w: malformed token", port.ErrUnauthenticated)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return port.Principal{}, fmt.Errorf("%w: invalid header", port.ErrUnauthenticated)
+	}
+	// Never let the token pick the algorithm; it must match configuration exactly.
+	if header.Alg != a.cfg.Algorithm {
+		return port.Principal{}, fmt.Errorf("%w: unexpected alg %q", port.ErrUnauthenticated, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return port.Principal{}, fmt.Errorf("%w: invalid signature encoding", port.ErrUnauthenticated)
+	}
+	if err := a.verify(parts[0]+"."+parts[1], sig, header.Kid); err != nil {
+		return port.Principal{}, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return port.Principal{}, fmt.Errorf("%w: invalid claims", port.ErrUnauthenticated)
+	}
+	return a.principalFromClaims(claims)
+}
+
+func (a *JWTAuthenticator) verify(signingInput string, sig []byte, kid string) error {
+	switch a.cfg.Algorithm {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, a.cfg.Secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return fmt.Errorf("%w: bad signature", port.ErrUnauthenticated)
+		}
+		return nil
+	case AlgRS256:
+		key, ok := a.cfg.PublicKeys[kid]
+		if !ok {
+			return fmt.Errorf("%w: unknown key id %q", port.ErrUnauthenticated, kid)
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("%w: bad signature", port.ErrUnauthenticated)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unsupported algorithm", port.ErrUnauthenticated)
+}
+
+func (a *JWTAuthenticator) principalFromClaims(claims map[string]any) (port.Principal, error) {
+	now := a.now()
+
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return port.Principal{}, fmt.Errorf("%w: missing exp", port.ErrUnauthenticated)
+	}
+	if now.After(time.Unix(exp, 0).Add(a.cfg.Leeway)) {
+		return port.Principal{}, fmt.Errorf("%w: token expired", port.ErrUnauthenticated)
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(a.cfg.Leeway).Before(time.Unix(nbf, 0)) {
+		return port.Principal{}, fmt.Errorf("%w: token not yet valid", port.ErrUnauthenticated)
+	}
+
+	if a.cfg.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.cfg.Issuer {
+			return port.Principal{}, fmt.Errorf("%w: unexpected issuer", port.ErrUnauthenticated)
+		}
+	}
+	if a.cfg.Audience != "" && !audienceContains(claims["aud"], a.cfg.Audience) {
+		return port.Principal{}, fmt.Errorf("%w: unexpected audience", port.ErrUnauthenticated)
+	}
+
+	// Users and tenants are stored under UUIDs; anything else could never match a row
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return port.Principal{}, fmt.Errorf("%w: missing sub", port.ErrUnauthenticated)
+	}
+	if _, err := uuid.Parse(sub); err != nil {
+		return port.Principal{}, fmt.Errorf("%w: sub is not a uuid", port.ErrUnauthenticated)
+	}
+	tenant, _ := claims[a.cfg.TenantClaim].(string)
+	if tenant != "" {
+		if _, err := uuid.Parse(tenant); err != nil {
+			return port.Principal{}, fmt.Errorf("%w: %s is not a uuid", port.ErrUnauthenticated, a.cfg.TenantClaim)
+		}
+	}
+	return port.Principal{UserID: sub, TenantID: tenant}, nil
+}
+
+func decodeSegment(seg string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func numericClaim(claims map[string]any, name string) (int64, bool) {
+	switch v := claims[name].(type) {
+	case float64:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// audienceContains accepts both the string and the array form of "aud".
+func audienceContains(aud any, expected string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == expected
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func loadRSAPublicKeyFile(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth: read public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("auth: public key file is not PEM encoded")
+	}
+	if pub, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		if k, ok := pub.(*rsa.PublicKey); ok {
+			return k, nil
+		}
+		return nil, errors.New("auth: public key is not RSA")
+	}
+	k, err := x509.ParsePKCS1PublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("auth: parse public key: %w", err)
+	}
+	return k, nil
+}
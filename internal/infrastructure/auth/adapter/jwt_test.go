@@ -0,0 +1,229 @@
+package adapter
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth/port"
+)
+
+const (
+	testUser   = "0d9b8c1e-5a4f-4e2b-9f3a-7c6d5e4f3a2b"
+	testTenant = "6a1f2e3d-4c5b-4a69-8877-665544332211"
+	testSecret = "0123456789abcdef0123456789abcdef"
+)
+
+var testNow = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// signToken builds a compact JWS with the given header and claims. Unless sign is nil, it signs the
+// signing input and appends the signature.
+func signToken(t *testing.T, header map[string]any, claims map[string]any, sign func(input []byte) []byte) string {
+	t.Helper()
+	enc := func(v any) string {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	input := enc(header) + "." + enc(claims)
+	var sig []byte
+	if sign != nil {
+		sig = sign([]byte(input))
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func hs256(secret []byte) func([]byte) []byte {
+	return func(input []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil)
+	}
+}
+
+func rs256(t *testing.T, key *rsa.PrivateKey) func([]byte) []byte {
+	return func(input []byte) []byte {
+		digest := sha256.Sum256(input)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return sig
+	}
+}
+
+func validClaims() map[string]any {
+	return map[string]any{"sub": testUser, "tenant_id": testTenant, "exp": testNow.Add(time.Hour).Unix()}
+}
+
+// with returns a copy of validClaims with name set to v, or removed when v is nil.
+func with(name string, v any) map[string]any {
+	c := validClaims()
+	if v == nil {
+		delete(c, name)
+	} else {
+		c[name] = v
+	}
+	return c
+}
+
+func newTestAuthenticator(t *testing.T, cfg JWTConfig) *JWTAuthenticator {
+	t.Helper()
+	a, err := NewJWTAuthenticator(cfg)
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator: %v", err)
+	}
+	a.now = func() time.Time { return testNow }
+	return a
+}
+
+type tokenCase struct {
+	name  string
+	token string
+	ok    bool
+}
+
+func runTokenCases(t *testing.T, a *JWTAuthenticator, cases []tokenCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := a.Authenticate(context.Background(), tc.token)
+			if !tc.ok {
+				if !errors.Is(err, port.ErrUnauthenticated) {
+					t.Fatalf("err = %v, want ErrUnauthenticated", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if p.UserID != testUser || p.TenantID != testTenant {
+				t.Fatalf("principal = %+v", p)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticatorHS256(t *testing.T) {
+	a := newTestAuthenticator(t, JWTConfig{Algorithm: AlgHS256, Secret: []byte(testSecret)})
+	hs := map[string]any{"alg": AlgHS256, "typ": "JWT"}
+	sign := hs256([]byte(testSecret))
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	runTokenCases(t, a, []tokenCase{
+		{"valid", signToken(t, hs, validClaims(), sign), true},
+		{"expired within leeway", signToken(t, hs, with("exp", testNow.Add(-10*time.Second).Unix()), sign), true},
+		{"bad signature", signToken(t, hs, validClaims(), hs256([]byte("another secret"))), false},
+		{"tampered claims", tamper(t, signToken(t, hs, validClaims(), sign)), false},
+		{"alg none", signToken(t, map[string]any{"alg": "none"}, validClaims(), nil), false},
+		{"alg none with signature", signToken(t, map[string]any{"alg": "none"}, validClaims(), sign), false},
+		{"alg switched to RS256", signToken(t, map[string]any{"alg": AlgRS256}, validClaims(), rs256(t, rsaKey)), false},
+		{"missing exp", signToken(t, hs, with("exp", nil), sign), false},
+		{"expired", signToken(t, hs, with("exp", testNow.Add(-time.Hour).Unix()), sign), false},
+		{"exp as string", signToken(t, hs, with("exp", "4102444800"), sign), false},
+		{"not yet valid", signToken(t, hs, with("nbf", testNow.Add(time.Hour).Unix()), sign), false},
+		{"missing sub", signToken(t, hs, with("sub", nil), sign), false},
+		{"sub not a uuid", signToken(t, hs, with("sub", "alice"), sign), false},
+		{"tenant not a uuid", signToken(t, hs, with("tenant_id", "acme"), sign), false},
+		{"malformed", "not.a-token", false},
+		{"empty", "", false},
+	})
+}
+
+// tamper swaps the claims of token for others while keeping its header and signature.
+func tamper(t *testing.T, token string) string {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	raw, err := json.Marshal(with("sub", "9e8d7c6b-5a49-4837-a625-140f0e0d0c0b"))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return parts[0] + "." + base64.RawURLEncoding.EncodeToString(raw) + "." + parts[2]
+}
+
+func TestJWTAuthenticatorRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	a := newTestAuthenticator(t, JWTConfig{
+		Algorithm:  AlgRS256,
+		PublicKeys: map[string]*rsa.PublicKey{"k1": &key.PublicKey, "": &other.PublicKey},
+		Issuer:     "https://issuer.example",
+		Audience:   "chat",
+	})
+	claims := func(overrides map[string]any) map[string]any {
+		c := validClaims()
+		c["iss"], c["aud"] = "https://issuer.example", []any{"other", "chat"}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+	k1 := map[string]any{"alg": AlgRS256, "kid": "k1"}
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	runTokenCases(t, a, []tokenCase{
+		{"valid", signToken(t, k1, claims(nil), rs256(t, key)), true},
+		{"default key without kid", signToken(t, map[string]any{"alg": AlgRS256}, claims(nil), rs256(t, other)), true},
+		{"signed with another key", signToken(t, k1, claims(nil), rs256(t, other)), false},
+		{"unknown kid", signToken(t, map[string]any{"alg": AlgRS256, "kid": "k2"}, claims(nil), rs256(t, key)), false},
+		// The classic confusion: an HMAC keyed with the public key the server trusts
+		{"alg switched to HS256", signToken(t, map[string]any{"alg": AlgHS256, "kid": "k1"}, claims(nil), hs256(pubDER)), false},
+		{"alg none", signToken(t, map[string]any{"alg": "none", "kid": "k1"}, claims(nil), nil), false},
+		{"wrong issuer", signToken(t, k1, claims(map[string]any{"iss": "https://evil.example"}), rs256(t, key)), false},
+		{"wrong audience", signToken(t, k1, claims(map[string]any{"aud": "billing"}), rs256(t, key)), false},
+		{"expired", signToken(t, k1, claims(map[string]any{"exp": testNow.Add(-time.Hour).Unix()}), rs256(t, key)), false},
+	})
+}
+
+func TestJWTAuthenticatorJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+	doc, _ := json.Marshal(map[string]any{"keys": []map[string]any{
+		{"kty": "RSA", "kid": "sig-1", "use": "sig", "alg": AlgRS256, "n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes())},
+		// Skipped: reserved for encryption, and not RSA
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256"},
+	}})
+	keys, err := ParseJWKS(doc)
+	if err != nil {
+		t.Fatalf("ParseJWKS: %v", err)
+	}
+	if len(keys) != 1 || keys["sig-1"] == nil {
+		t.Fatalf("keys = %v, want sig-1 only", keys)
+	}
+	a := newTestAuthenticator(t, JWTConfig{Algorithm: AlgRS256, PublicKeys: keys})
+
+	runTokenCases(t, a, []tokenCase{
+		{"valid", signToken(t, map[string]any{"alg": AlgRS256, "kid": "sig-1"}, validClaims(), rs256(t, key)), true},
+		{"encryption key", signToken(t, map[string]any{"alg": AlgRS256, "kid": "enc-1"}, validClaims(), rs256(t, key)), false},
+		{"missing kid", signToken(t, map[string]any{"alg": AlgRS256}, validClaims(), rs256(t, key)), false},
+	})
+}
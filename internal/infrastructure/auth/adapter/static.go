@@ -0,0 +1,74 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"go-chatty/internal/infrastructure/auth/port"
+)
+
+// StaticAuthenticator maps fixed tokens to principals. Meant for local development only.
+type StaticAuthenticator struct {
+	tokens map[string]port.Principal
+}
+
+// NewStaticAuthenticator constructs a StaticAuthenticator from a token -> principal map.
+func NewStaticAuthenticator(tokens map[string]port.Principal) *StaticAuthenticator {
+	return &StaticAuthenticator{tokens: tokens}
+}
+
+// NewStaticAuthenticatorFromEnv parses AUTH_STATIC_TOKENS, a CSV like
+// "token1=userId1,token2=userId2@tenantId" (the "@tenantId" suffix is optional).
+func NewStaticAuthenticatorFromEnv() (*StaticAuthenticator, error) {
+	raw := strings.TrimSpace(os.Getenv("AUTH_STATIC_TOKENS"))
+	if raw == "" {
+		return nil, errors.New("auth: AUTH_STATIC_TOKENS environment variable is not set")
+	}
+	tokens := parseStaticTokens(raw)
+	if len(tokens) == 0 {
+		return nil, errors.New("auth: AUTH_STATIC_TOKENS contains no valid entries")
+	}
+	return NewStaticAuthenticator(tokens), nil
+}
+
+// Ensure interface compliance at compile time
+var _ port.Authenticator = (*StaticAuthenticator)(nil)
+
+func (a *StaticAuthenticator) Authenticate(ctx context.Context, token string) (port.Principal, error) {
+	p, ok := a.tokens[token]
+	if !ok || token == "" {
+		return port.Principal{}, port.ErrUnauthenticated
+	}
+	return p, nil
+}
+
+// parseStaticTokens parses strings like "t1=u1,t2=u2@tenant" into a map.
+func parseStaticTokens(s string) map[string]port.Principal {
+	res := make(map[string]port.Principal)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		token := strings.TrimSpace(kv[0])
+		identity := strings.TrimSpace(kv[1])
+		if token == "" || identity == "" {
+			continue
+		}
+		p := port.Principal{UserID: identity}
+		if user, tenant, found := strings.Cut(identity, "@"); found {
+			p = port.Principal{UserID: strings.TrimSpace(user), TenantID: strings.TrimSpace(tenant)}
+		}
+		if p.UserID == "" {
+			continue
+		}
+		res[token] = p
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"go-chatty/internal/infrastructure/auth/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminChecker decides whether a principal may manage the settings of its tenant, such as webhooks.
+type AdminChecker func(p port.Principal) bool
+
+// NewAdminChecker grants administration to the listed user ids, each within their own tenant.
+// With an empty list nobody is an administrator.
+func NewAdminChecker(userIDs []string) AdminChecker {
+	set := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			set[id] = struct{}{}
+		}
+	}
+	return func(p port.Principal) bool {
+		_, ok := set[p.UserID]
+		return ok
+	}
+}
+
+// NewAdminCheckerFromEnv reads the administrators from ADMIN_USER_IDS, a CSV of user ids.
+func NewAdminCheckerFromEnv() AdminChecker {
+	return NewAdminChecker(strings.Split(os.Getenv("ADMIN_USER_IDS"), ","))
+}
+
+// RequireAdmin rejects requests whose principal isAdmin refuses with 403. It must run after Middleware.
+func RequireAdmin(isAdmin AdminChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := PrincipalFrom(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+		if !isAdmin(p) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "administrator privileges required"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogFormatter writes gin's request log lines in the default format, with the value of the "access_token"
+// query parameter websocket handshakes may carry replaced by "REDACTED", so credentials stay out of logs.
+func LogFormatter(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		redactAccessToken(param.Path),
+		param.ErrorMessage,
+	)
+}
+
+// redactAccessToken replaces the value of every access_token parameter in the query of path.
+func redactAccessToken(path string) string {
+	base, query, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	params := strings.Split(query, "&")
+	for i, p := range params {
+		if key, _, _ := strings.Cut(p, "="); key == "access_token" {
+			params[i] = "access_token=REDACTED"
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLogFormatterRedactsAccessToken(t *testing.T) {
+	for path, want := range map[string]string{
+		"/api/v1/chat/ws":                               "/api/v1/chat/ws",
+		"/api/v1/chat/ws?access_token=secret":           "/api/v1/chat/ws?access_token=REDACTED",
+		"/api/v1/chat/ws?device=a&access_token=secret":  "/api/v1/chat/ws?device=a&access_token=REDACTED",
+		"/api/v1/chat/ws?access_token=a&access_token=b": "/api/v1/chat/ws?access_token=REDACTED&access_token=REDACTED",
+		"/search?q=access_token":                        "/search?q=access_token",
+	} {
+		line := LogFormatter(gin.LogFormatterParams{Method: "GET", Path: path, StatusCode: 101})
+		if !strings.Contains(line, `"`+want+`"`) || strings.Contains(line, "secret") {
+			t.Fatalf("logged %q for %s, want path %q", line, path, want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"go-chatty/internal/infrastructure/auth/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+const principalKey = "auth.principal"
+
+// Middleware authenticates every request with authn and stores the verified
+// Principal in the gin context. Requests without a valid credential are rejected with 401.
+//
+// Credentials are read from the "Authorization: Bearer <token>" header. Browsers cannot
+// set headers on websocket handshakes, so upgrade requests may pass "access_token" as a query parameter instead.
+// The parameter is removed from the request once read; LogFormatter keeps it out of the request log.
+func Middleware(authn port.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerToken(c.Request)
+		// Nothing downstream needs a query credential, and panic dumps print the request line
+		stripAccessToken(c.Request)
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		p, err := authn.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+			return
+		}
+
+		c.Set(principalKey, p)
+		c.Next()
+	}
+}
+
+// PrincipalFrom returns the Principal stored by Middleware, if any.
+func PrincipalFrom(c *gin.Context) (port.Principal, bool) {
+	v, ok := c.Get(principalKey)
+	if !ok {
+		return port.Principal{}, false
+	}
+	p, ok := v.(port.Principal)
+	if !ok || p.UserID == "" {
+		return port.Principal{}, false
+	}
+	return p, true
+}
+
+func bearerToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		scheme, token, found := strings.Cut(h, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	if isWebsocketUpgrade(r) {
+		return strings.TrimSpace(r.URL.Query().Get("access_token"))
+	}
+	return ""
+}
+
+// stripAccessToken removes the "access_token" query parameter from r's URL.
+func stripAccessToken(r *http.Request) {
+	q := r.URL.Query()
+	if !q.Has("access_token") {
+		return
+	}
+	q.Del("access_token")
+	r.URL.RawQuery = q.Encode()
+}
+
+func isWebsocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// OriginChecker decides whether a websocket handshake from the request's Origin is allowed.
+type OriginChecker func(r *http.Request) bool
+
+// NewOriginChecker builds an OriginChecker from an allow-list of origins such as
+// "https://app.example.com". A "*" entry allows every origin. With an empty list only
+// same-host origins are accepted. Requests without an Origin header (non-browser clients) are always allowed.
+func NewOriginChecker(allowed []string) OriginChecker {
+	allowAll := false
+	set := make(map[string]struct{}, len(allowed))
+	for _, o := range allowed {
+		o = strings.TrimRight(strings.ToLower(strings.TrimSpace(o)), "/")
+		if o == "" {
+			continue
+		}
+		if o == "*" {
+			allowAll = true
+			continue
+		}
+		set[o] = struct{}{}
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || allowAll {
+			return true
+		}
+		if len(set) == 0 {
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		}
+		_, ok := set[strings.TrimRight(strings.ToLower(origin), "/")]
+		return ok
+	}
+}
+
+// NewOriginCheckerFromEnv reads the allow-list from WS_ALLOWED_ORIGINS, a CSV of origins.
+func NewOriginCheckerFromEnv() OriginChecker {
+	return NewOriginChecker(strings.Split(os.Getenv("WS_ALLOWED_ORIGINS"), ","))
+}
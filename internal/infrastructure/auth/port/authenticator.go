@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+	"errors"
+)
+
+// Principal is the verified identity behind a request.
+type Principal struct {
+	UserID   string
+	TenantID string // empty when the credential carries no tenant
+}
+
+// Authenticator verifies a bearer credential and resolves the Principal it belongs to.
+// Implementations must be safe for concurrent use.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (Principal, error)
+}
+
+// ErrUnauthenticated should be returned (optionally wrapped) by adapters when the
+// credential is missing, malformed, expired or otherwise not acceptable.
+var ErrUnauthenticated = errors.New("auth: unauthenticated")
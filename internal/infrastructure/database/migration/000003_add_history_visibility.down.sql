@@ -0,0 +1,6 @@
+-- 000003_add_history_visibility.down.sql
+ALTER TABLE chat.participant DROP COLUMN IF EXISTS joined_at;
+
+ALTER TABLE chat.conversation
+  DROP COLUMN IF EXISTS history_hidden,
+  DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,20 @@
+-- 000003_add_history_visibility.up.sql
+-- Track conversation kind and whether group history is visible to newcomers,
+-- plus when each participant joined so history can be cut off at that point.
+
+ALTER TABLE chat.conversation
+  ADD COLUMN IF NOT EXISTS kind           SMALLINT NOT NULL DEFAULT 0,     -- 0=direct, 1=group
+  ADD COLUMN IF NOT EXISTS history_hidden BOOLEAN  NOT NULL DEFAULT FALSE; -- groups only: hide messages sent before a member joined
+
+-- Existing members keep full history: backfill with the conversation creation time
+ALTER TABLE chat.participant
+  ADD COLUMN IF NOT EXISTS joined_at TIMESTAMP;
+
+UPDATE chat.participant p
+SET joined_at = c.created_at
+FROM chat.conversation c
+WHERE c.id = p.conversation_id AND p.joined_at IS NULL;
+
+ALTER TABLE chat.participant
+  ALTER COLUMN joined_at SET DEFAULT (now() AT TIME ZONE 'utc'),
+  ALTER COLUMN joined_at SET NOT NULL;
@@ -0,0 +1,4 @@
+-- 000004_add_message_keyset_index.down.sql
+CREATE INDEX IF NOT EXISTS idx_messages_conv_created ON chat.message(conversation_id, created_at);
+
+DROP INDEX IF EXISTS chat.idx_messages_conv_created_id;
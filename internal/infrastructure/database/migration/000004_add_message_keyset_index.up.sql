@@ -0,0 +1,7 @@
+-- 000004_add_message_keyset_index.up.sql
+-- Keyset pagination walks (created_at, id) within a conversation; the id tiebreaker
+-- keeps pages stable when several messages share the same timestamp.
+CREATE INDEX IF NOT EXISTS idx_messages_conv_created_id ON chat.message(conversation_id, created_at DESC, id DESC);
+
+-- Superseded by the composite index above
+DROP INDEX IF EXISTS chat.idx_messages_conv_created;
@@ -0,0 +1,4 @@
+-- 000005_add_group_profile.down.sql
+ALTER TABLE chat.conversation
+  DROP COLUMN IF EXISTS avatar_url,
+  DROP COLUMN IF EXISTS title;
@@ -0,0 +1,6 @@
+-- 000005_add_group_profile.up.sql
+-- Group conversations carry a display title and avatar.
+-- participant.role values: 0=member, 1=admin, 2=owner
+ALTER TABLE chat.conversation
+  ADD COLUMN IF NOT EXISTS title      VARCHAR(255),
+  ADD COLUMN IF NOT EXISTS avatar_url TEXT;
@@ -0,0 +1,4 @@
+-- 000006_add_direct_pair_key.down.sql
+DROP INDEX IF EXISTS chat.uq_conversation_direct_pair;
+
+ALTER TABLE chat.conversation DROP COLUMN IF EXISTS pair_key;
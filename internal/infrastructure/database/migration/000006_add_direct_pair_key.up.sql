@@ -0,0 +1,32 @@
+-- 000006_add_direct_pair_key.up.sql
+-- Direct conversations are unique per tenant and unordered pair of users.
+-- pair_key holds "<lower user id>:<higher user id>" for direct conversations and NULL for groups.
+ALTER TABLE chat.conversation
+  ADD COLUMN IF NOT EXISTS pair_key VARCHAR(73);
+
+-- Backfill existing direct conversations; when duplicates already exist only the oldest one is keyed
+WITH pairs AS (
+  SELECT c.id,
+         c.tenant_id,
+         c.created_at,
+         min(p.user_id::text) || ':' || max(p.user_id::text) AS pair_key
+  FROM chat.conversation c
+  JOIN chat.participant p ON p.conversation_id = c.id
+  WHERE c.kind = 0
+  GROUP BY c.id
+  HAVING count(*) = 2
+), ranked AS (
+  SELECT id,
+         pair_key,
+         row_number() OVER (PARTITION BY tenant_id, pair_key ORDER BY created_at, id) AS rn
+  FROM pairs
+)
+UPDATE chat.conversation c
+SET pair_key = r.pair_key
+FROM ranked r
+WHERE c.id = r.id AND r.rn = 1;
+
+-- NULL tenants are folded into a fixed uuid so they take part in uniqueness
+CREATE UNIQUE INDEX IF NOT EXISTS uq_conversation_direct_pair
+  ON chat.conversation (COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), pair_key)
+  WHERE pair_key IS NOT NULL;
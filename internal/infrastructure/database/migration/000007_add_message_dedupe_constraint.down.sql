@@ -0,0 +1,2 @@
+-- 000007_add_message_dedupe_constraint.down.sql
+DROP INDEX IF EXISTS chat.uq_message_dedupe;
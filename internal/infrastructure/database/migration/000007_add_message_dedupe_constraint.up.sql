@@ -0,0 +1,18 @@
+-- 000007_add_message_dedupe_constraint.up.sql
+-- A client retry carrying the same dedupe_key must not create a second message.
+
+-- Keep the oldest row of any pre-existing duplicates keyed; later copies lose their key rather than being deleted
+WITH ranked AS (
+  SELECT id,
+         row_number() OVER (PARTITION BY conversation_id, sender_id, dedupe_key ORDER BY created_at, id) AS rn
+  FROM chat.message
+  WHERE dedupe_key IS NOT NULL
+)
+UPDATE chat.message m
+SET dedupe_key = NULL
+FROM ranked r
+WHERE m.id = r.id AND r.rn > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_message_dedupe
+  ON chat.message (conversation_id, sender_id, dedupe_key)
+  WHERE dedupe_key IS NOT NULL;
@@ -0,0 +1,7 @@
+-- 000008_add_message_edits.down.sql
+DROP TABLE IF EXISTS chat.message_edit;
+
+ALTER TABLE chat.message
+  DROP COLUMN IF EXISTS deleted_by,
+  DROP COLUMN IF EXISTS deleted_at,
+  DROP COLUMN IF EXISTS edited_at;
@@ -0,0 +1,18 @@
+-- 000008_add_message_edits.up.sql
+-- Messages can be edited by their sender and deleted for everyone.
+-- A deleted message stays in the log as a tombstone: its content is blanked and deleted_at is set.
+ALTER TABLE chat.message
+  ADD COLUMN IF NOT EXISTS edited_at  TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL,
+  ADD COLUMN IF NOT EXISTS deleted_by UUID NULL;
+
+-- Previous bodies of edited messages, purged when the message is deleted
+CREATE TABLE IF NOT EXISTS chat.message_edit (
+  id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  message_id    UUID NOT NULL REFERENCES chat.message(id) ON DELETE CASCADE,
+  editor_id     UUID NOT NULL,
+  previous_body TEXT,
+  edited_at     TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_edit_message ON chat.message_edit (message_id, edited_at);
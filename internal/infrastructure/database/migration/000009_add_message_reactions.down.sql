@@ -0,0 +1,2 @@
+-- 000009_add_message_reactions.down.sql
+DROP TABLE IF EXISTS chat.reaction;
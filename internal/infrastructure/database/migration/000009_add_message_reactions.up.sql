@@ -0,0 +1,12 @@
+-- 000009_add_message_reactions.up.sql
+-- Emoji reactions: a user reacts at most once per emoji on a message.
+CREATE TABLE IF NOT EXISTS chat.reaction (
+  message_id UUID NOT NULL REFERENCES chat.message(id) ON DELETE CASCADE,
+  user_id    UUID NOT NULL,
+  emoji      VARCHAR(64) NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (message_id, user_id, emoji)
+);
+
+-- Aggregation by message and emoji
+CREATE INDEX IF NOT EXISTS idx_reaction_message_emoji ON chat.reaction (message_id, emoji);
@@ -0,0 +1,8 @@
+-- 000010_add_message_threads.down.sql
+DROP INDEX IF EXISTS chat.idx_message_thread_created_id;
+
+ALTER TABLE chat.message
+  DROP COLUMN IF EXISTS thread_last_reply_at,
+  DROP COLUMN IF EXISTS thread_reply_count,
+  DROP COLUMN IF EXISTS thread_root_id,
+  DROP COLUMN IF EXISTS reply_to_id;
@@ -0,0 +1,13 @@
+-- 000010_add_message_threads.up.sql
+-- A message may quote another one (reply_to_id) and/or belong to the thread under a top-level
+-- message (thread_root_id). Thread replies are kept out of the main timeline; the root tracks
+-- how many replies it has and when the last one arrived.
+ALTER TABLE chat.message
+  ADD COLUMN IF NOT EXISTS reply_to_id          UUID NULL REFERENCES chat.message(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS thread_root_id       UUID NULL REFERENCES chat.message(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS thread_reply_count   INT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS thread_last_reply_at TIMESTAMP NULL;
+
+-- Keyset pagination through a thread's replies
+CREATE INDEX IF NOT EXISTS idx_message_thread_created_id ON chat.message (thread_root_id, created_at DESC, id DESC)
+  WHERE thread_root_id IS NOT NULL;
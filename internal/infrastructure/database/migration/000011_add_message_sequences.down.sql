@@ -0,0 +1,9 @@
+-- 000011_add_message_sequences.down.sql
+DROP INDEX IF EXISTS chat.idx_message_conv_change_seq;
+
+ALTER TABLE chat.message
+  DROP COLUMN IF EXISTS change_seq,
+  DROP COLUMN IF EXISTS seq;
+
+ALTER TABLE chat.conversation
+  DROP COLUMN IF EXISTS last_seq;
@@ -0,0 +1,27 @@
+-- 000011_add_message_sequences.up.sql
+-- Every change to a conversation's message log takes the next number of the conversation's sequence:
+-- seq is the number a message got when it was stored, change_seq the number of its latest change
+-- (edit, delete, new thread reply). Reconnecting clients ask for the changes after the last number they saw.
+ALTER TABLE chat.conversation
+  ADD COLUMN IF NOT EXISTS last_seq BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE chat.message
+  ADD COLUMN IF NOT EXISTS seq        BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT 0;
+
+-- Number existing messages in creation order
+UPDATE chat.message m
+SET seq = n.seq, change_seq = n.seq
+FROM (
+  SELECT id, row_number() OVER (PARTITION BY conversation_id ORDER BY created_at, id) AS seq
+  FROM chat.message
+) n
+WHERE m.id = n.id;
+
+UPDATE chat.conversation c
+SET last_seq = s.last_seq
+FROM (SELECT conversation_id, max(seq) AS last_seq FROM chat.message GROUP BY conversation_id) s
+WHERE c.id = s.conversation_id;
+
+-- Replay of the changes after a sequence number
+CREATE INDEX IF NOT EXISTS idx_message_conv_change_seq ON chat.message (conversation_id, change_seq);
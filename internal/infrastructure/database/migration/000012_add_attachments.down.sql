@@ -0,0 +1,7 @@
+-- 000012_add_attachments.down.sql
+DROP INDEX IF EXISTS chat.uq_message_attachment;
+
+ALTER TABLE chat.message
+  DROP COLUMN IF EXISTS attachment_id;
+
+DROP TABLE IF EXISTS chat.attachment;
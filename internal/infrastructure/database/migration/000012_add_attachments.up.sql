@@ -0,0 +1,18 @@
+-- 000012_add_attachments.up.sql
+-- Files uploaded to object storage. A row is created when the upload is signed; the object itself
+-- lives under storage_key. Each attachment can back a single message.
+CREATE TABLE IF NOT EXISTS chat.attachment (
+  id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  owner_id     UUID NOT NULL,
+  storage_key  TEXT NOT NULL UNIQUE,
+  file_name    TEXT NOT NULL,
+  content_type VARCHAR(255) NOT NULL,
+  size_bytes   BIGINT NOT NULL,
+  created_at   TIMESTAMP NOT NULL
+);
+
+ALTER TABLE chat.message
+  ADD COLUMN IF NOT EXISTS attachment_id UUID NULL REFERENCES chat.attachment(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_message_attachment ON chat.message (attachment_id)
+  WHERE attachment_id IS NOT NULL;
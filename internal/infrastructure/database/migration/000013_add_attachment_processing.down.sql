@@ -0,0 +1,8 @@
+-- 000013_add_attachment_processing.down.sql
+ALTER TABLE chat.attachment
+  DROP COLUMN IF EXISTS processed_at,
+  DROP COLUMN IF EXISTS thumbnail_key,
+  DROP COLUMN IF EXISTS height,
+  DROP COLUMN IF EXISTS width,
+  DROP COLUMN IF EXISTS checksum,
+  DROP COLUMN IF EXISTS sniffed_type;
@@ -0,0 +1,10 @@
+-- 000013_add_attachment_processing.up.sql
+-- What background media processing learned about an uploaded file. processed_at stays NULL until the
+-- file has been inspected; thumbnail_key locates the thumbnail of images larger than one.
+ALTER TABLE chat.attachment
+  ADD COLUMN IF NOT EXISTS sniffed_type  VARCHAR(255) NULL,
+  ADD COLUMN IF NOT EXISTS checksum      CHAR(64) NULL,
+  ADD COLUMN IF NOT EXISTS width         INTEGER NULL,
+  ADD COLUMN IF NOT EXISTS height        INTEGER NULL,
+  ADD COLUMN IF NOT EXISTS thumbnail_key TEXT NULL,
+  ADD COLUMN IF NOT EXISTS processed_at  TIMESTAMP NULL;
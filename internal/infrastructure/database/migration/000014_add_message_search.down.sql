@@ -0,0 +1,6 @@
+-- 000014_add_message_search.down.sql
+DROP INDEX IF EXISTS chat.idx_message_search;
+
+ALTER TABLE chat.message
+  DROP COLUMN IF EXISTS search_vector,
+  DROP COLUMN IF EXISTS search_config;
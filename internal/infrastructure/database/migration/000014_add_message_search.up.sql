@@ -0,0 +1,15 @@
+-- 000014_add_message_search.up.sql
+-- Full-text search over message bodies. search_config records the text search configuration (language) a
+-- message was indexed with: the session default when it was stored, which the API sets from SEARCH_LANGUAGE.
+-- Existing messages take the default of the session running this migration. Reindex with another language by
+-- updating search_config, e.g. UPDATE chat.message SET search_config = 'german';
+-- System messages carry JSON events, not text, and are left out of the index.
+ALTER TABLE chat.message
+  ADD COLUMN IF NOT EXISTS search_config regconfig NOT NULL DEFAULT get_current_ts_config();
+
+ALTER TABLE chat.message
+  ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    CASE WHEN msg_type = 3 THEN NULL ELSE to_tsvector(search_config, coalesce(body, '')) END
+  ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_message_search ON chat.message USING GIN (search_vector);
@@ -0,0 +1,6 @@
+-- 000015_add_conversation_activity.down.sql
+DROP INDEX IF EXISTS chat.idx_participant_user;
+
+ALTER TABLE chat.conversation
+  DROP COLUMN IF EXISTS last_activity_at,
+  DROP COLUMN IF EXISTS last_message_id;
@@ -0,0 +1,24 @@
+-- 000015_add_conversation_activity.up.sql
+-- The inbox lists a user's conversations by last activity with a preview of their latest message. Both are
+-- kept on the conversation row, updated in the transaction storing a top-level message, so listing needs no
+-- scan of the message table. A conversation without messages is as active as it is old.
+ALTER TABLE chat.conversation
+  ADD COLUMN IF NOT EXISTS last_message_id  UUID NULL,
+  ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMP NULL;
+
+UPDATE chat.conversation c
+SET last_message_id = l.id, last_activity_at = l.created_at
+FROM (
+  SELECT DISTINCT ON (conversation_id) conversation_id, id, created_at
+  FROM chat.message
+  WHERE thread_root_id IS NULL
+  ORDER BY conversation_id, created_at DESC, id DESC
+) l
+WHERE c.id = l.conversation_id;
+
+UPDATE chat.conversation SET last_activity_at = created_at WHERE last_activity_at IS NULL;
+
+ALTER TABLE chat.conversation ALTER COLUMN last_activity_at SET NOT NULL;
+
+-- The participant primary key leads with the conversation; the inbox starts from the user
+CREATE INDEX IF NOT EXISTS idx_participant_user ON chat.participant (user_id);
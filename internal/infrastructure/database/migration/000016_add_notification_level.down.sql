@@ -0,0 +1,3 @@
+-- 000016_add_notification_level.down.sql
+ALTER TABLE chat.participant
+  DROP COLUMN IF EXISTS notify_level;
@@ -0,0 +1,5 @@
+-- 000016_add_notification_level.up.sql
+-- How much of a conversation a participant wants to be notified about: 0 = all messages, 1 = mentions only,
+-- 2 = none. Muting (muted_until) silences every level until it expires; messages still reach the inbox.
+ALTER TABLE chat.participant
+  ADD COLUMN IF NOT EXISTS notify_level SMALLINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- 000017_add_device_tokens.down.sql
+DROP TABLE IF EXISTS chat.device_token;
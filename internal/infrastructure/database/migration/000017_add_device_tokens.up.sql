@@ -0,0 +1,13 @@
+-- 000017_add_device_tokens.up.sql
+-- Push tokens of the users' app installs. A token identifies one install, so registering it again, even for
+-- another user, takes it over; an install (device_id) keeps a single token. platform: 0 = fcm, 1 = apns.
+CREATE TABLE IF NOT EXISTS chat.device_token (
+  token      TEXT PRIMARY KEY,
+  user_id    UUID NOT NULL,
+  platform   SMALLINT NOT NULL,
+  device_id  VARCHAR(64) NULL,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_token_user ON chat.device_token (user_id);
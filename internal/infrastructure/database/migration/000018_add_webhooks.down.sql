@@ -0,0 +1,3 @@
+-- 000018_add_webhooks.down.sql
+DROP TABLE IF EXISTS chat.webhook_delivery;
+DROP TABLE IF EXISTS chat.webhook_subscription;
@@ -0,0 +1,38 @@
+-- 000018_add_webhooks.up.sql
+-- Endpoints tenants subscribe to their conversations' events, and the deliveries of those events.
+-- tenant_id is NULL for deployments without tenants; events is the filter of event types, empty for all.
+CREATE TABLE IF NOT EXISTS chat.webhook_subscription (
+  id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  tenant_id   UUID NULL,
+  url         TEXT NOT NULL,
+  secret      TEXT NOT NULL,
+  events      TEXT[] NOT NULL DEFAULT '{}',
+  description VARCHAR(255) NULL,
+  created_at  TIMESTAMP NOT NULL,
+  updated_at  TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscription_tenant
+  ON chat.webhook_subscription (COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+-- One row per event and subscription. payload is the exact request body; status: 0 = pending,
+-- 1 = delivered, 2 = dead (attempts exhausted, kept for inspection and replay).
+CREATE TABLE IF NOT EXISTS chat.webhook_delivery (
+  id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  subscription_id  UUID NOT NULL REFERENCES chat.webhook_subscription(id) ON DELETE CASCADE,
+  event_id         UUID NOT NULL,
+  event_type       VARCHAR(64) NOT NULL,
+  payload          JSONB NOT NULL,
+  status           SMALLINT NOT NULL DEFAULT 0,
+  attempts         INTEGER NOT NULL DEFAULT 0,
+  last_status_code INTEGER NULL,
+  last_error       TEXT NULL,
+  next_attempt_at  TIMESTAMP NULL,
+  created_at       TIMESTAMP NOT NULL,
+  updated_at       TIMESTAMP NOT NULL,
+  delivered_at     TIMESTAMP NULL,
+  CONSTRAINT uq_webhook_delivery_event UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_status
+  ON chat.webhook_delivery (subscription_id, status, created_at DESC, id DESC);
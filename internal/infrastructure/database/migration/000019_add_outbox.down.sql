@@ -0,0 +1,2 @@
+-- 000019_add_outbox.down.sql
+DROP TABLE IF EXISTS chat.outbox;
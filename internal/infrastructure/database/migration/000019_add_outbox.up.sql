@@ -0,0 +1,19 @@
+-- 000019_add_outbox.up.sql
+-- Changes waiting to be published to websockets, push and webhooks. Rows are written in the transaction that
+-- stores the change and deleted once published. kind: 0 = message created (message_id set),
+-- 1 = conversation created. event_id identifies the webhook events of the row across republishing.
+CREATE TABLE IF NOT EXISTS chat.outbox (
+  id              BIGSERIAL PRIMARY KEY,
+  event_id        UUID NOT NULL DEFAULT gen_random_uuid(),
+  kind            SMALLINT NOT NULL,
+  conversation_id UUID NOT NULL REFERENCES chat.conversation(id) ON DELETE CASCADE,
+  message_id      UUID NULL REFERENCES chat.message(id) ON DELETE CASCADE,
+  created_at      TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  last_error      TEXT NULL,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+-- Publishing keeps to id order within a conversation, which is commit order since writers of a
+-- conversation hold its row lock
+CREATE INDEX IF NOT EXISTS idx_outbox_conversation ON chat.outbox (conversation_id, id);
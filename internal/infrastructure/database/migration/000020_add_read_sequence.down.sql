@@ -0,0 +1,6 @@
+-- 000020_add_read_sequence.down.sql
+DROP INDEX IF EXISTS chat.idx_participant_conv_read_seq;
+
+ALTER TABLE chat.participant
+  DROP COLUMN IF EXISTS read_at,
+  DROP COLUMN IF EXISTS read_seq;
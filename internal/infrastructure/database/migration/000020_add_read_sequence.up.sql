@@ -0,0 +1,10 @@
+-- 000020_add_read_sequence.up.sql
+-- Moving a read watermark takes the next number of the conversation's sequence like any other change, so
+-- reconnecting clients replay the read receipts they missed. read_seq is the number of the participant's
+-- latest watermark move, read_at when it happened.
+ALTER TABLE chat.participant
+  ADD COLUMN IF NOT EXISTS read_seq BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS read_at  TIMESTAMP NULL;
+
+-- Replay of the read receipts after a sequence number
+CREATE INDEX IF NOT EXISTS idx_participant_conv_read_seq ON chat.participant (conversation_id, read_seq);
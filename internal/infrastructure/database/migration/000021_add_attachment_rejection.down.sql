@@ -0,0 +1,3 @@
+-- 000021_add_attachment_rejection.down.sql
+ALTER TABLE chat.attachment
+  DROP COLUMN IF EXISTS rejected;
@@ -0,0 +1,5 @@
+-- 000021_add_attachment_rejection.up.sql
+-- Processing checks the type sniffed from an uploaded file against the upload policy, like the declared type was
+-- when the upload was signed. rejected marks files whose real type the deployment does not accept.
+ALTER TABLE chat.attachment
+  ADD COLUMN IF NOT EXISTS rejected BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+-- 000022_add_webhook_delivery_due_index.down.sql
+DROP INDEX IF EXISTS chat.idx_webhook_delivery_due;
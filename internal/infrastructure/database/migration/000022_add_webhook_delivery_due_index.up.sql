@@ -0,0 +1,6 @@
+-- 000022_add_webhook_delivery_due_index.up.sql
+-- A sweep looks for pending deliveries whose next attempt is long overdue, i.e. whose queue task got lost,
+-- and queues them again.
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_due
+  ON chat.webhook_delivery (next_attempt_at)
+  WHERE status = 0;
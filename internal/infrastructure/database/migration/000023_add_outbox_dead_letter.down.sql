@@ -0,0 +1,5 @@
+-- 000023_add_outbox_dead_letter.down.sql
+DROP INDEX IF EXISTS chat.idx_outbox_due;
+
+ALTER TABLE chat.outbox
+  DROP COLUMN IF EXISTS dead_at;
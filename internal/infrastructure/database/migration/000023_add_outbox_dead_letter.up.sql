@@ -0,0 +1,11 @@
+-- 000023_add_outbox_dead_letter.up.sql
+-- Entries that keep failing to publish are set aside after chat.MaxOutboxAttempts attempts instead of being
+-- retried forever: dead_at marks them, they stay for inspection with their last_error, and the later entries
+-- of their conversation no longer wait for them.
+ALTER TABLE chat.outbox
+  ADD COLUMN IF NOT EXISTS dead_at TIMESTAMP NULL;
+
+-- Relays look for the conversations whose oldest live entry is due
+CREATE INDEX IF NOT EXISTS idx_outbox_due
+  ON chat.outbox (next_attempt_at, id)
+  WHERE dead_at IS NULL;
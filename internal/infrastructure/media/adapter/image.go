@@ -0,0 +1,195 @@
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-chatty/internal/infrastructure/media/port"
+)
+
+const (
+	defaultThumbnailSize = 320        // longest side of a thumbnail, in pixels
+	defaultMaxPixels     = 25_000_000 // larger images are measured but not decoded
+	sniffLength          = 512        // bytes http.DetectContentType looks at
+	thumbnailQuality     = 80
+)
+
+// decodable lists the sniffed types the standard library can decode.
+var decodable = map[string]bool{"image/jpeg": true, "image/png": true, "image/gif": true}
+
+// ImageAnalyzer sniffs and hashes any file, and measures and thumbnails JPEG, PNG and GIF images
+// with the standard library. Images are decoded in memory, so a pixel limit bounds the cost of one.
+type ImageAnalyzer struct {
+	thumbnailSize int
+	maxPixels     int
+}
+
+// NewImageAnalyzer makes thumbnails whose longest side is thumbnailSize pixels, for images of at most maxPixels pixels.
+func NewImageAnalyzer(thumbnailSize int, maxPixels int) *ImageAnalyzer {
+	if thumbnailSize <= 0 {
+		thumbnailSize = defaultThumbnailSize
+	}
+	if maxPixels <= 0 {
+		maxPixels = defaultMaxPixels
+	}
+	return &ImageAnalyzer{thumbnailSize: thumbnailSize, maxPixels: maxPixels}
+}
+
+// NewImageAnalyzerFromEnv reads MEDIA_THUMBNAIL_SIZE (default 320) and MEDIA_MAX_PIXELS (default 25 million).
+func NewImageAnalyzerFromEnv() *ImageAnalyzer {
+	return NewImageAnalyzer(intFromEnv("MEDIA_THUMBNAIL_SIZE"), intFromEnv("MEDIA_MAX_PIXELS"))
+}
+
+func intFromEnv(name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Ensure interface compliance at compile time
+var _ port.Analyzer = (*ImageAnalyzer)(nil)
+
+func (a *ImageAnalyzer) Analyze(ctx context.Context, r io.Reader) (port.Analysis, error) {
+	hash := sha256.New()
+	var size byteCounter
+	src := bufio.NewReaderSize(io.TeeReader(r, io.MultiWriter(hash, &size)), sniffLength)
+
+	head, err := src.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return port.Analysis{}, err
+	}
+	contentType := mediaType(http.DetectContentType(head))
+
+	// Only images are kept in memory; everything else is just hashed on the way through
+	var data []byte
+	if decodable[contentType] {
+		data, err = io.ReadAll(src)
+	} else {
+		_, err = io.Copy(io.Discard, src)
+	}
+	if err != nil {
+		return port.Analysis{}, err
+	}
+
+	out := port.Analysis{ContentType: contentType, Checksum: hex.EncodeToString(hash.Sum(nil)), Size: int64(size)}
+	if data == nil {
+		return out, nil
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		// The signature matched but the rest is not a valid image: report the type alone
+		return out, nil
+	}
+	out.Width, out.Height = cfg.Width, cfg.Height
+	if cfg.Width*cfg.Height > a.maxPixels || max(cfg.Width, cfg.Height) <= a.thumbnailSize {
+		return out, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return port.Analysis{}, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return out, nil
+	}
+	thumb, err := a.thumbnail(img)
+	if err != nil {
+		return port.Analysis{}, err
+	}
+	out.Thumbnail = thumb
+	return out, nil
+}
+
+// thumbnail scales img down to fit the thumbnail size. Opaque images become JPEGs, others keep their transparency as PNGs.
+func (a *ImageAnalyzer) thumbnail(img image.Image) (*port.Thumbnail, error) {
+	dst := downscale(img, a.thumbnailSize)
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	var err error
+	if dst.Opaque() {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality})
+	} else {
+		contentType = "image/png"
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b := dst.Bounds()
+	return &port.Thumbnail{ContentType: contentType, Data: buf.Bytes(), Width: b.Dx(), Height: b.Dy()}, nil
+}
+
+// downscale shrinks img so its longest side is maxSide, averaging the source pixels behind each target pixel.
+func downscale(img image.Image, maxSide int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := maxSide, maxSide
+	if w >= h {
+		th = max(1, h*maxSide/w)
+	} else {
+		tw = max(1, w*maxSide/h)
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0 := y * h / th
+		y1 := max((y+1)*h/th, y0+1)
+		for x := 0; x < tw; x++ {
+			x0 := x * w / tw
+			x1 := max((x+1)*w/tw, x0+1)
+			// RGBA is alpha-premultiplied, so channels can be averaged independently
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					px := row[sx*4 : sx*4+4]
+					sum[0] += int(px[0])
+					sum[1] += int(px[1])
+					sum[2] += int(px[2])
+					sum[3] += int(px[3])
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			px := dst.Pix[y*dst.Stride+x*4 : y*dst.Stride+x*4+4]
+			for i := range px {
+				px[i] = uint8(sum[i] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// mediaType drops the parameters of a sniffed type, e.g. "text/plain; charset=utf-8" -> "text/plain".
+func mediaType(contentType string) string {
+	if t, _, err := mime.ParseMediaType(contentType); err == nil {
+		return t
+	}
+	return contentType
+}
+
+// byteCounter is an io.Writer counting what passes through it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
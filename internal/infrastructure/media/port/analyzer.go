@@ -0,0 +1,30 @@
+package port
+
+import (
+	"context"
+	"io"
+)
+
+// Thumbnail is a downscaled copy of an image, encoded as ContentType.
+type Thumbnail struct {
+	ContentType string
+	Data        []byte
+	Width       int
+	Height      int
+}
+
+// Analysis is what an Analyzer learned from a file's content.
+type Analysis struct {
+	ContentType string // sniffed from the content, without parameters
+	Checksum    string // hex-encoded SHA-256 of the content
+	Size        int64  // bytes read
+	Width       int    // pixels, zero unless the content is a decodable image
+	Height      int
+	Thumbnail   *Thumbnail // nil for non-images, images already small enough and images too large to decode
+}
+
+// Analyzer inspects uploaded files. Implementations must be safe for concurrent use.
+type Analyzer interface {
+	// Analyze reads r to its end.
+	Analyze(ctx context.Context, r io.Reader) (Analysis, error)
+}
@@ -0,0 +1,37 @@
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go-chatty/internal/infrastructure/push/port"
+)
+
+const defaultCollapseWindow = 10 * time.Second
+
+// NewProviderFromEnv selects the push provider named by PUSH_DRIVER: "log" (default) records notifications in memory
+// and logs them to stderr, "webhook" posts them to PUSH_WEBHOOK_URL.
+func NewProviderFromEnv() (port.Provider, error) {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("PUSH_DRIVER")))
+	switch driver {
+	case "", "log":
+		return NewMemoryProvider(os.Stderr), nil
+	case "webhook":
+		return NewWebhookProviderFromEnv()
+	default:
+		return nil, fmt.Errorf("push: unsupported PUSH_DRIVER %q", driver)
+	}
+}
+
+// CollapseWindowFromEnv reads PUSH_COLLAPSE_WINDOW as a Go duration (default 10s): how long a recipient's pushes for
+// a conversation are held back so that a burst of messages results in a single notification.
+func CollapseWindowFromEnv() time.Duration {
+	if v := strings.TrimSpace(os.Getenv("PUSH_COLLAPSE_WINDOW")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return defaultCollapseWindow
+}
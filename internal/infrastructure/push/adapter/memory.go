@@ -0,0 +1,74 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"go-chatty/internal/infrastructure/push/port"
+)
+
+// SentPush is a notification recorded by MemoryProvider with the payload a push service would have received.
+type SentPush struct {
+	Notification port.Notification
+	Payload      []byte
+}
+
+// memoryProviderCapacity bounds the notifications a MemoryProvider remembers; older ones are dropped.
+const memoryProviderCapacity = 1000
+
+// MemoryProvider keeps the latest notifications it is asked to send instead of delivering them, and optionally
+// logs them. It serves development setups and tests.
+type MemoryProvider struct {
+	mu   sync.Mutex
+	sent []SentPush
+	log  io.Writer
+}
+
+// Ensure interface compliance at compile time
+var _ port.Provider = (*MemoryProvider)(nil)
+
+// NewMemoryProvider records notifications and writes a line about each to log, which may be nil.
+func NewMemoryProvider(log io.Writer) *MemoryProvider {
+	return &MemoryProvider{log: log}
+}
+
+func (p *MemoryProvider) Send(_ context.Context, n port.Notification) error {
+	payload, err := Payload(n)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.sent) == memoryProviderCapacity {
+		p.sent = append(p.sent[:0], p.sent[1:]...)
+	}
+	p.sent = append(p.sent, SentPush{Notification: n, Payload: payload})
+	if p.log != nil {
+		_, _ = fmt.Fprintf(p.log, "push: %s ...%s: %s\n", n.Platform, tokenSuffix(n.Token), payload)
+	}
+	return nil
+}
+
+// Sent returns the notifications recorded so far, oldest first.
+func (p *MemoryProvider) Sent() []SentPush {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]SentPush(nil), p.sent...)
+}
+
+// Reset forgets the recorded notifications.
+func (p *MemoryProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = nil
+}
+
+// tokenSuffix keeps device tokens, which are credentials of sorts, out of logs.
+func tokenSuffix(token string) string {
+	if len(token) <= 6 {
+		return token
+	}
+	return token[len(token)-6:]
+}
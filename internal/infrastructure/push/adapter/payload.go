@@ -0,0 +1,77 @@
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go-chatty/internal/infrastructure/push/port"
+)
+
+// fcmMessage follows the message resource of the FCM HTTP v1 API.
+type fcmMessage struct {
+	Message struct {
+		Token        string            `json:"token"`
+		Notification fcmNotification   `json:"notification"`
+		Data         map[string]string `json:"data,omitempty"`
+		Android      *fcmAndroid       `json:"android,omitempty"`
+	} `json:"message"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmAndroid struct {
+	CollapseKey  string `json:"collapse_key,omitempty"`
+	Notification struct {
+		Tag string `json:"tag,omitempty"`
+	} `json:"notification"`
+}
+
+// apnsRequest is an APNs request: the device token, the apns-* headers and the JSON payload.
+type apnsRequest struct {
+	Token   string            `json:"token"`
+	Headers map[string]string `json:"headers"`
+	Payload map[string]any    `json:"payload"`
+}
+
+// Payload renders n the way its platform's push service expects it. APNs requests carry their headers
+// next to the payload so a gateway can forward them as is.
+func Payload(n port.Notification) ([]byte, error) {
+	switch n.Platform {
+	case port.PlatformFCM:
+		var m fcmMessage
+		m.Message.Token = n.Token
+		m.Message.Notification = fcmNotification{Title: n.Title, Body: n.Body}
+		m.Message.Data = n.Data
+		if n.CollapseKey != "" {
+			m.Message.Android = &fcmAndroid{CollapseKey: n.CollapseKey}
+			m.Message.Android.Notification.Tag = n.CollapseKey
+		}
+		return json.Marshal(m)
+	case port.PlatformAPNs:
+		aps := map[string]any{
+			"alert": map[string]string{"title": n.Title, "body": n.Body},
+			"sound": "default",
+		}
+		if n.Badge > 0 {
+			aps["badge"] = n.Badge
+		}
+		headers := map[string]string{"apns-push-type": "alert", "apns-priority": "10"}
+		if n.CollapseKey != "" {
+			aps["thread-id"] = n.CollapseKey
+			headers["apns-collapse-id"] = n.CollapseKey
+		}
+		// Custom keys sit next to aps, as APNs requires
+		payload := map[string]any{"aps": aps}
+		for k, v := range n.Data {
+			if k != "aps" {
+				payload[k] = v
+			}
+		}
+		return json.Marshal(apnsRequest{Token: n.Token, Headers: headers, Payload: payload})
+	default:
+		return nil, fmt.Errorf("push: unsupported platform %q", n.Platform)
+	}
+}
@@ -0,0 +1,70 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go-chatty/internal/infrastructure/push/port"
+)
+
+// WebhookProvider posts every notification, in its platform's shape, to a push gateway that talks to FCM and
+// APNs. The gateway answers 2xx once the push service accepted it, and 404 or 410 for tokens to forget.
+type WebhookProvider struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// Ensure interface compliance at compile time
+var _ port.Provider = (*WebhookProvider)(nil)
+
+// NewWebhookProvider posts to url, authenticating with token as a bearer credential when it is not empty.
+func NewWebhookProvider(url string, token string) (*WebhookProvider, error) {
+	if url == "" {
+		return nil, errors.New("push: webhook URL is required")
+	}
+	return &WebhookProvider{url: url, token: token, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// NewWebhookProviderFromEnv reads PUSH_WEBHOOK_URL and the optional PUSH_WEBHOOK_TOKEN.
+func NewWebhookProviderFromEnv() (*WebhookProvider, error) {
+	return NewWebhookProvider(strings.TrimSpace(os.Getenv("PUSH_WEBHOOK_URL")), strings.TrimSpace(os.Getenv("PUSH_WEBHOOK_TOKEN")))
+}
+
+func (p *WebhookProvider) Send(ctx context.Context, n port.Notification) error {
+	body, err := Payload(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Push-Platform", string(n.Platform))
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return port.ErrInvalidToken
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push: webhook answered %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+}
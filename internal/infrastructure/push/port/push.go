@@ -0,0 +1,39 @@
+package port
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidToken is returned by Provider.Send when the device token is unknown or expired;
+// callers should forget the token.
+var ErrInvalidToken = errors.New("push: device token is no longer valid")
+
+// Platform identifies the push service a device token belongs to.
+type Platform string
+
+const (
+	PlatformFCM  Platform = "fcm"  // Firebase Cloud Messaging (Android, web)
+	PlatformAPNs Platform = "apns" // Apple Push Notification service
+)
+
+// Notification is a push addressed to a single device.
+type Notification struct {
+	Token    string
+	Platform Platform
+	Title    string
+	Body     string
+	// Badge is the number shown on the app icon (APNs only); zero leaves it unchanged.
+	Badge int
+	// CollapseKey groups notifications on the device: a newer one replaces an older one with the same key.
+	CollapseKey string
+	// Data is handed to the app alongside the alert.
+	Data map[string]string
+}
+
+// Provider delivers push notifications to devices. Implementations should be concurrency-safe.
+type Provider interface {
+	// Send hands n to the push service. It returns ErrInvalidToken for tokens that will never work again;
+	// other errors are worth retrying.
+	Send(ctx context.Context, n Notification) error
+}
@@ -69,6 +69,9 @@ func (a *AsynqClient) Enqueue(ctx context.Context, t port.Task, opts ...port.Enq
 		}
 	}
 	info, err := a.client.EnqueueContext(ctx, at, asynqOpts...)
+	if errors.Is(err, asynq.ErrDuplicateTask) {
+		return "", fmt.Errorf("%w: %v", port.ErrDuplicate, err)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -2,9 +2,13 @@ package port
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrDuplicate is returned by Client.Enqueue when an identical task is still within its UniqueTTL window.
+var ErrDuplicate = errors.New("queue: duplicate task")
+
 // Task represents a background job message with a type and opaque payload bytes.
 // Type should be a stable string identifier. Payload encoding is up to callers.
 // Keep this port free from serialization concerns to avoid coupling.
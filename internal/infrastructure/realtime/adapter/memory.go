@@ -0,0 +1,73 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go-chatty/internal/infrastructure/realtime/port"
+)
+
+// MemoryBus is an in-process port.Bus. Sharing one instance between several
+// routers simulates a multi-node cluster inside a single process (tests, local dev).
+type MemoryBus struct {
+	mu       sync.RWMutex
+	handlers map[int]port.Handler
+	nextID   int
+	closed   bool
+}
+
+// NewMemoryBus constructs an empty MemoryBus.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{handlers: make(map[int]port.Handler)}
+}
+
+// Ensure interface compliance at compile time
+var _ port.Bus = (*MemoryBus)(nil)
+
+func (b *MemoryBus) Publish(ctx context.Context, env port.Envelope) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return errors.New("realtime bus: closed")
+	}
+	handlers := make([]port.Handler, 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	// Deliver synchronously; handlers only enqueue onto connection buffers.
+	for _, h := range handlers {
+		h(env)
+	}
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context, h port.Handler) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errors.New("realtime bus: closed")
+	}
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = h
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}()
+	return nil
+}
+
+func (b *MemoryBus) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.handlers = make(map[int]port.Handler)
+	b.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,97 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+
+	"go-chatty/internal/infrastructure/realtime/port"
+)
+
+const defaultRedisChannel = "chatty:realtime"
+
+// RedisBus implements port.Bus on top of Redis pub/sub.
+// Every node publishes to and subscribes on the same channel.
+type RedisBus struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisBusFromEnv constructs a RedisBus using REDIS_URL and optional config:
+// - REALTIME_CHANNEL: pub/sub channel name (default "chatty:realtime")
+func NewRedisBusFromEnv() (*RedisBus, error) {
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		return nil, errors.New("realtime bus: REDIS_URL environment variable is not set")
+	}
+	opt, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("realtime bus: parse url: %w", err)
+	}
+	c := redis.NewClient(opt)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := c.Ping(ctx).Err(); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("realtime bus: ping: %w", err)
+	}
+
+	channel := strings.TrimSpace(os.Getenv("REALTIME_CHANNEL"))
+	if channel == "" {
+		channel = defaultRedisChannel
+	}
+	return &RedisBus{client: c, channel: channel}, nil
+}
+
+// Ensure interface compliance at compile time
+var _ port.Bus = (*RedisBus)(nil)
+
+func (b *RedisBus) Publish(ctx context.Context, env port.Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("realtime bus: encode envelope: %w", err)
+	}
+	return b.client.Publish(ctx, b.channel, data).Err()
+}
+
+func (b *RedisBus) Subscribe(ctx context.Context, h port.Handler) error {
+	sub := b.client.Subscribe(ctx, b.channel)
+	// Wait for the subscription confirmation so publishes right after return are not missed
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return fmt.Errorf("realtime bus: subscribe: %w", err)
+	}
+
+	go func() {
+		defer func() { _ = sub.Close() }()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var env port.Envelope
+				if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+					// Best-effort log to stderr without introducing logging deps
+					_, _ = fmt.Fprintf(os.Stderr, "realtime bus: drop malformed envelope: %v\n", err)
+					continue
+				}
+				h(env)
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *RedisBus) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,104 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"go-chatty/internal/infrastructure/realtime/port"
+)
+
+// maxBlockLoads bounds how often LoadBlocked retries a load that raced with block changes.
+const maxBlockLoads = 3
+
+// ErrBlocksUnknown is returned by Blocked for users whose block list was not loaded on this node.
+var ErrBlocksUnknown = errors.New("realtime: block list not loaded")
+
+// LoadBlocked keeps the users blocked by userID in memory for as long as the user holds a session on this
+// node, so high-frequency ephemeral frames can be filtered without a database query. load reads the list
+// from storage; a load racing with a block change is retried, and changes made afterwards reach the list
+// through UpdateBlocked on every node. conn must already be attached.
+func (r *Router) LoadBlocked(ctx context.Context, conn *Connection, load func(ctx context.Context) ([]string, error)) error {
+	for attempt := 1; ; attempt++ {
+		gen := r.blocksGen.Load()
+		ids, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		r.blocksMu.Lock()
+		if r.blocksGen.Load() != gen && attempt < maxBlockLoads {
+			r.blocksMu.Unlock()
+			continue
+		}
+		r.mu.RLock()
+		_, attached := r.sessions[conn.ID]
+		r.mu.RUnlock()
+		if attached {
+			r.blocked[conn.UserID] = ids
+		}
+		r.blocksMu.Unlock()
+		return nil
+	}
+}
+
+// Blocked returns the users blocked by userID as loaded by LoadBlocked, or ErrBlocksUnknown. The slice is
+// shared and must not be modified.
+func (r *Router) Blocked(userID string) ([]string, error) {
+	r.blocksMu.Lock()
+	defer r.blocksMu.Unlock()
+	ids, ok := r.blocked[userID]
+	if !ok {
+		return nil, ErrBlocksUnknown
+	}
+	return ids, nil
+}
+
+// UpdateBlocked applies a stored block, or unblock when blocked is false, to the lists loaded on every node.
+// A new block also hides the ephemeral signals blockerID has active from blockedID right away.
+func (r *Router) UpdateBlocked(blockerID string, blockedID string, blocked bool) {
+	r.updateBlockedLocal(blockerID, blockedID, blocked)
+	kind := port.EnvelopeKindUnblock
+	if blocked {
+		kind = port.EnvelopeKindBlock
+	}
+	r.publish(port.Envelope{Kind: kind, Target: blockerID, UserID: blockedID})
+}
+
+func (r *Router) updateBlockedLocal(blockerID string, blockedID string, blocked bool) {
+	r.blocksMu.Lock()
+	r.blocksGen.Add(1)
+	if ids, ok := r.blocked[blockerID]; ok {
+		// Copy on write: Blocked hands the current slice out
+		next := slices.DeleteFunc(slices.Clone(ids), func(id string) bool { return id == blockedID })
+		if blocked {
+			next = append(next, blockedID)
+		}
+		r.blocked[blockerID] = next
+	}
+	r.blocksMu.Unlock()
+
+	if !blocked {
+		return
+	}
+	var stops [][]byte
+	r.signalsMu.Lock()
+	for key, s := range r.signals {
+		if key.userID == blockerID && !containsUser(s.exclude, blockedID) {
+			s.exclude = append(slices.Clone(s.exclude), blockedID)
+			stops = append(stops, s.stop)
+		}
+	}
+	r.signalsMu.Unlock()
+	for _, stop := range stops {
+		// The blocked user saw the signal start and would otherwise never see it end
+		r.NotifyUser(blockedID, stop)
+	}
+}
+
+// forgetBlocked drops the block list of a user left without sessions on this node.
+func (r *Router) forgetBlocked(userID string) {
+	r.blocksMu.Lock()
+	delete(r.blocked, userID)
+	r.blocksMu.Unlock()
+}
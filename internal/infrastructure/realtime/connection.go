@@ -3,6 +3,7 @@ package realtime
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,29 +13,73 @@ import (
 const (
 	writeWait  = 10 * time.Second
 	pingPeriod = 30 * time.Second
+	// maxHeldFrames bounds the frames held back while a session resumes, see Router.Resume.
+	maxHeldFrames = 1024
+)
+
+var (
+	errConnectionClosed = errors.New("connection closed")
+	errBufferExceeded   = errors.New("connection buffer exceeded")
 )
 
 // Connection wraps a websocket and coordinates outbound writes via a buffered channel.
 // A connection is uniquely identified per user session and is safe for concurrent use.
+// DeviceID is optional; a new session on the same device replaces the previous one.
 type Connection struct {
-	ID     string
-	UserID string
+	ID          string
+	UserID      string
+	DeviceID    string
+	ConnectedAt time.Time
 
 	ws    *websocket.Conn
 	send  chan []byte
 	once  sync.Once
 	close chan struct{}
+
+	lastActive atomic.Int64 // unix nanos of the last frame from the client
+	lastSeen   atomic.Int64 // unix nanos of the last sign of life, frames or pongs
+
+	ephemeralMu     sync.Mutex
+	ephemeralTokens float64   // budget for ephemeral events, see takeEphemeralToken
+	ephemeralAt     time.Time // last refill
+
+	holdMu  sync.Mutex
+	holding bool     // set while a resume replays; Send then appends to held
+	held    [][]byte // frames to deliver once the replay is done
 }
 
-// NewConnection constructs a Connection for the given user.
-func NewConnection(userID string, ws *websocket.Conn) *Connection {
-	return &Connection{
-		ID:     uuid.NewString(),
-		UserID: userID,
-		ws:     ws,
-		send:   make(chan []byte, 128),
-		close:  make(chan struct{}),
+// NewConnection constructs a Connection for the given user and optional device.
+func NewConnection(userID string, deviceID string, ws *websocket.Conn) *Connection {
+	c := &Connection{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		DeviceID:    deviceID,
+		ConnectedAt: time.Now().UTC(),
+		ws:          ws,
+		send:        make(chan []byte, 128),
+		close:       make(chan struct{}),
+
+		ephemeralTokens: ephemeralBurst,
 	}
+	c.markActive(c.ConnectedAt)
+	return c
+}
+
+func (c *Connection) markActive(t time.Time) {
+	c.lastActive.Store(t.UnixNano())
+	c.markSeen(t)
+}
+
+func (c *Connection) markSeen(t time.Time) {
+	c.lastSeen.Store(t.UnixNano())
+}
+
+func (c *Connection) activeAt() time.Time {
+	return time.Unix(0, c.lastActive.Load())
+}
+
+func (c *Connection) seenAt() time.Time {
+	return time.Unix(0, c.lastSeen.Load())
 }
 
 // Start launches the write loop. It must be called exactly once per connection.
@@ -45,28 +90,109 @@ func (c *Connection) Start() {
 // Send enqueues payload for delivery. If the client is slow and the buffer is full,
 // the connection is closed to keep backpressure bounded.
 func (c *Connection) Send(payload []byte) error {
+	c.holdMu.Lock()
+	if c.holding {
+		defer c.holdMu.Unlock()
+		if len(c.held) >= maxHeldFrames {
+			c.Close(websocket.CloseGoingAway, "send buffer full")
+			return errBufferExceeded
+		}
+		c.held = append(c.held, payload)
+		return nil
+	}
+	c.holdMu.Unlock()
+
+	if c.closed() {
+		return errConnectionClosed
+	}
 	select {
 	case <-c.close:
-		return errors.New("connection closed")
+		return errConnectionClosed
 	case c.send <- payload:
 		return nil
 	default:
 		c.Close(websocket.CloseGoingAway, "send buffer full")
-		return errors.New("connection buffer exceeded")
+		return errBufferExceeded
+	}
+}
+
+// sendWait enqueues payload past any hold, waiting up to writeWait for room in the buffer
+// rather than closing the connection right away. It is meant for bulk writes such as replays.
+func (c *Connection) sendWait(payload []byte) error {
+	if c.closed() {
+		return errConnectionClosed
+	}
+	timer := time.NewTimer(writeWait)
+	defer timer.Stop()
+	select {
+	case <-c.close:
+		return errConnectionClosed
+	case c.send <- payload:
+		return nil
+	case <-timer.C:
+		c.Close(websocket.CloseGoingAway, "send buffer full")
+		return errBufferExceeded
+	}
+}
+
+// hold makes Send keep frames back until release. It reports false, changing nothing, when frames are
+// already held.
+func (c *Connection) hold() bool {
+	c.holdMu.Lock()
+	defer c.holdMu.Unlock()
+	if c.holding {
+		return false
+	}
+	c.holding = true
+	return true
+}
+
+// release delivers the held frames in order, then lets Send enqueue directly again. Frames sent
+// while release drains are appended to the backlog so ordering is kept.
+func (c *Connection) release() {
+	for {
+		c.holdMu.Lock()
+		batch := c.held
+		c.held = nil
+		if len(batch) == 0 {
+			c.holding = false
+			c.holdMu.Unlock()
+			return
+		}
+		c.holdMu.Unlock()
+
+		for _, payload := range batch {
+			if err := c.sendWait(payload); err != nil {
+				c.holdMu.Lock()
+				c.held = nil
+				c.holding = false
+				c.holdMu.Unlock()
+				return
+			}
+		}
 	}
 }
 
 // Close terminates the connection and stops the write loop.
 func (c *Connection) Close(code int, reason string) {
 	c.once.Do(func() {
+		// send stays open: a Send racing with Close must fail, not panic on a closed channel
 		close(c.close)
-		close(c.send)
 		_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
 		_ = c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
 		_ = c.ws.Close()
 	})
 }
 
+func (c *Connection) closed() bool {
+	select {
+	case <-c.close:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Connection) writeLoop() {
 	ticker := time.NewTicker(pingPeriod)
 	defer ticker.Stop()
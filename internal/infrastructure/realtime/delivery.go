@@ -0,0 +1,73 @@
+package realtime
+
+import "time"
+
+// Delivery reports that a message frame reached one of the recipient's sessions on this node,
+// or that the recipient's client acknowledged it.
+type Delivery struct {
+	ConversationID string
+	MessageID      string
+	SenderID       string
+	RecipientID    string
+	At             time.Time
+}
+
+// DeliveryRecorder consumes deliveries observed by the Router. RecordDelivery runs on the
+// fan-out path, so implementations must return quickly and never block on I/O.
+type DeliveryRecorder interface {
+	RecordDelivery(d Delivery)
+}
+
+// WithDeliveryRecorder reports every successful local delivery of a message frame sent with
+// BroadcastMessage to rec. Each node records the deliveries to its own sessions.
+func WithDeliveryRecorder(rec DeliveryRecorder) Option {
+	return func(r *Router) {
+		r.recorder = rec
+	}
+}
+
+// messageRef identifies the message carried by a room payload; the zero value means the
+// payload is not a message frame and no delivery is recorded.
+type messageRef struct {
+	conversationID string
+	messageID      string
+	senderID       string
+}
+
+// BroadcastMessage is Broadcast for message frames: in addition to fanning out payload, it
+// records a delivery for every recipient whose session accepted the frame.
+func (r *Router) BroadcastMessage(conversationID string, messageID string, senderID string, payload []byte, excludeUserIDs ...string) int {
+	ref := messageRef{conversationID: conversationID, messageID: messageID, senderID: senderID}
+	return r.broadcast(conversationID, payload, excludeUserIDs, ref)
+}
+
+// AckDelivery records a delivery confirmed by the recipient's client, e.g. for messages
+// fetched over HTTP or delivered while the node's recorder was unavailable.
+func (r *Router) AckDelivery(d Delivery) {
+	if r.recorder == nil {
+		return
+	}
+	if d.At.IsZero() {
+		d.At = time.Now().UTC()
+	}
+	r.recorder.RecordDelivery(d)
+}
+
+func (r *Router) recordDeliveries(ref messageRef, recipients []string) {
+	if r.recorder == nil || ref.messageID == "" {
+		return
+	}
+	now := time.Now().UTC()
+	for _, userID := range recipients {
+		if userID == ref.senderID {
+			continue
+		}
+		r.recorder.RecordDelivery(Delivery{
+			ConversationID: ref.conversationID,
+			MessageID:      ref.messageID,
+			SenderID:       ref.senderID,
+			RecipientID:    userID,
+			At:             now,
+		})
+	}
+}
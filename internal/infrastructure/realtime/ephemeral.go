@@ -0,0 +1,144 @@
+package realtime
+
+import (
+	"errors"
+	"time"
+)
+
+// Ephemeral events are room-scoped signals that are never persisted, such as typing indicators. They are
+// announced once when they become active and again when they stop, either explicitly, when the session leaves
+// or disconnects, or when the sender stops refreshing them. Every start or refresh consumes a token from a
+// per-session bucket.
+const (
+	ephemeralBurst  = 10  // events a session may send back to back
+	ephemeralRefill = 5.0 // tokens regained per second
+)
+
+var (
+	// ErrNotInRoom is returned when a session sends an ephemeral event to a room it has not joined.
+	ErrNotInRoom = errors.New("realtime: session has not joined the room")
+	// ErrThrottled is returned when a session exceeds its ephemeral event budget.
+	ErrThrottled = errors.New("realtime: too many ephemeral events")
+)
+
+// signalKey identifies a stateful signal: one per room, kind and user, whichever session set it.
+type signalKey struct {
+	room   string
+	kind   string
+	userID string
+}
+
+type activeSignal struct {
+	sessionID string
+	timer     *time.Timer
+	stop      []byte
+	exclude   []string
+}
+
+// StartSignal activates the signal kind of conn's user in room. startPayload is broadcast only when the
+// signal was not active yet; otherwise its expiry is pushed back by ttl. stopPayload is broadcast when the
+// signal ends. The sender's own sessions and excludeUserIDs receive neither.
+func (r *Router) StartSignal(conn *Connection, room string, kind string, ttl time.Duration, startPayload []byte, stopPayload []byte, excludeUserIDs ...string) error {
+	if err := r.checkEphemeral(conn, room); err != nil {
+		return err
+	}
+
+	key := signalKey{room: room, kind: kind, userID: conn.UserID}
+	r.signalsMu.Lock()
+	if s, ok := r.signals[key]; ok {
+		s.sessionID = conn.ID
+		s.timer.Reset(ttl)
+		r.signalsMu.Unlock()
+		return nil
+	}
+	s := &activeSignal{
+		sessionID: conn.ID,
+		stop:      stopPayload,
+		exclude:   excludingSender(excludeUserIDs, conn.UserID),
+	}
+	s.timer = time.AfterFunc(ttl, func() { r.endSignal(key, s) })
+	r.signals[key] = s
+	exclude := s.exclude // UpdateBlocked may replace it once the lock is released
+	r.signalsMu.Unlock()
+
+	r.Broadcast(room, startPayload, exclude...)
+	return nil
+}
+
+// StopSignal ends the signal kind of conn's user in room, reporting whether it was active.
+func (r *Router) StopSignal(conn *Connection, room string, kind string) bool {
+	return r.endSignal(signalKey{room: room, kind: kind, userID: conn.UserID}, nil)
+}
+
+// endSignal removes the signal under key and broadcasts its stop payload. When only is set, the signal is
+// ended only if it is still that instance, so a stale expiry cannot end a signal that was started again.
+func (r *Router) endSignal(key signalKey, only *activeSignal) bool {
+	r.signalsMu.Lock()
+	s, ok := r.signals[key]
+	if !ok || (only != nil && s != only) {
+		r.signalsMu.Unlock()
+		return false
+	}
+	delete(r.signals, key)
+	s.timer.Stop()
+	r.signalsMu.Unlock()
+
+	r.Broadcast(key.room, s.stop, s.exclude...)
+	return true
+}
+
+// endSignalsWhere ends every active signal matching match.
+func (r *Router) endSignalsWhere(match func(key signalKey, s *activeSignal) bool) {
+	r.signalsMu.Lock()
+	var keys []signalKey
+	for key, s := range r.signals {
+		if match(key, s) {
+			keys = append(keys, key)
+		}
+	}
+	r.signalsMu.Unlock()
+
+	for _, key := range keys {
+		r.endSignal(key, nil)
+	}
+}
+
+// excludingSender copies excludeUserIDs plus the sender, so callers' slices are never retained or written to.
+func excludingSender(excludeUserIDs []string, senderID string) []string {
+	out := make([]string, 0, len(excludeUserIDs)+1)
+	out = append(out, excludeUserIDs...)
+	return append(out, senderID)
+}
+
+// checkEphemeral ensures conn joined room and still has budget for another event.
+func (r *Router) checkEphemeral(conn *Connection, room string) error {
+	r.mu.RLock()
+	_, joined := r.rooms[room][conn.ID]
+	r.mu.RUnlock()
+	if !joined {
+		return ErrNotInRoom
+	}
+	if !conn.takeEphemeralToken(time.Now()) {
+		return ErrThrottled
+	}
+	return nil
+}
+
+// takeEphemeralToken refills the session's bucket for the time elapsed and takes one token if available.
+func (c *Connection) takeEphemeralToken(now time.Time) bool {
+	c.ephemeralMu.Lock()
+	defer c.ephemeralMu.Unlock()
+
+	if !c.ephemeralAt.IsZero() {
+		c.ephemeralTokens += now.Sub(c.ephemeralAt).Seconds() * ephemeralRefill
+		if c.ephemeralTokens > ephemeralBurst {
+			c.ephemeralTokens = ephemeralBurst
+		}
+	}
+	c.ephemeralAt = now
+	if c.ephemeralTokens < 1 {
+		return false
+	}
+	c.ephemeralTokens--
+	return true
+}
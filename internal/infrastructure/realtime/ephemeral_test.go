@@ -0,0 +1,118 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEphemeralTokenBucket(t *testing.T) {
+	c := &Connection{ephemeralTokens: ephemeralBurst}
+	now := time.Now()
+
+	for i := 0; i < ephemeralBurst; i++ {
+		if !c.takeEphemeralToken(now) {
+			t.Fatalf("token %d of the burst refused", i+1)
+		}
+	}
+	if c.takeEphemeralToken(now) {
+		t.Fatalf("token past the burst granted")
+	}
+	// 5 tokens per second: one is back after 200ms, not before
+	if c.takeEphemeralToken(now.Add(150 * time.Millisecond)) {
+		t.Fatalf("token granted before it was refilled")
+	}
+	if !c.takeEphemeralToken(now.Add(350 * time.Millisecond)) {
+		t.Fatalf("refilled token refused")
+	}
+
+	// A long pause refills up to the burst only
+	later := now.Add(time.Hour)
+	for i := 0; i < ephemeralBurst; i++ {
+		if !c.takeEphemeralToken(later) {
+			t.Fatalf("token %d after a pause refused", i+1)
+		}
+	}
+	if c.takeEphemeralToken(later) {
+		t.Fatalf("bucket refilled past the burst")
+	}
+}
+
+func TestStartSignalChecksRoomAndBudget(t *testing.T) {
+	r, err := NewRouter()
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	t.Cleanup(r.Close)
+	alice := newTestSession(t, "alice", "")
+	bob := newTestSession(t, "bob", "")
+	r.Attach(alice.Connection)
+	r.Attach(bob.Connection)
+	r.Join("conv", bob.Connection)
+
+	if err := r.StartSignal(alice.Connection, "conv", "typing", time.Minute, []byte("start"), []byte("stop")); !errors.Is(err, ErrNotInRoom) {
+		t.Fatalf("StartSignal outside the room: err = %v, want ErrNotInRoom", err)
+	}
+	r.Join("conv", alice.Connection)
+
+	// Refreshes spend the budget too, but only the first start is announced
+	for i := 0; i < ephemeralBurst; i++ {
+		if err := r.StartSignal(alice.Connection, "conv", "typing", time.Minute, []byte("start"), []byte("stop")); err != nil {
+			t.Fatalf("StartSignal %d: %v", i+1, err)
+		}
+	}
+	if err := r.StartSignal(alice.Connection, "conv", "typing", time.Minute, []byte("start"), []byte("stop")); !errors.Is(err, ErrThrottled) {
+		t.Fatalf("StartSignal past the budget: err = %v, want ErrThrottled", err)
+	}
+	bob.expectFrames(t, "start")
+	alice.expectNoFrame(t)
+
+	if !r.StopSignal(alice.Connection, "conv", "typing") {
+		t.Fatalf("StopSignal found no active signal")
+	}
+	bob.expectFrames(t, "stop")
+}
+
+func TestSignalsSkipBlockedUsers(t *testing.T) {
+	nodes := newTestCluster(t, 2)
+	alice := newTestSession(t, "alice", "")
+	bob := newTestSession(t, "bob", "")
+	carol := newTestSession(t, "carol", "")
+	nodes[0].Attach(alice.Connection)
+	nodes[1].Attach(bob.Connection)
+	nodes[1].Attach(carol.Connection)
+	for i, s := range []*testSession{alice, bob, carol} {
+		nodes[min(i, 1)].Join("conv", s.Connection)
+	}
+
+	err := nodes[0].LoadBlocked(context.Background(), alice.Connection, func(context.Context) ([]string, error) {
+		return []string{"carol"}, nil
+	})
+	if err != nil {
+		t.Fatalf("LoadBlocked: %v", err)
+	}
+	blocked, err := nodes[0].Blocked("alice")
+	if err != nil || len(blocked) != 1 || blocked[0] != "carol" {
+		t.Fatalf("Blocked = %v, %v, want [carol]", blocked, err)
+	}
+	if _, err := nodes[1].Blocked("alice"); !errors.Is(err, ErrBlocksUnknown) {
+		t.Fatalf("Blocked on a node without alice's sessions: err = %v, want ErrBlocksUnknown", err)
+	}
+
+	if err := nodes[0].StartSignal(alice.Connection, "conv", "typing", time.Minute, []byte("start"), []byte("stop"), blocked...); err != nil {
+		t.Fatalf("StartSignal: %v", err)
+	}
+	bob.expectFrames(t, "start")
+	carol.expectNoFrame(t)
+
+	// Blocking bob mid-signal ends it for him alone, and he does not see it stop twice
+	nodes[1].UpdateBlocked("alice", "bob", true)
+	bob.expectFrames(t, "stop")
+	if blocked, _ := nodes[0].Blocked("alice"); len(blocked) != 2 {
+		t.Fatalf("Blocked after a block on another node = %v, want carol and bob", blocked)
+	}
+	nodes[0].StopSignal(alice.Connection, "conv", "typing")
+	bob.expectNoFrame(t)
+	carol.expectNoFrame(t)
+}
@@ -0,0 +1,71 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// EnvelopeKind tells peers how to route an Envelope locally.
+type EnvelopeKind string
+
+const (
+	// EnvelopeKindRoom targets every session joined to the conversation in Target.
+	EnvelopeKindRoom EnvelopeKind = "room"
+	// EnvelopeKindUser targets every session of the user in Target.
+	EnvelopeKindUser EnvelopeKind = "user"
+	// EnvelopeKindLeave removes the sessions of UserID from the conversation in Target.
+	EnvelopeKindLeave EnvelopeKind = "leave"
+	// EnvelopeKindBlock and EnvelopeKindUnblock tell that the user in Target blocked or unblocked UserID.
+	EnvelopeKindBlock   EnvelopeKind = "block"
+	EnvelopeKindUnblock EnvelopeKind = "unblock"
+	// EnvelopeKindRevoke closes the session SessionID, or every session on DeviceID, of the user in Target.
+	EnvelopeKindRevoke EnvelopeKind = "revoke"
+	// EnvelopeKindSessionsQuery asks every node for the sessions of the user in Target.
+	EnvelopeKindSessionsQuery EnvelopeKind = "sessions_query"
+	// EnvelopeKindSessionsReply answers a query; Target is the asking node and RequestID the query.
+	EnvelopeKindSessionsReply EnvelopeKind = "sessions_reply"
+)
+
+// SessionInfo describes a websocket session of a user on some node.
+type SessionInfo struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"userId"`
+	DeviceID    string    `json:"deviceId,omitempty"`
+	NodeID      string    `json:"nodeId"`
+	ConnectedAt time.Time `json:"connectedAt"`
+}
+
+// Envelope is the unit exchanged between API nodes over the cluster bus.
+// Payload is the already-encoded websocket frame; the bus never inspects it.
+type Envelope struct {
+	NodeID         string        `json:"nodeId"`              // origin node, used to drop our own echoes
+	Kind           EnvelopeKind  `json:"kind"`                // routing strategy on the receiving node
+	Target         string        `json:"target"`              // conversationID or userID depending on Kind
+	UserID         string        `json:"userId,omitempty"`    // subject of membership envelopes
+	ExcludeUserIDs []string      `json:"exclude,omitempty"`   // users that must not receive the payload
+	MessageID      string        `json:"messageId,omitempty"` // set on message frames so receivers record deliveries
+	SenderID       string        `json:"senderId,omitempty"`  // author of MessageID; never gets a receipt for it
+	SessionID      string        `json:"sessionId,omitempty"` // session to revoke
+	DeviceID       string        `json:"deviceId,omitempty"`  // device whose sessions to revoke
+	RequestID      string        `json:"requestId,omitempty"` // correlates session queries and replies
+	Sessions       []SessionInfo `json:"sessions,omitempty"`  // sessions reported in a reply
+	Payload        []byte        `json:"payload,omitempty"`   // opaque frame bytes
+}
+
+// Handler consumes envelopes received from the bus.
+type Handler func(env Envelope)
+
+// Bus fans out realtime envelopes between API nodes so that users connected
+// to different replicas can still reach each other.
+// Implementations must be safe for concurrent use.
+type Bus interface {
+	// Publish sends env to every subscribed node, including the publisher.
+	Publish(ctx context.Context, env Envelope) error
+
+	// Subscribe registers h and starts delivering envelopes until ctx is canceled.
+	// It returns once the subscription is established.
+	Subscribe(ctx context.Context, h Handler) error
+
+	// Close releases any resources held by the bus.
+	Close() error
+}
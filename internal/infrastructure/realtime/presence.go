@@ -0,0 +1,334 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	cacheport "go-chatty/internal/infrastructure/cache/port"
+)
+
+// PresenceStatus is the coarse availability of a user across all of their sessions.
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceAway    PresenceStatus = "away"
+	PresenceOffline PresenceStatus = "offline"
+)
+
+const (
+	defaultAwayAfter      = 5 * time.Minute
+	presenceSweepInterval = 30 * time.Second
+	// presenceTTL bounds how long a status survives without a refresh, e.g. after a node crash.
+	presenceTTL          = 3 * presenceSweepInterval
+	presenceStoreTimeout = 2 * time.Second
+)
+
+// Presence is what other users may learn about a user's availability.
+// LastSeen is the last time any session of the user showed a sign of life; nil if never seen.
+type Presence struct {
+	UserID   string         `json:"userId"`
+	Status   PresenceStatus `json:"status"`
+	LastSeen *time.Time     `json:"lastSeen,omitempty"`
+}
+
+// PresenceListener is told about presence changes decided on this node. PresenceChanged runs on the
+// router's goroutines, so implementations must return quickly and never block on I/O.
+type PresenceListener interface {
+	PresenceChanged(p Presence)
+}
+
+// WithPresence tracks user presence in cache and reports changes to listener (which may be nil).
+// A user is online while one of their sessions was active within the away threshold, away while
+// connected but idle, and offline once their last session on any node is gone.
+func WithPresence(cache cacheport.Cache, listener PresenceListener) Option {
+	return func(r *Router) {
+		r.presence = &presenceStore{cache: cache}
+		r.presenceListener = listener
+	}
+}
+
+// WithAwayAfter overrides how long sessions may stay idle before their user is reported away.
+func WithAwayAfter(d time.Duration) Option {
+	return func(r *Router) {
+		if d > 0 {
+			r.awayAfter = d
+		}
+	}
+}
+
+// AwayAfterFromEnv reads PRESENCE_AWAY_AFTER as a Go duration (default 5m).
+func AwayAfterFromEnv() time.Duration {
+	if v := strings.TrimSpace(os.Getenv("PRESENCE_AWAY_AFTER")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultAwayAfter
+}
+
+// Activity records that the user behind conn did something (sent a frame). An away user comes back online right away.
+func (r *Router) Activity(conn *Connection) {
+	conn.markActive(time.Now())
+	if r.presence == nil {
+		return
+	}
+	r.presenceMu.Lock()
+	away := r.localStatus[conn.UserID] == PresenceAway
+	r.presenceMu.Unlock()
+	if away {
+		go r.refreshPresence(conn.UserID)
+	}
+}
+
+// Heartbeat records that conn is still alive (e.g. it answered a ping) without counting as user activity.
+func (r *Router) Heartbeat(conn *Connection) {
+	conn.markSeen(time.Now())
+}
+
+// LookupPresence returns the presence of each of userIDs; users never seen are offline without LastSeen.
+func (r *Router) LookupPresence(ctx context.Context, userIDs []string) (map[string]Presence, error) {
+	out := make(map[string]Presence, len(userIDs))
+	for _, id := range userIDs {
+		p := Presence{UserID: id, Status: PresenceOffline}
+		if r.presence != nil {
+			var err error
+			if p, err = r.presence.get(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+		out[id] = p
+	}
+	return out, nil
+}
+
+// startPresence launches the sweep that refreshes the presence of local users and notices idle ones.
+func (r *Router) startPresence() {
+	r.presenceStop = make(chan struct{})
+	r.presenceDone = make(chan struct{})
+	go func() {
+		defer close(r.presenceDone)
+		ticker := time.NewTicker(presenceSweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.presenceStop:
+				return
+			case <-ticker.C:
+				r.mu.RLock()
+				users := make([]string, 0, len(r.userSessions))
+				for userID := range r.userSessions {
+					users = append(users, userID)
+				}
+				r.mu.RUnlock()
+				for _, userID := range users {
+					r.refreshPresence(userID)
+				}
+			}
+		}
+	}()
+}
+
+func (r *Router) stopPresence() {
+	if r.presenceStop == nil {
+		return
+	}
+	close(r.presenceStop)
+	<-r.presenceDone
+}
+
+// refreshPresence stores the status derived from the user's sessions on this node. Users without local
+// sessions are left alone: going offline needs the cluster-wide check in checkOffline.
+func (r *Router) refreshPresence(userID string) {
+	unlock := r.lockPresence(userID)
+	defer unlock()
+
+	status, lastSeen, ok := r.localPresence(userID)
+	if !ok {
+		return
+	}
+	r.setPresence(userID, status, lastSeen)
+}
+
+// checkOffline reports userID offline unless they still have a session on some node. The cluster-wide
+// lookup takes a while, so a session attached here meanwhile is checked for again under the user's
+// presence lock: either it is seen and the user stays online, or its refresh waits and follows the offline.
+func (r *Router) checkOffline(userID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceStoreTimeout)
+	defer cancel()
+	if len(r.Sessions(ctx, userID)) > 0 {
+		return
+	}
+
+	unlock := r.lockPresence(userID)
+	defer unlock()
+	r.mu.RLock()
+	_, reconnected := r.userSessions[userID]
+	r.mu.RUnlock()
+	if reconnected {
+		return
+	}
+	r.setPresence(userID, PresenceOffline, time.Now().UTC())
+}
+
+// lockPresence serialises the presence writes of userID on this node and returns the matching unlock.
+func (r *Router) lockPresence(userID string) func() {
+	r.presenceMu.Lock()
+	l := r.presenceLocks[userID]
+	if l == nil {
+		l = &presenceLock{}
+		r.presenceLocks[userID] = l
+	}
+	l.refs++
+	r.presenceMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		r.presenceMu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(r.presenceLocks, userID)
+		}
+		r.presenceMu.Unlock()
+	}
+}
+
+// presenceLock is a per-user mutex, dropped once no writer holds or waits for it.
+type presenceLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// localPresence derives a status from the user's sessions on this node; ok is false without any.
+func (r *Router) localPresence(userID string) (PresenceStatus, time.Time, bool) {
+	var lastActive, lastSeen time.Time
+	r.mu.RLock()
+	for sessionID := range r.userSessions[userID] {
+		conn := r.sessions[sessionID]
+		if conn == nil {
+			continue
+		}
+		if t := conn.activeAt(); t.After(lastActive) {
+			lastActive = t
+		}
+		if t := conn.seenAt(); t.After(lastSeen) {
+			lastSeen = t
+		}
+	}
+	r.mu.RUnlock()
+
+	if lastSeen.IsZero() {
+		return "", time.Time{}, false
+	}
+	if time.Since(lastActive) < r.awayAfter {
+		return PresenceOnline, lastSeen, true
+	}
+	return PresenceAway, lastSeen, true
+}
+
+// setPresence stores the user's status and last-seen time and reports status changes. An idle node does
+// not turn a user away while another node still reports them online.
+func (r *Router) setPresence(userID string, status PresenceStatus, lastSeen time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceStoreTimeout)
+	defer cancel()
+
+	prev, err := r.presence.entry(ctx, userID)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "realtime: read presence of %s: %v\n", userID, err)
+		return
+	}
+	if status == PresenceAway && prev.Status == PresenceOnline && prev.NodeID != r.nodeID {
+		// That node refreshes the entry for as long as the user stays active there
+		return
+	}
+
+	if err := r.presence.set(ctx, userID, presenceEntry{Status: status, NodeID: r.nodeID}, lastSeen); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "realtime: store presence of %s: %v\n", userID, err)
+		return
+	}
+
+	r.presenceMu.Lock()
+	if status == PresenceOffline {
+		delete(r.localStatus, userID)
+	} else {
+		r.localStatus[userID] = status
+	}
+	r.presenceMu.Unlock()
+
+	if prev.Status != status && r.presenceListener != nil {
+		seen := lastSeen.UTC()
+		r.presenceListener.PresenceChanged(Presence{UserID: userID, Status: status, LastSeen: &seen})
+	}
+}
+
+// presenceStore keeps presence in the cache: a short-lived status entry refreshed by the sweep, and
+// a last-seen timestamp that never expires.
+type presenceStore struct {
+	cache cacheport.Cache
+}
+
+// presenceEntry is the cached status of a user and the node that wrote it.
+type presenceEntry struct {
+	Status PresenceStatus `json:"status"`
+	NodeID string         `json:"nodeId"`
+}
+
+func presenceStatusKey(userID string) string   { return "presence:status:" + userID }
+func presenceLastSeenKey(userID string) string { return "presence:last_seen:" + userID }
+
+// entry returns the cached status of userID; a missing or expired entry means offline.
+func (s *presenceStore) entry(ctx context.Context, userID string) (presenceEntry, error) {
+	raw, err := s.cache.Get(ctx, presenceStatusKey(userID))
+	if errors.Is(err, cacheport.ErrMiss) {
+		return presenceEntry{Status: PresenceOffline}, nil
+	}
+	if err != nil {
+		return presenceEntry{}, err
+	}
+	var e presenceEntry
+	if err := json.Unmarshal([]byte(raw), &e); err != nil {
+		return presenceEntry{Status: PresenceOffline}, nil
+	}
+	return e, nil
+}
+
+func (s *presenceStore) set(ctx context.Context, userID string, e presenceEntry, lastSeen time.Time) error {
+	if err := s.cache.Set(ctx, presenceLastSeenKey(userID), lastSeen.UTC().Format(time.RFC3339Nano), 0); err != nil {
+		return err
+	}
+	if e.Status == PresenceOffline {
+		_, err := s.cache.Del(ctx, presenceStatusKey(userID))
+		return err
+	}
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(ctx, presenceStatusKey(userID), string(raw), presenceTTL)
+}
+
+func (s *presenceStore) get(ctx context.Context, userID string) (Presence, error) {
+	e, err := s.entry(ctx, userID)
+	if err != nil {
+		return Presence{}, err
+	}
+	p := Presence{UserID: userID, Status: e.Status}
+
+	raw, err := s.cache.Get(ctx, presenceLastSeenKey(userID))
+	if errors.Is(err, cacheport.ErrMiss) {
+		return p, nil
+	}
+	if err != nil {
+		return Presence{}, err
+	}
+	if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		p.LastSeen = &t
+	}
+	return p, nil
+}
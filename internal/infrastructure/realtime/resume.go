@@ -0,0 +1,25 @@
+package realtime
+
+import "errors"
+
+// ErrResumeInProgress is returned by Resume while an earlier resume of the same session is replaying.
+var ErrResumeInProgress = errors.New("realtime: session is already resuming")
+
+// Resume puts a reconnecting session back into rooms and lets replay catch it up before live traffic flows
+// again. Frames sent to conn from the moment it rejoins are held back, and delivered in order once replay
+// returns; past maxHeldFrames the session is closed as too slow. Because the rooms are joined before replay
+// runs, anything replay does not cover reaches the session live; the same change may arrive both ways, which
+// clients recognise by its sequence number. replay must write through send, which waits for room in the
+// connection's buffer instead of failing fast. A session resumes once at a time.
+func (r *Router) Resume(conn *Connection, rooms []string, replay func(send func(payload []byte) error)) error {
+	if !conn.hold() {
+		return ErrResumeInProgress
+	}
+	defer conn.release()
+
+	for _, room := range rooms {
+		r.Join(room, conn)
+	}
+	replay(conn.sendWait)
+	return nil
+}
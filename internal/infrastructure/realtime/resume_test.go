@@ -0,0 +1,63 @@
+package realtime
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestResumeDeliversHeldFramesAfterReplay(t *testing.T) {
+	nodes := newTestCluster(t, 2)
+	s := newTestSession(t, "alice", "")
+	nodes[0].Attach(s.Connection)
+
+	err := nodes[0].Resume(s.Connection, []string{"a", "b"}, func(send func([]byte) error) {
+		_ = send([]byte("replay 1"))
+		// Live frames of either node reach the rejoined rooms, but wait for the replay
+		nodes[0].Broadcast("a", []byte("live 1"))
+		nodes[1].Broadcast("b", []byte("live 2"))
+		_ = s.Send([]byte("live 3"))
+		if err := nodes[0].Resume(s.Connection, nil, func(func([]byte) error) {}); !errors.Is(err, ErrResumeInProgress) {
+			t.Errorf("nested Resume: err = %v, want ErrResumeInProgress", err)
+		}
+		_ = send([]byte("replay 2"))
+	})
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	s.expectFrames(t, "replay 1", "replay 2", "live 1", "live 2", "live 3")
+
+	// Once released, frames flow straight through again
+	nodes[1].Broadcast("a", []byte("live 4"))
+	s.expectFrames(t, "live 4")
+	if err := nodes[0].Resume(s.Connection, nil, func(func([]byte) error) {}); err != nil {
+		t.Fatalf("Resume after the first finished: %v", err)
+	}
+}
+
+func TestResumeClosesSessionHoldingTooManyFrames(t *testing.T) {
+	r, err := NewRouter()
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	t.Cleanup(r.Close)
+	s := newTestSession(t, "alice", "")
+	r.Attach(s.Connection)
+
+	var overflow error
+	_ = r.Resume(s.Connection, []string{"a"}, func(send func([]byte) error) {
+		for i := 0; i < maxHeldFrames; i++ {
+			if err := s.Send([]byte(fmt.Sprint(i))); err != nil {
+				t.Errorf("Send %d: %v", i, err)
+				return
+			}
+		}
+		overflow = s.Send([]byte("one too many"))
+	})
+	if !errors.Is(overflow, errBufferExceeded) {
+		t.Fatalf("Send past maxHeldFrames: err = %v, want errBufferExceeded", overflow)
+	}
+	s.expectClosed(t, websocket.CloseGoingAway)
+}
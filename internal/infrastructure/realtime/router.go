@@ -1,60 +1,191 @@
 package realtime
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-chatty/internal/infrastructure/realtime/port"
+
+	"github.com/google/uuid"
 )
 
+const publishTimeout = 2 * time.Second
+
 // Router coordinates websocket sessions and logical rooms (conversations).
-// It keeps one active Connection per user while allowing efficient fan-out
-// to all members subscribed to a conversation.
+// A user may hold several sessions at once (one per device), and every fan-out reaches
+// all of them. When a cluster bus is configured, broadcasts are also relayed to peer
+// nodes so members connected elsewhere receive them.
 type Router struct {
 	mu           sync.RWMutex
 	sessions     map[string]*Connection            // sessionID -> connection
-	userSessions map[string]string                 // userID -> sessionID
+	userSessions map[string]map[string]struct{}    // userID -> set of sessionIDs
 	rooms        map[string]map[string]*Connection // conversationID -> sessionID -> connection
 	sessionRooms map[string]map[string]struct{}    // sessionID -> set of conversationIDs
+	maxSessions  int                               // per user and node; <= 0 means unlimited
+
+	nodeID    string
+	bus       port.Bus
+	busCancel context.CancelFunc
+	recorder  DeliveryRecorder
+
+	queriesMu sync.Mutex
+	queries   map[string]*sessionQuery // requestID -> pending cluster-wide session listing
+
+	signalsMu sync.Mutex
+	signals   map[signalKey]*activeSignal // active ephemeral signals started on this node
+
+	blocksMu  sync.Mutex
+	blocked   map[string][]string // userID -> users they blocked, for users with sessions on this node
+	blocksGen atomic.Uint64       // bumped by every block change, see LoadBlocked
+
+	presence         *presenceStore // nil unless WithPresence is used
+	presenceListener PresenceListener
+	awayAfter        time.Duration
+	presenceMu       sync.Mutex
+	localStatus      map[string]PresenceStatus // userID -> last status this node stored
+	presenceLocks    map[string]*presenceLock  // userID -> lock serialising the user's presence writes
+	presenceStop     chan struct{}
+	presenceDone     chan struct{}
 }
 
-// NewRouter constructs an initialized Router.
-func NewRouter() *Router {
-	return &Router{
-		sessions:     make(map[string]*Connection),
-		userSessions: make(map[string]string),
-		rooms:        make(map[string]map[string]*Connection),
-		sessionRooms: make(map[string]map[string]struct{}),
+// Option customizes a Router at construction time.
+type Option func(*Router)
+
+// WithBus relays broadcasts and user notifications to peer nodes through bus.
+func WithBus(bus port.Bus) Option {
+	return func(r *Router) {
+		r.bus = bus
 	}
 }
 
-// Attach registers a connection for the given user. If a previous session exists,
-// it is removed and closed after the swap to enforce one active socket per user.
+// WithNodeID overrides the random node identifier used to drop our own bus echoes.
+func WithNodeID(nodeID string) Option {
+	return func(r *Router) {
+		if nodeID != "" {
+			r.nodeID = nodeID
+		}
+	}
+}
+
+// NewRouter constructs an initialized Router. If a bus is configured, the router
+// subscribes to it right away and returns an error if the subscription fails.
+func NewRouter(opts ...Option) (*Router, error) {
+	r := &Router{
+		sessions:      make(map[string]*Connection),
+		userSessions:  make(map[string]map[string]struct{}),
+		rooms:         make(map[string]map[string]*Connection),
+		sessionRooms:  make(map[string]map[string]struct{}),
+		maxSessions:   defaultMaxSessionsPerUser,
+		nodeID:        uuid.NewString(),
+		queries:       make(map[string]*sessionQuery),
+		signals:       make(map[signalKey]*activeSignal),
+		blocked:       make(map[string][]string),
+		awayAfter:     defaultAwayAfter,
+		localStatus:   make(map[string]PresenceStatus),
+		presenceLocks: make(map[string]*presenceLock),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+
+	if r.bus != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		if err := r.bus.Subscribe(ctx, r.handleEnvelope); err != nil {
+			cancel()
+			return nil, fmt.Errorf("realtime: subscribe to bus: %w", err)
+		}
+		r.busCancel = cancel
+	}
+	if r.presence != nil {
+		r.startPresence()
+	}
+	return r, nil
+}
+
+// NodeID returns the identifier this router stamps on outgoing bus envelopes.
+func (r *Router) NodeID() string {
+	return r.nodeID
+}
+
+// Attach registers a connection for its user. A previous session on the same device is
+// replaced and closed with 4001, on this node and on peers. When the user already holds the
+// maximum number of sessions on this node, the oldest one is closed with 4002.
 func (r *Router) Attach(conn *Connection) {
-	var previous *Connection
+	var replaced, evicted []*Connection
 
 	r.mu.Lock()
-	if existingID, ok := r.userSessions[conn.UserID]; ok {
-		if existing := r.sessions[existingID]; existing != nil {
-			previous = existing
-			r.detachLocked(existingID)
+	if conn.DeviceID != "" {
+		for sessionID := range r.userSessions[conn.UserID] {
+			if existing := r.sessions[sessionID]; existing != nil && existing.DeviceID == conn.DeviceID {
+				replaced = append(replaced, existing)
+				r.detachLocked(sessionID)
+			}
+		}
+	}
+	if r.maxSessions > 0 {
+		for len(r.userSessions[conn.UserID]) >= r.maxSessions {
+			oldest := r.oldestSessionLocked(conn.UserID)
+			if oldest == nil {
+				break
+			}
+			evicted = append(evicted, oldest)
+			r.detachLocked(oldest.ID)
 		}
 	}
 
 	r.sessions[conn.ID] = conn
-	r.userSessions[conn.UserID] = conn.ID
+	set := r.userSessions[conn.UserID]
+	if set == nil {
+		set = make(map[string]struct{})
+		r.userSessions[conn.UserID] = set
+	}
+	set[conn.ID] = struct{}{}
 	r.sessionRooms[conn.ID] = make(map[string]struct{})
 	r.mu.Unlock()
 
 	conn.Start()
+	if r.presence != nil {
+		go r.refreshPresence(conn.UserID)
+	}
 
-	if previous != nil {
-		previous.Close(4001, "session replaced")
+	for _, c := range replaced {
+		c.Close(4001, "session replaced")
+	}
+	for _, c := range evicted {
+		c.Close(4002, "session limit reached")
+	}
+	if conn.DeviceID != "" {
+		// The same device may still hold a session on another node
+		r.publish(port.Envelope{
+			Kind:     port.EnvelopeKindRevoke,
+			Target:   conn.UserID,
+			DeviceID: conn.DeviceID,
+		})
 	}
 }
 
-// Detach removes a connection if it is still tracked.
+// Detach removes a connection if it is still tracked and ends the signals it set. When it was the
+// user's last session on this node, the user goes offline unless other nodes still hold a session.
 func (r *Router) Detach(conn *Connection) {
 	r.mu.Lock()
 	r.detachLocked(conn.ID)
+	_, stillLocal := r.userSessions[conn.UserID]
 	r.mu.Unlock()
+
+	if !stillLocal {
+		r.forgetBlocked(conn.UserID)
+		if r.presence != nil {
+			go r.checkOffline(conn.UserID)
+		}
+	}
+
+	r.endSignalsWhere(func(_ signalKey, s *activeSignal) bool { return s.sessionID == conn.ID })
 }
 
 // Join adds the connection to the conversation room.
@@ -81,16 +212,138 @@ func (r *Router) Join(conversationID string, conn *Connection) {
 	r.mu.Unlock()
 }
 
-// Leave removes the connection from the conversation room.
+// Leave removes the connection from the conversation room and ends the signals it set there.
 func (r *Router) Leave(conversationID string, conn *Connection) {
 	r.mu.Lock()
 	r.leaveLocked(conversationID, conn.ID)
 	r.mu.Unlock()
+
+	r.endSignalsWhere(func(key signalKey, s *activeSignal) bool {
+		return key.room == conversationID && s.sessionID == conn.ID
+	})
+}
+
+// RemoveUser drops every session of userID from the conversation room and its thread rooms,
+// on this node and on peer nodes. Used when a member is removed from or leaves a conversation.
+func (r *Router) RemoveUser(conversationID string, userID string) {
+	r.removeUserLocal(conversationID, userID)
+	r.publish(port.Envelope{
+		Kind:   port.EnvelopeKindLeave,
+		Target: conversationID,
+		UserID: userID,
+	})
+}
+
+// Broadcast writes payload to all members in the conversation, on this node and,
+// through the bus, on peer nodes. Users in excludeUserIDs (empty entries are ignored) do not receive it.
+// The returned count only covers local deliveries.
+func (r *Router) Broadcast(conversationID string, payload []byte, excludeUserIDs ...string) int {
+	return r.broadcast(conversationID, payload, excludeUserIDs, messageRef{})
+}
+
+func (r *Router) broadcast(conversationID string, payload []byte, excludeUserIDs []string, ref messageRef) int {
+	excluded := make([]string, 0, len(excludeUserIDs))
+	for _, id := range excludeUserIDs {
+		if id != "" {
+			excluded = append(excluded, id)
+		}
+	}
+	delivered := r.deliverRoom(conversationID, payload, excluded, ref)
+	r.publish(port.Envelope{
+		Kind:           port.EnvelopeKindRoom,
+		Target:         conversationID,
+		ExcludeUserIDs: excluded,
+		MessageID:      ref.messageID,
+		SenderID:       ref.senderID,
+		Payload:        payload,
+	})
+	return delivered
 }
 
-// Broadcast writes payload to all members in the conversation.
-// excludeUserID, when non-empty, prevents delivering to that user.
-func (r *Router) Broadcast(conversationID string, payload []byte, excludeUserID string) int {
+// NotifyUser delivers payload to the sessions of the given user on every node.
+// It reports whether the payload was delivered locally or handed off to the bus.
+func (r *Router) NotifyUser(userID string, payload []byte) bool {
+	delivered := r.deliverUser(userID, payload)
+	published := r.publish(port.Envelope{
+		Kind:    port.EnvelopeKindUser,
+		Target:  userID,
+		Payload: payload,
+	})
+	return delivered || published
+}
+
+// Close terminates all tracked connections and clears router state.
+func (r *Router) Close() {
+	r.stopPresence()
+	if r.busCancel != nil {
+		r.busCancel()
+	}
+
+	r.mu.Lock()
+	sessions := make([]*Connection, 0, len(r.sessions))
+	for _, conn := range r.sessions {
+		sessions = append(sessions, conn)
+	}
+	r.sessions = make(map[string]*Connection)
+	r.userSessions = make(map[string]map[string]struct{})
+	r.rooms = make(map[string]map[string]*Connection)
+	r.sessionRooms = make(map[string]map[string]struct{})
+	r.mu.Unlock()
+
+	r.signalsMu.Lock()
+	for key, s := range r.signals {
+		s.timer.Stop()
+		delete(r.signals, key)
+	}
+	r.signalsMu.Unlock()
+
+	for _, conn := range sessions {
+		conn.Close(1001, "router shutdown")
+	}
+}
+
+// handleEnvelope delivers envelopes published by peer nodes to local sessions.
+func (r *Router) handleEnvelope(env port.Envelope) {
+	if env.NodeID == r.nodeID {
+		return
+	}
+	switch env.Kind {
+	case port.EnvelopeKindRoom:
+		ref := messageRef{conversationID: env.Target, messageID: env.MessageID, senderID: env.SenderID}
+		r.deliverRoom(env.Target, env.Payload, env.ExcludeUserIDs, ref)
+	case port.EnvelopeKindUser:
+		r.deliverUser(env.Target, env.Payload)
+	case port.EnvelopeKindLeave:
+		r.removeUserLocal(env.Target, env.UserID)
+	case port.EnvelopeKindBlock, port.EnvelopeKindUnblock:
+		r.updateBlockedLocal(env.Target, env.UserID, env.Kind == port.EnvelopeKindBlock)
+	case port.EnvelopeKindRevoke:
+		r.revokeLocal(env.Target, env.SessionID, env.DeviceID)
+	case port.EnvelopeKindSessionsQuery:
+		r.answerSessionsQuery(env)
+	case port.EnvelopeKindSessionsReply:
+		r.collectSessionsReply(env)
+	}
+}
+
+// publish relays env to peer nodes. It reports whether the bus accepted the envelope.
+func (r *Router) publish(env port.Envelope) bool {
+	if r.bus == nil {
+		return false
+	}
+	env.NodeID = r.nodeID
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if err := r.bus.Publish(ctx, env); err != nil {
+		// Best-effort log to stderr without introducing logging deps
+		_, _ = fmt.Fprintf(os.Stderr, "realtime: publish %s envelope: %v\n", env.Kind, err)
+		return false
+	}
+	return true
+}
+
+func (r *Router) deliverRoom(conversationID string, payload []byte, excludeUserIDs []string, ref messageRef) int {
 	r.mu.RLock()
 	room := r.rooms[conversationID]
 	if len(room) == 0 {
@@ -99,50 +352,57 @@ func (r *Router) Broadcast(conversationID string, payload []byte, excludeUserID
 	}
 
 	delivered := 0
+	var recipients []string
 	for _, conn := range room {
-		if excludeUserID != "" && conn.UserID == excludeUserID {
+		if containsUser(excludeUserIDs, conn.UserID) {
 			continue
 		}
 		if err := conn.Send(payload); err == nil {
 			delivered++
+			if !containsUser(recipients, conn.UserID) {
+				recipients = append(recipients, conn.UserID)
+			}
 		}
 	}
 	r.mu.RUnlock()
+
+	r.recordDeliveries(ref, recipients)
 	return delivered
 }
 
-// NotifyUser delivers payload to the current connection of the given user.
-func (r *Router) NotifyUser(userID string, payload []byte) bool {
-	r.mu.RLock()
-	sessionID, ok := r.userSessions[userID]
-	if !ok {
-		r.mu.RUnlock()
-		return false
-	}
-	conn := r.sessions[sessionID]
-	r.mu.RUnlock()
-	if conn == nil {
-		return false
+func (r *Router) removeUserLocal(conversationID string, userID string) {
+	r.mu.Lock()
+	for sessionID := range r.userSessions[userID] {
+		for room := range r.sessionRooms[sessionID] {
+			if inConversation(room, conversationID) {
+				r.leaveLocked(room, sessionID)
+			}
+		}
 	}
-	return conn.Send(payload) == nil
+	r.mu.Unlock()
+
+	r.endSignalsWhere(func(key signalKey, _ *activeSignal) bool {
+		return key.userID == userID && inConversation(key.room, conversationID)
+	})
 }
 
-// Close terminates all tracked connections and clears router state.
-func (r *Router) Close() {
-	r.mu.Lock()
-	sessions := make([]*Connection, 0, len(r.sessions))
-	for _, conn := range r.sessions {
-		sessions = append(sessions, conn)
+func (r *Router) deliverUser(userID string, payload []byte) bool {
+	r.mu.RLock()
+	conns := make([]*Connection, 0, len(r.userSessions[userID]))
+	for sessionID := range r.userSessions[userID] {
+		if conn := r.sessions[sessionID]; conn != nil {
+			conns = append(conns, conn)
+		}
 	}
-	r.sessions = make(map[string]*Connection)
-	r.userSessions = make(map[string]string)
-	r.rooms = make(map[string]map[string]*Connection)
-	r.sessionRooms = make(map[string]map[string]struct{})
-	r.mu.Unlock()
+	r.mu.RUnlock()
 
-	for _, conn := range sessions {
-		conn.Close(1001, "router shutdown")
+	delivered := false
+	for _, conn := range conns {
+		if conn.Send(payload) == nil {
+			delivered = true
+		}
 	}
+	return delivered
 }
 
 func (r *Router) detachLocked(sessionID string) {
@@ -152,8 +412,11 @@ func (r *Router) detachLocked(sessionID string) {
 	}
 	delete(r.sessions, sessionID)
 
-	if current, ok := r.userSessions[conn.UserID]; ok && current == sessionID {
-		delete(r.userSessions, conn.UserID)
+	if set, ok := r.userSessions[conn.UserID]; ok {
+		delete(set, sessionID)
+		if len(set) == 0 {
+			delete(r.userSessions, conn.UserID)
+		}
 	}
 
 	for roomID := range r.sessionRooms[sessionID] {
@@ -181,3 +444,12 @@ func (r *Router) leaveLocked(conversationID string, sessionID string) {
 		}
 	}
 }
+
+func containsUser(userIDs []string, userID string) bool {
+	for _, id := range userIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package realtime
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-chatty/internal/infrastructure/realtime/adapter"
+
+	"github.com/gorilla/websocket"
+)
+
+const testWait = 2 * time.Second
+
+// testSession is a Connection whose websocket is served in process. The client end collects the frames
+// the session is sent and the code it is closed with.
+type testSession struct {
+	*Connection
+	frames chan string
+	closed chan int
+}
+
+func newTestSession(t *testing.T, userID string, deviceID string) *testSession {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	serverWS := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ws, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		serverWS <- ws
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	s := &testSession{
+		Connection: NewConnection(userID, deviceID, <-serverWS),
+		frames:     make(chan string, 64),
+		closed:     make(chan int, 1),
+	}
+	go func() {
+		for {
+			_, data, err := client.ReadMessage()
+			if err != nil {
+				var ce *websocket.CloseError
+				if errors.As(err, &ce) {
+					s.closed <- ce.Code
+				}
+				close(s.frames)
+				return
+			}
+			s.frames <- string(data)
+		}
+	}()
+	return s
+}
+
+// expectFrames fails unless the session receives exactly want, in order, next.
+func (s *testSession) expectFrames(t *testing.T, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		select {
+		case got, ok := <-s.frames:
+			if !ok {
+				t.Fatalf("session %s closed while waiting for %q", s.UserID, w)
+			}
+			if got != w {
+				t.Fatalf("session %s got %q, want %q", s.UserID, got, w)
+			}
+		case <-time.After(testWait):
+			t.Fatalf("session %s did not receive %q", s.UserID, w)
+		}
+	}
+}
+
+// expectNoFrame fails if the session receives a frame within a short while.
+func (s *testSession) expectNoFrame(t *testing.T) {
+	t.Helper()
+	select {
+	case got, ok := <-s.frames:
+		if ok {
+			t.Fatalf("session %s got unexpected %q", s.UserID, got)
+		}
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// expectClosed fails unless the session is closed with code.
+func (s *testSession) expectClosed(t *testing.T, code int) {
+	t.Helper()
+	select {
+	case got := <-s.closed:
+		if got != code {
+			t.Fatalf("session %s closed with %d, want %d", s.UserID, got, code)
+		}
+	case <-time.After(testWait):
+		t.Fatalf("session %s was not closed with %d", s.UserID, code)
+	}
+}
+
+// newTestCluster returns n routers sharing one in-memory bus, as API nodes share Redis.
+func newTestCluster(t *testing.T, n int, opts ...Option) []*Router {
+	t.Helper()
+	bus := adapter.NewMemoryBus()
+	routers := make([]*Router, n)
+	for i := range routers {
+		r, err := NewRouter(append([]Option{WithBus(bus)}, opts...)...)
+		if err != nil {
+			t.Fatalf("NewRouter: %v", err)
+		}
+		t.Cleanup(r.Close)
+		routers[i] = r
+	}
+	return routers
+}
+
+func TestRouterBroadcastReachesRoomMembersOnEveryNode(t *testing.T) {
+	nodes := newTestCluster(t, 2)
+	alice := newTestSession(t, "alice", "")
+	bob := newTestSession(t, "bob", "")
+	carol := newTestSession(t, "carol", "")
+	dave := newTestSession(t, "dave", "")
+	nodes[0].Attach(alice.Connection)
+	nodes[1].Attach(bob.Connection)
+	nodes[1].Attach(carol.Connection)
+	nodes[1].Attach(dave.Connection)
+	nodes[0].Join("conv", alice.Connection)
+	nodes[1].Join("conv", bob.Connection)
+	nodes[1].Join("conv", carol.Connection)
+
+	if n := nodes[0].Broadcast("conv", []byte("hello"), "carol"); n != 1 {
+		t.Fatalf("Broadcast delivered %d frames locally, want 1", n)
+	}
+	alice.expectFrames(t, "hello")
+	bob.expectFrames(t, "hello")
+	carol.expectNoFrame(t) // excluded
+	dave.expectNoFrame(t)  // not in the room
+
+	// A member removed on one node leaves the room on every node
+	nodes[0].RemoveUser("conv", "bob")
+	nodes[0].Broadcast("conv", []byte("after"))
+	alice.expectFrames(t, "after")
+	carol.expectFrames(t, "after")
+	bob.expectNoFrame(t)
+}
+
+func TestRouterNotifyUserReachesEverySession(t *testing.T) {
+	nodes := newTestCluster(t, 2)
+	phone := newTestSession(t, "bob", "phone")
+	laptop := newTestSession(t, "bob", "laptop")
+	other := newTestSession(t, "carol", "")
+	nodes[0].Attach(phone.Connection)
+	nodes[1].Attach(laptop.Connection)
+	nodes[1].Attach(other.Connection)
+
+	if !nodes[0].NotifyUser("bob", []byte("ping")) {
+		t.Fatalf("NotifyUser reported no delivery")
+	}
+	phone.expectFrames(t, "ping")
+	laptop.expectFrames(t, "ping")
+	other.expectNoFrame(t)
+
+	// Users without a session anywhere are handed to the bus all the same
+	if !nodes[0].NotifyUser("nobody", []byte("ping")) {
+		t.Fatalf("NotifyUser reported no hand-off for a user without sessions")
+	}
+}
+
+func TestRouterRevokeSessionOnAnotherNode(t *testing.T) {
+	nodes := newTestCluster(t, 2)
+	revoked := newTestSession(t, "bob", "phone")
+	kept := newTestSession(t, "bob", "laptop")
+	nodes[1].Attach(revoked.Connection)
+	nodes[1].Attach(kept.Connection)
+
+	if !nodes[0].RevokeSession("bob", revoked.ID) {
+		t.Fatalf("RevokeSession reported nothing done")
+	}
+	revoked.expectClosed(t, 4003)
+	if got := nodes[1].localSessions("bob"); len(got) != 1 || got[0].ID != kept.ID {
+		t.Fatalf("sessions left on node 1 = %+v, want only %s", got, kept.ID)
+	}
+	nodes[0].NotifyUser("bob", []byte("still here"))
+	kept.expectFrames(t, "still here")
+}
@@ -0,0 +1,186 @@
+package realtime
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-chatty/internal/infrastructure/realtime/port"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMaxSessionsPerUser = 10
+	sessionQueryWindow        = 300 * time.Millisecond
+)
+
+// WithMaxSessionsPerUser caps how many sessions a user may hold on this node; n <= 0 disables the cap.
+// The cap is enforced per node so that connecting never waits on the cluster.
+func WithMaxSessionsPerUser(n int) Option {
+	return func(r *Router) {
+		r.maxSessions = n
+	}
+}
+
+// MaxSessionsPerUserFromEnv reads REALTIME_MAX_SESSIONS_PER_USER (default 10; 0 disables the cap).
+func MaxSessionsPerUserFromEnv() int {
+	if v := strings.TrimSpace(os.Getenv("REALTIME_MAX_SESSIONS_PER_USER")); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultMaxSessionsPerUser
+}
+
+// sessionQuery gathers the replies of peer nodes to a Sessions call.
+type sessionQuery struct {
+	mu       sync.Mutex
+	sessions []port.SessionInfo
+}
+
+// Sessions lists the active sessions of userID across the cluster, oldest first.
+// Peer nodes answer through the bus; replies arriving after a short window or after ctx is done are ignored.
+func (r *Router) Sessions(ctx context.Context, userID string) []port.SessionInfo {
+	sessions := r.localSessions(userID)
+
+	if r.bus != nil {
+		requestID := uuid.NewString()
+		q := &sessionQuery{}
+		r.queriesMu.Lock()
+		r.queries[requestID] = q
+		r.queriesMu.Unlock()
+
+		if r.publish(port.Envelope{Kind: port.EnvelopeKindSessionsQuery, Target: userID, RequestID: requestID}) {
+			timer := time.NewTimer(sessionQueryWindow)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+			}
+			timer.Stop()
+		}
+
+		r.queriesMu.Lock()
+		delete(r.queries, requestID)
+		r.queriesMu.Unlock()
+
+		q.mu.Lock()
+		sessions = append(sessions, q.sessions...)
+		q.mu.Unlock()
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].ConnectedAt.Before(sessions[j].ConnectedAt)
+	})
+	return sessions
+}
+
+// RevokeSession closes the session sessionID of userID wherever it is connected.
+// It reports whether the session was closed locally or the request was handed off to the bus.
+func (r *Router) RevokeSession(userID string, sessionID string) bool {
+	if userID == "" || sessionID == "" {
+		return false
+	}
+	revoked := r.revokeLocal(userID, sessionID, "") > 0
+	published := r.publish(port.Envelope{
+		Kind:      port.EnvelopeKindRevoke,
+		Target:    userID,
+		SessionID: sessionID,
+	})
+	return revoked || published
+}
+
+func (r *Router) localSessions(userID string) []port.SessionInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]port.SessionInfo, 0, len(r.userSessions[userID]))
+	for sessionID := range r.userSessions[userID] {
+		conn := r.sessions[sessionID]
+		if conn == nil {
+			continue
+		}
+		sessions = append(sessions, port.SessionInfo{
+			ID:          conn.ID,
+			UserID:      conn.UserID,
+			DeviceID:    conn.DeviceID,
+			NodeID:      r.nodeID,
+			ConnectedAt: conn.ConnectedAt,
+		})
+	}
+	return sessions
+}
+
+// revokeLocal closes the user's sessions matching sessionID, or deviceID when sessionID is empty.
+func (r *Router) revokeLocal(userID string, sessionID string, deviceID string) int {
+	if sessionID == "" && deviceID == "" {
+		return 0
+	}
+
+	var revoked []*Connection
+	r.mu.Lock()
+	for id := range r.userSessions[userID] {
+		conn := r.sessions[id]
+		if conn == nil {
+			continue
+		}
+		if (sessionID != "" && id == sessionID) || (sessionID == "" && conn.DeviceID == deviceID) {
+			revoked = append(revoked, conn)
+			r.detachLocked(id)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, conn := range revoked {
+		if sessionID != "" {
+			conn.Close(4003, "session revoked")
+		} else {
+			conn.Close(4001, "session replaced")
+		}
+	}
+	return len(revoked)
+}
+
+func (r *Router) answerSessionsQuery(env port.Envelope) {
+	sessions := r.localSessions(env.Target)
+	if len(sessions) == 0 {
+		return
+	}
+	r.publish(port.Envelope{
+		Kind:      port.EnvelopeKindSessionsReply,
+		Target:    env.NodeID,
+		RequestID: env.RequestID,
+		Sessions:  sessions,
+	})
+}
+
+func (r *Router) collectSessionsReply(env port.Envelope) {
+	if env.Target != r.nodeID {
+		return
+	}
+	r.queriesMu.Lock()
+	q := r.queries[env.RequestID]
+	r.queriesMu.Unlock()
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	q.sessions = append(q.sessions, env.Sessions...)
+	q.mu.Unlock()
+}
+
+// oldestSessionLocked returns the user's longest-lived session on this node. Callers hold r.mu.
+func (r *Router) oldestSessionLocked(userID string) *Connection {
+	var oldest *Connection
+	for sessionID := range r.userSessions[userID] {
+		conn := r.sessions[sessionID]
+		if conn != nil && (oldest == nil || conn.ConnectedAt.Before(oldest.ConnectedAt)) {
+			oldest = conn
+		}
+	}
+	return oldest
+}
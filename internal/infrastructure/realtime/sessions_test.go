@@ -0,0 +1,90 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAttachReplacesSessionOfSameDevice(t *testing.T) {
+	nodes := newTestCluster(t, 2)
+	first := newTestSession(t, "bob", "phone")
+	laptop := newTestSession(t, "bob", "laptop")
+	nodes[0].Attach(first.Connection)
+	nodes[0].Attach(laptop.Connection)
+	nodes[0].Join("conv", first.Connection)
+
+	// The phone reconnects to the same node
+	second := newTestSession(t, "bob", "phone")
+	nodes[0].Attach(second.Connection)
+	first.expectClosed(t, 4001)
+	if n := nodes[0].Broadcast("conv", []byte("hi")); n != 0 {
+		t.Fatalf("Broadcast reached %d replaced sessions", n)
+	}
+
+	// ... and then to another node, which revokes it on the first through the bus
+	third := newTestSession(t, "bob", "phone")
+	nodes[1].Attach(third.Connection)
+	second.expectClosed(t, 4001)
+
+	sessions := nodes[0].Sessions(context.Background(), "bob")
+	if len(sessions) != 2 || sessions[0].ID != laptop.ID || sessions[1].ID != third.ID {
+		t.Fatalf("Sessions = %+v, want the laptop then the phone on node 1", sessions)
+	}
+	if sessions[1].NodeID != nodes[1].NodeID() {
+		t.Fatalf("phone session reported on node %s, want %s", sessions[1].NodeID, nodes[1].NodeID())
+	}
+	laptop.expectNoFrame(t)
+}
+
+func TestAttachEvictsOldestSessionAtLimit(t *testing.T) {
+	nodes := newTestCluster(t, 2, WithMaxSessionsPerUser(2))
+	oldest := newTestSession(t, "bob", "")
+	middle := newTestSession(t, "bob", "")
+	nodes[0].Attach(oldest.Connection)
+	time.Sleep(time.Millisecond)
+	nodes[0].Attach(middle.Connection)
+	time.Sleep(time.Millisecond)
+
+	// The cap is per node: a session elsewhere does not count
+	elsewhere := newTestSession(t, "bob", "")
+	nodes[1].Attach(elsewhere.Connection)
+	oldest.expectNoFrame(t)
+
+	newest := newTestSession(t, "bob", "")
+	nodes[0].Attach(newest.Connection)
+	oldest.expectClosed(t, 4002)
+
+	local := nodes[0].localSessions("bob")
+	if len(local) != 2 {
+		t.Fatalf("node 0 keeps %d sessions, want 2", len(local))
+	}
+	for _, s := range local {
+		if s.ID == oldest.ID {
+			t.Fatalf("evicted session %s still tracked", oldest.ID)
+		}
+	}
+	nodes[1].NotifyUser("bob", []byte("hi"))
+	middle.expectFrames(t, "hi")
+	newest.expectFrames(t, "hi")
+	elsewhere.expectFrames(t, "hi")
+}
+
+func TestRevokeEnvelopeClosesOnlyTheTargetedSession(t *testing.T) {
+	nodes := newTestCluster(t, 3)
+	target := newTestSession(t, "bob", "phone")
+	sameDeviceOtherUser := newTestSession(t, "carol", "phone")
+	nodes[2].Attach(target.Connection)
+	nodes[2].Attach(sameDeviceOtherUser.Connection)
+
+	// Unknown sessions and other users' sessions are left alone
+	nodes[0].RevokeSession("bob", "no-such-session")
+	nodes[0].RevokeSession("carol", target.ID)
+	target.expectNoFrame(t)
+
+	nodes[1].RevokeSession("bob", target.ID)
+	target.expectClosed(t, 4003)
+	if got := nodes[2].localSessions("carol"); len(got) != 1 {
+		t.Fatalf("carol's sessions = %+v, want hers kept", got)
+	}
+}
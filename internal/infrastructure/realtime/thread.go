@@ -0,0 +1,24 @@
+package realtime
+
+import "strings"
+
+const threadRoomSeparator = "/thread/"
+
+// ThreadRoom returns the room key of the thread under rootMessageID. Thread rooms are regular rooms,
+// so sessions may follow a thread with or without joining its conversation; RemoveUser on the
+// conversation also drops the user from its threads.
+func ThreadRoom(conversationID string, rootMessageID string) string {
+	return conversationID + threadRoomSeparator + rootMessageID
+}
+
+// BroadcastThreadMessage is BroadcastMessage for thread replies: payload goes to the thread's
+// room, while deliveries are recorded against the conversation.
+func (r *Router) BroadcastThreadMessage(conversationID string, rootMessageID string, messageID string, senderID string, payload []byte, excludeUserIDs ...string) int {
+	ref := messageRef{conversationID: conversationID, messageID: messageID, senderID: senderID}
+	return r.broadcast(ThreadRoom(conversationID, rootMessageID), payload, excludeUserIDs, ref)
+}
+
+// inConversation tells whether room is conversationID's room or the room of one of its threads.
+func inConversation(room string, conversationID string) bool {
+	return room == conversationID || strings.HasPrefix(room, conversationID+threadRoomSeparator)
+}
@@ -0,0 +1,57 @@
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-chatty/internal/infrastructure/storage/port"
+)
+
+const defaultMaxAttachmentSize = 25 << 20 // 25 MiB
+
+// defaultAllowedTypes covers pictures, short media and common documents.
+var defaultAllowedTypes = []string{
+	"image/jpeg", "image/png", "image/gif", "image/webp",
+	"video/mp4", "audio/mpeg", "audio/ogg", "audio/mp4",
+	"application/pdf", "text/plain", "application/zip",
+}
+
+// NewStorageFromEnv picks an attachment store according to STORAGE_DRIVER:
+// - "local" (default): see NewLocalStorageFromEnv
+// - "s3": see NewS3StorageFromEnv (AWS S3, MinIO and other S3-compatible services)
+func NewStorageFromEnv() (port.Storage, error) {
+	driver := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_DRIVER")))
+	switch driver {
+	case "", "local":
+		return NewLocalStorageFromEnv()
+	case "s3":
+		return NewS3StorageFromEnv()
+	default:
+		return nil, fmt.Errorf("storage: unsupported STORAGE_DRIVER %q", driver)
+	}
+}
+
+// PolicyFromEnv reads ATTACHMENT_MAX_BYTES (default 25 MiB) and ATTACHMENT_ALLOWED_TYPES, a comma-separated
+// list of MIME types where "image/*" allows a whole family (default: common images, audio/video, PDF, text, zip).
+func PolicyFromEnv() port.Policy {
+	p := port.Policy{MaxSize: defaultMaxAttachmentSize, AllowedTypes: defaultAllowedTypes}
+	if v := strings.TrimSpace(os.Getenv("ATTACHMENT_MAX_BYTES")); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			p.MaxSize = n
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv("ATTACHMENT_ALLOWED_TYPES")); v != "" {
+		var types []string
+		for _, t := range strings.Split(v, ",") {
+			if t = port.NormalizeContentType(t); t != "" {
+				types = append(types, t)
+			}
+		}
+		if len(types) > 0 {
+			p.AllowedTypes = types
+		}
+	}
+	return p
+}
@@ -0,0 +1,348 @@
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-chatty/internal/infrastructure/storage/port"
+)
+
+const (
+	defaultLocalDir       = "./data/attachments"
+	defaultLocalPublicURL = "http://localhost:8080/files"
+	maxHeaderBytes        = 4096 // longest metadata line an object file may start with
+)
+
+var (
+	errBadSignature = errors.New("storage: invalid or expired signature")
+	errIncomplete   = errors.New("storage: content length does not match the declared size")
+	errExists       = errors.New("storage: an object is already stored under this key")
+)
+
+// LocalStorage keeps attachments on the local filesystem and serves presigned requests itself:
+// presigned URLs point at ServeHTTP, mounted under MountPath, and carry an HMAC signature in place
+// of credentials. It suits development and single-node deployments.
+//
+// Each object is a single file: a line of JSON metadata followed by the data, so both are replaced together.
+type LocalStorage struct {
+	root      string
+	publicURL *url.URL
+	secret    []byte
+}
+
+// NewLocalStorage stores objects below root and signs URLs under publicURL, the absolute URL at which
+// the application serves this storage (e.g. "https://chat.example.com/files").
+func NewLocalStorage(root string, publicURL string, secret []byte) (*LocalStorage, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("storage: local storage needs a signing secret")
+	}
+	u, err := url.Parse(strings.TrimRight(publicURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("storage: public url %q must be absolute", publicURL)
+	}
+	if u.Path == "" {
+		return nil, fmt.Errorf("storage: public url %q needs a path to mount the files route on", publicURL)
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("storage: create %s: %w", root, err)
+	}
+	return &LocalStorage{root: root, publicURL: u, secret: secret}, nil
+}
+
+// NewLocalStorageFromEnv reads STORAGE_LOCAL_DIR (default ./data/attachments), STORAGE_PUBLIC_URL, where the
+// files route is reachable from clients (default http://localhost:8080/files), and STORAGE_SIGNING_SECRET (required).
+func NewLocalStorageFromEnv() (*LocalStorage, error) {
+	root := strings.TrimSpace(os.Getenv("STORAGE_LOCAL_DIR"))
+	if root == "" {
+		root = defaultLocalDir
+	}
+	publicURL := strings.TrimSpace(os.Getenv("STORAGE_PUBLIC_URL"))
+	if publicURL == "" {
+		publicURL = defaultLocalPublicURL
+	}
+	secret := os.Getenv("STORAGE_SIGNING_SECRET")
+	if secret == "" {
+		return nil, errors.New("storage: STORAGE_SIGNING_SECRET environment variable is not set")
+	}
+	return NewLocalStorage(root, publicURL, []byte(secret))
+}
+
+// Ensure interface compliance at compile time
+var _ port.Storage = (*LocalStorage)(nil)
+
+// MountPath is the HTTP path prefix ServeHTTP expects to be mounted on.
+func (s *LocalStorage) MountPath() string {
+	return s.publicURL.Path
+}
+
+func (s *LocalStorage) PresignPut(_ context.Context, key string, contentType string, size int64, expiry time.Duration) (port.PresignedRequest, error) {
+	if err := checkKey(key); err != nil {
+		return port.PresignedRequest{}, err
+	}
+	contentType = port.NormalizeContentType(contentType)
+	expiresAt := time.Now().Add(expiry).UTC().Truncate(time.Second)
+	q := url.Values{
+		"expires": {strconv.FormatInt(expiresAt.Unix(), 10)},
+		"type":    {contentType},
+		"size":    {strconv.FormatInt(size, 10)},
+	}
+	q.Set("signature", s.sign(http.MethodPut, key, q))
+	return port.PresignedRequest{
+		Method:    http.MethodPut,
+		URL:       s.objectURL(key, q),
+		Header:    http.Header{"Content-Type": {contentType}},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *LocalStorage) PresignGet(_ context.Context, key string, expiry time.Duration) (port.PresignedRequest, error) {
+	if err := checkKey(key); err != nil {
+		return port.PresignedRequest{}, err
+	}
+	expiresAt := time.Now().Add(expiry).UTC().Truncate(time.Second)
+	q := url.Values{"expires": {strconv.FormatInt(expiresAt.Unix(), 10)}}
+	q.Set("signature", s.sign(http.MethodGet, key, q))
+	return port.PresignedRequest{Method: http.MethodGet, URL: s.objectURL(key, q), ExpiresAt: expiresAt}, nil
+}
+
+func (s *LocalStorage) Stat(_ context.Context, key string) (port.ObjectInfo, error) {
+	if err := checkKey(key); err != nil {
+		return port.ObjectInfo{}, err
+	}
+	return s.stat(key)
+}
+
+func (s *LocalStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	f, info, offset, err := s.open(key)
+	if err != nil {
+		return nil, err
+	}
+	return readCloser{Reader: io.NewSectionReader(f, offset, info.Size), Closer: f}, nil
+}
+
+func (s *LocalStorage) Put(_ context.Context, key string, contentType string, r io.Reader, size int64) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	return s.write(key, port.NormalizeContentType(contentType), r, size, true)
+}
+
+// ServeHTTP performs presigned uploads (PUT) and downloads (GET, HEAD).
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	key := strings.TrimPrefix(req.URL.Path, s.publicURL.Path+"/")
+	if err := checkKey(key); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	q := req.URL.Query()
+
+	switch req.Method {
+	case http.MethodPut:
+		if err := s.verify(http.MethodPut, key, q); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		s.serveUpload(w, req, key, q)
+	case http.MethodGet, http.MethodHead:
+		if err := s.verify(http.MethodGet, key, q); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		s.serveDownload(w, req, key)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *LocalStorage) serveUpload(w http.ResponseWriter, req *http.Request, key string, q url.Values) {
+	size, _ := strconv.ParseInt(q.Get("size"), 10, 64)
+	if port.NormalizeContentType(req.Header.Get("Content-Type")) != q.Get("type") {
+		http.Error(w, "content type does not match the signed upload", http.StatusBadRequest)
+		return
+	}
+	if req.ContentLength != size {
+		http.Error(w, "content length does not match the signed upload", http.StatusBadRequest)
+		return
+	}
+
+	err := s.write(key, q.Get("type"), req.Body, size, false)
+	switch {
+	case errors.Is(err, errIncomplete):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errExists):
+		// As S3 answers a conditional PUT
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+	case err != nil:
+		http.Error(w, "storage unavailable", http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// write stores exactly size bytes from r at key together with their metadata. The object is written next to
+// the destination and then moved into place, so readers never see a partial object: renamed over any existing
+// one when replace is set, otherwise linked, which fails with errExists once the key is taken.
+func (s *LocalStorage) write(key string, contentType string, r io.Reader, size int64, replace bool) error {
+	dst := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+	if !replace {
+		// Spares the copy; the link below settles races
+		if _, err := os.Lstat(dst); err == nil {
+			return errExists
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	meta, _ := json.Marshal(port.ObjectInfo{Size: size, ContentType: contentType})
+	_, err = tmp.Write(append(meta, '\n'))
+	var n int64
+	if err == nil {
+		n, err = io.Copy(tmp, io.LimitReader(r, size+1))
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n != size {
+		err = errIncomplete
+	}
+	if err != nil {
+		return err
+	}
+
+	if replace {
+		return os.Rename(tmp.Name(), dst)
+	}
+	if err := os.Link(tmp.Name(), dst); errors.Is(err, fs.ErrExist) {
+		return errExists
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) serveDownload(w http.ResponseWriter, req *http.Request, key string) {
+	f, info, offset, err := s.open(key)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	fi, err := f.Stat()
+	if err != nil {
+		http.Error(w, "storage unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, req, "", fi.ModTime(), io.NewSectionReader(f, offset, info.Size))
+}
+
+func (s *LocalStorage) stat(key string) (port.ObjectInfo, error) {
+	f, info, _, err := s.open(key)
+	if err != nil {
+		return port.ObjectInfo{}, err
+	}
+	_ = f.Close()
+	return info, nil
+}
+
+// open opens the object file at key and reads its metadata; the data starts at offset. Callers must close f.
+func (s *LocalStorage) open(key string) (f *os.File, info port.ObjectInfo, offset int64, err error) {
+	f, err = os.Open(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, port.ObjectInfo{}, 0, port.ErrNotFound
+	}
+	if err != nil {
+		return nil, port.ObjectInfo{}, 0, err
+	}
+	line, err := bufio.NewReaderSize(io.LimitReader(f, maxHeaderBytes), maxHeaderBytes).ReadSlice('\n')
+	if err == nil {
+		err = json.Unmarshal(bytes.TrimSuffix(line, []byte("\n")), &info)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, port.ObjectInfo{}, 0, fmt.Errorf("storage: corrupt metadata for %s: %w", key, err)
+	}
+	return f, info, int64(len(line)), nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// sign authenticates method on key together with the presigned parameters in q.
+func (s *LocalStorage) sign(method string, key string, q url.Values) string {
+	mac := hmac.New(sha256.New, s.secret)
+	_, _ = io.WriteString(mac, strings.Join([]string{method, key, q.Get("expires"), q.Get("type"), q.Get("size")}, "\n"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStorage) verify(method string, key string, q url.Values) error {
+	expires, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return errBadSignature
+	}
+	got, err := hex.DecodeString(q.Get("signature"))
+	if err != nil {
+		return errBadSignature
+	}
+	want, _ := hex.DecodeString(s.sign(method, key, q))
+	if !hmac.Equal(got, want) {
+		return errBadSignature
+	}
+	return nil
+}
+
+func (s *LocalStorage) objectURL(key string, q url.Values) string {
+	u := *s.publicURL
+	u.Path = path.Join(u.Path, key)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+// checkKey rejects keys that could escape the storage root or collide with uploads in progress.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." || part == "." || strings.HasPrefix(part, ".upload-") {
+			return fmt.Errorf("storage: invalid key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-chatty/internal/infrastructure/storage/port"
+)
+
+// newTestLocalStorage serves a LocalStorage rooted in a temporary directory the way main mounts it.
+func newTestLocalStorage(t *testing.T) *LocalStorage {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	s, err := NewLocalStorage(t.TempDir(), srv.URL+"/files", []byte("test-secret"))
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	mux.Handle(s.MountPath()+"/", s)
+	return s
+}
+
+// doPresigned performs a presigned request with body, sending the headers it requires.
+func doPresigned(t *testing.T, pr port.PresignedRequest, body []byte) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(pr.Method, pr.URL, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	for name, values := range pr.Header {
+		if strings.EqualFold(name, "Content-Length") {
+			continue
+		}
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", pr.Method, pr.URL, err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func readObject(t *testing.T, s port.Storage, key string) string {
+	t.Helper()
+	rc, err := s.Open(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Open(%s): %v", key, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read %s: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestLocalStoragePresignedUploadAndDownload(t *testing.T) {
+	s := newTestLocalStorage(t)
+	ctx := context.Background()
+	body := []byte("hello, world")
+
+	put, err := s.PresignPut(ctx, "u1/a.txt", "text/plain; charset=utf-8", int64(len(body)), time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	if resp := doPresigned(t, put, body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("upload: got %s", resp.Status)
+	}
+
+	info, err := s.Stat(ctx, "u1/a.txt")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size != int64(len(body)) || info.ContentType != "text/plain" {
+		t.Fatalf("Stat: got %+v", info)
+	}
+	if got := readObject(t, s, "u1/a.txt"); got != string(body) {
+		t.Fatalf("Open: got %q", got)
+	}
+
+	get, err := s.PresignGet(ctx, "u1/a.txt", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet: %v", err)
+	}
+	resp := doPresigned(t, get, nil)
+	got, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(got) != string(body) {
+		t.Fatalf("download: got %s %q", resp.Status, got)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/plain" {
+		t.Fatalf("download: got content type %q", ct)
+	}
+}
+
+func TestLocalStorageRefusesSecondUpload(t *testing.T) {
+	s := newTestLocalStorage(t)
+	ctx := context.Background()
+
+	first, err := s.PresignPut(ctx, "u1/b.txt", "text/plain", 5, time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	if resp := doPresigned(t, first, []byte("first")); resp.StatusCode != http.StatusOK {
+		t.Fatalf("first upload: got %s", resp.Status)
+	}
+
+	// Replaying the same URL, or a fresh one for the key, must not swap the content
+	if resp := doPresigned(t, first, []byte("swap!")); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("replayed upload: got %s, want 412", resp.Status)
+	}
+	second, err := s.PresignPut(ctx, "u1/b.txt", "text/plain", 6, time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	if resp := doPresigned(t, second, []byte("second")); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("second upload: got %s, want 412", resp.Status)
+	}
+
+	if got := readObject(t, s, "u1/b.txt"); got != "first" {
+		t.Fatalf("content changed to %q", got)
+	}
+	if info, _ := s.Stat(ctx, "u1/b.txt"); info.Size != 5 {
+		t.Fatalf("metadata changed to %+v", info)
+	}
+}
+
+func TestLocalStorageRejectsIncompleteUpload(t *testing.T) {
+	s := newTestLocalStorage(t)
+	ctx := context.Background()
+
+	put, err := s.PresignPut(ctx, "u1/c.txt", "text/plain", 10, time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	if resp := doPresigned(t, put, []byte("short")); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("short upload: got %s, want 400", resp.Status)
+	}
+	if _, err := s.Stat(ctx, "u1/c.txt"); !errors.Is(err, port.ErrNotFound) {
+		t.Fatalf("Stat after short upload: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStoragePutReplacesDataAndMetadataTogether(t *testing.T) {
+	s := newTestLocalStorage(t)
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "u1/thumb.jpg", "image/jpeg", strings.NewReader("jpeg"), 4); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Put(ctx, "u1/thumb.jpg", "image/png", strings.NewReader("png!!"), 5); err != nil {
+		t.Fatalf("Put again: %v", err)
+	}
+
+	info, err := s.Stat(ctx, "u1/thumb.jpg")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size != 5 || info.ContentType != "image/png" {
+		t.Fatalf("Stat: got %+v", info)
+	}
+	if got := readObject(t, s, "u1/thumb.jpg"); got != "png!!" {
+		t.Fatalf("Open: got %q", got)
+	}
+}
+
+func TestLocalStorageRejectsTamperedSignature(t *testing.T) {
+	s := newTestLocalStorage(t)
+
+	put, err := s.PresignPut(context.Background(), "u1/d.txt", "text/plain", 4, time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	put.URL = strings.Replace(put.URL, "size=4", "size=8", 1)
+	if resp := doPresigned(t, put, []byte("12345678")); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("tampered upload: got %s, want 403", resp.Status)
+	}
+}
@@ -0,0 +1,322 @@
+package adapter
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-chatty/internal/infrastructure/storage/port"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	emptyPayloadSHA = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	maxPresignAge   = 7 * 24 * time.Hour // longest expiry SigV4 accepts
+)
+
+// S3Config locates a bucket on AWS S3 or an S3-compatible service such as MinIO.
+type S3Config struct {
+	Endpoint        string // e.g. "https://s3.eu-west-1.amazonaws.com" or "http://localhost:9000"
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool // address the bucket as endpoint/bucket/key instead of bucket.endpoint/key
+}
+
+// S3Storage keeps attachments in an S3 bucket. Requests are signed with AWS Signature Version 4:
+// presigned URLs for clients, and signed HEAD requests for Stat.
+type S3Storage struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+// NewS3Storage validates cfg and returns a storage for its bucket.
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	if cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("storage: s3 needs a bucket and credentials")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	u, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("storage: s3 endpoint %q must be an absolute URL", cfg.Endpoint)
+	}
+	return &S3Storage{cfg: cfg, endpoint: u, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// NewS3StorageFromEnv reads S3_ENDPOINT, S3_REGION (default us-east-1), S3_BUCKET, S3_ACCESS_KEY_ID,
+// S3_SECRET_ACCESS_KEY and S3_FORCE_PATH_STYLE (default true, as MinIO expects). The endpoint must be
+// reachable by clients too, since presigned URLs point at it.
+func NewS3StorageFromEnv() (*S3Storage, error) {
+	pathStyle := true
+	if v := strings.TrimSpace(os.Getenv("S3_FORCE_PATH_STYLE")); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("storage: invalid S3_FORCE_PATH_STYLE %q", v)
+		}
+		pathStyle = b
+	}
+	return NewS3Storage(S3Config{
+		Endpoint:        strings.TrimSpace(os.Getenv("S3_ENDPOINT")),
+		Region:          strings.TrimSpace(os.Getenv("S3_REGION")),
+		Bucket:          strings.TrimSpace(os.Getenv("S3_BUCKET")),
+		AccessKeyID:     strings.TrimSpace(os.Getenv("S3_ACCESS_KEY_ID")),
+		SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		PathStyle:       pathStyle,
+	})
+}
+
+// Ensure interface compliance at compile time
+var _ port.Storage = (*S3Storage)(nil)
+
+func (s *S3Storage) PresignPut(_ context.Context, key string, contentType string, size int64, expiry time.Duration) (port.PresignedRequest, error) {
+	// Content type and length are signed, so the upload must match what the attachment declared, and so is
+	// the condition making S3 refuse the upload with 412 once an object exists at key
+	header := http.Header{
+		"Content-Type":   {port.NormalizeContentType(contentType)},
+		"Content-Length": {strconv.FormatInt(size, 10)},
+		"If-None-Match":  {"*"},
+	}
+	return s.presign(http.MethodPut, key, header, expiry, time.Now())
+}
+
+func (s *S3Storage) PresignGet(_ context.Context, key string, expiry time.Duration) (port.PresignedRequest, error) {
+	return s.presign(http.MethodGet, key, nil, expiry, time.Now())
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (port.ObjectInfo, error) {
+	host, uri := s.location(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.endpoint.Scheme+"://"+host+uri, nil)
+	if err != nil {
+		return port.ObjectInfo{}, err
+	}
+	s.signHeaders(req, host, uri, emptyPayloadSHA, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return port.ObjectInfo{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return port.ObjectInfo{}, port.ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return port.ObjectInfo{}, fmt.Errorf("storage: s3 HEAD %s: %s", key, resp.Status)
+	}
+	return port.ObjectInfo{Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	host, uri := s.location(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint.Scheme+"://"+host+uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.signHeaders(req, host, uri, emptyPayloadSHA, time.Now())
+
+	// Objects may be large: stream them without the client timeout, bounded by ctx instead
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, port.ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("storage: s3 GET %s: %s", key, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, contentType string, r io.Reader, size int64) error {
+	host, uri := s.location(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.endpoint.Scheme+"://"+host+uri, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", port.NormalizeContentType(contentType))
+	// The body is streamed, so its hash is not part of the signature
+	s.signHeaders(req, host, uri, unsignedPayload, time.Now())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("storage: s3 PUT %s: %s", key, resp.Status)
+	}
+	return nil
+}
+
+// presign builds a query-signed request valid for expiry; header lists headers the client must send as is.
+func (s *S3Storage) presign(method string, key string, header http.Header, expiry time.Duration, now time.Time) (port.PresignedRequest, error) {
+	if key == "" {
+		return port.PresignedRequest{}, errors.New("storage: empty key")
+	}
+	if expiry <= 0 || expiry > maxPresignAge {
+		return port.PresignedRequest{}, fmt.Errorf("storage: expiry must be within %s", maxPresignAge)
+	}
+	now = now.UTC()
+	host, uri := s.location(key)
+
+	signed := map[string]string{"host": host}
+	for name, values := range header {
+		signed[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+	names := sortedKeys(signed)
+
+	q := url.Values{
+		"X-Amz-Algorithm":     {sigV4Algorithm},
+		"X-Amz-Credential":    {s.cfg.AccessKeyID + "/" + s.scope(now)},
+		"X-Amz-Date":          {now.Format(sigV4TimeFormat)},
+		"X-Amz-Expires":       {strconv.FormatInt(int64(expiry/time.Second), 10)},
+		"X-Amz-SignedHeaders": {strings.Join(names, ";")},
+	}
+	canonical := strings.Join([]string{
+		method,
+		uri,
+		canonicalQuery(q),
+		canonicalHeaders(signed, names),
+		strings.Join(names, ";"),
+		unsignedPayload,
+	}, "\n")
+	q.Set("X-Amz-Signature", s.signature(now, canonical))
+
+	return port.PresignedRequest{
+		Method:    method,
+		URL:       s.endpoint.Scheme + "://" + host + uri + "?" + canonicalQuery(q),
+		Header:    header,
+		ExpiresAt: now.Add(expiry),
+	}, nil
+}
+
+// signHeaders adds an Authorization header to req; payloadHash is the hex SHA-256 of its body or UNSIGNED-PAYLOAD.
+func (s *S3Storage) signHeaders(req *http.Request, host string, uri string, payloadHash string, now time.Time) {
+	now = now.UTC()
+	signed := map[string]string{
+		"host":                 host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           now.Format(sigV4TimeFormat),
+	}
+	names := sortedKeys(signed)
+	canonical := strings.Join([]string{
+		req.Method,
+		uri,
+		"",
+		canonicalHeaders(signed, names),
+		strings.Join(names, ";"),
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", signed["x-amz-date"])
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.cfg.AccessKeyID, s.scope(now), strings.Join(names, ";"), s.signature(now, canonical)))
+}
+
+// location returns the host and escaped path addressing key.
+func (s *S3Storage) location(key string) (string, string) {
+	base := strings.TrimRight(s.endpoint.EscapedPath(), "/")
+	if s.cfg.PathStyle {
+		return s.endpoint.Host, base + "/" + uriEncode(s.cfg.Bucket, false) + "/" + uriEncode(key, true)
+	}
+	return s.cfg.Bucket + "." + s.endpoint.Host, base + "/" + uriEncode(key, true)
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return now.Format(sigV4DateFormat) + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+// signature derives the day's signing key and signs the canonical request.
+func (s *S3Storage) signature(now time.Time, canonicalRequest string) string {
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		now.Format(sigV4TimeFormat),
+		s.scope(now),
+		hex.EncodeToString(digest[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), now.Format(sigV4DateFormat))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(q url.Values) string {
+	parts := make([]string, 0, len(q))
+	for _, name := range sortedKeys(q) {
+		for _, v := range q[name] {
+			parts = append(parts, uriEncode(name, false)+"="+uriEncode(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func canonicalHeaders(headers map[string]string, names []string) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(strings.TrimSpace(headers[name]))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// uriEncode escapes everything but unreserved characters, as SigV4 requires; slashes survive when keepSlash is set.
+func uriEncode(s string, keepSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&15])
+		}
+	}
+	return b.String()
+}
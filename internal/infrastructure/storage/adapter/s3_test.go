@@ -0,0 +1,182 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-chatty/internal/infrastructure/storage/port"
+)
+
+// fakeS3 stands in for MinIO: a path-style bucket keeping objects in memory. It does not verify signatures,
+// but, like S3, it requires every header a request signed to be sent and honours "If-None-Match: *" on PUT.
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+func newFakeS3(t *testing.T, bucket string) (*fakeS3, *httptest.Server) {
+	t.Helper()
+	f := &fakeS3{bucket: bucket, objects: make(map[string]fakeObject)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	key, ok := strings.CutPrefix(req.URL.Path, "/"+f.bucket+"/")
+	if !ok || key == "" {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	if !f.signedHeadersPresent(req) {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch req.Method {
+	case http.MethodPut:
+		if _, exists := f.objects[key]; exists && req.Header.Get("If-None-Match") == "*" {
+			http.Error(w, "PreconditionFailed", http.StatusPreconditionFailed)
+			return
+		}
+		data, err := io.ReadAll(req.Body)
+		if err != nil || int64(len(data)) != req.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = fakeObject{data: data, contentType: req.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodHead, http.MethodGet:
+		obj, exists := f.objects[key]
+		if !exists {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.WriteHeader(http.StatusOK)
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(obj.data)
+		}
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// signedHeadersPresent checks that the request carries every header its signature covers, from the presigned
+// query or the Authorization header.
+func (f *fakeS3) signedHeadersPresent(req *http.Request) bool {
+	signed := req.URL.Query().Get("X-Amz-SignedHeaders")
+	if auth := req.Header.Get("Authorization"); signed == "" && auth != "" {
+		_, rest, _ := strings.Cut(auth, "SignedHeaders=")
+		signed, _, _ = strings.Cut(rest, ",")
+	}
+	if signed == "" {
+		return false
+	}
+	for _, name := range strings.Split(signed, ";") {
+		switch name {
+		case "host":
+		case "content-length":
+			if req.ContentLength < 0 {
+				return false
+			}
+		default:
+			if req.Header.Get(name) == "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func newTestS3Storage(t *testing.T) (*S3Storage, *fakeS3) {
+	t.Helper()
+	f, srv := newFakeS3(t, "attachments")
+	s, err := NewS3Storage(S3Config{
+		Endpoint:        srv.URL,
+		Bucket:          "attachments",
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		PathStyle:       true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	return s, f
+}
+
+func TestS3StoragePresignedUploadIsAcceptedOnce(t *testing.T) {
+	s, f := newTestS3Storage(t)
+	ctx := context.Background()
+
+	put, err := s.PresignPut(ctx, "u1/a.png", "image/png", 5, time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut: %v", err)
+	}
+	if got := put.Header.Get("If-None-Match"); got != "*" {
+		t.Fatalf("PresignPut: If-None-Match header %q, want *", got)
+	}
+	if resp := doPresigned(t, put, []byte("first")); resp.StatusCode != http.StatusOK {
+		t.Fatalf("first upload: got %s", resp.Status)
+	}
+	if resp := doPresigned(t, put, []byte("swap!")); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("replayed upload: got %s, want 412", resp.Status)
+	}
+
+	// Dropping the condition breaks the signature
+	put.Header.Del("If-None-Match")
+	if resp := doPresigned(t, put, []byte("swap!")); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("unconditional upload: got %s, want 403", resp.Status)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if got := string(f.objects["u1/a.png"].data); got != "first" {
+		t.Fatalf("content changed to %q", got)
+	}
+}
+
+func TestS3StoragePutStatOpen(t *testing.T) {
+	s, _ := newTestS3Storage(t)
+	ctx := context.Background()
+
+	if _, err := s.Stat(ctx, "u1/thumb.jpg"); !errors.Is(err, port.ErrNotFound) {
+		t.Fatalf("Stat before Put: got %v, want ErrNotFound", err)
+	}
+	if err := s.Put(ctx, "u1/thumb.jpg", "image/jpeg", strings.NewReader("jpeg"), 4); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// Derived objects are rewritten when processing runs again
+	if err := s.Put(ctx, "u1/thumb.jpg", "image/jpeg", strings.NewReader("jpeg2"), 5); err != nil {
+		t.Fatalf("Put again: %v", err)
+	}
+
+	info, err := s.Stat(ctx, "u1/thumb.jpg")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size != 5 || info.ContentType != "image/jpeg" {
+		t.Fatalf("Stat: got %+v", info)
+	}
+	if got := readObject(t, s, "u1/thumb.jpg"); got != "jpeg2" {
+		t.Fatalf("Open: got %q", got)
+	}
+}
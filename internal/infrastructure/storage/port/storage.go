@@ -0,0 +1,78 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by adapters when no object is stored under the key.
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrNotAllowed is returned by Policy.Check for files the deployment does not accept.
+var ErrNotAllowed = errors.New("storage: file type or size not allowed")
+
+// PresignedRequest is an HTTP request a client may perform directly against the storage backend
+// until ExpiresAt. Header lists headers the client must send with exactly these values.
+type PresignedRequest struct {
+	Method    string      `json:"method"`
+	URL       string      `json:"url"`
+	Header    http.Header `json:"headers,omitempty"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+}
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// Storage is the object store holding message attachments. Clients move the bytes themselves through
+// presigned requests; the application only signs them and inspects the result, and writes derived
+// objects such as thumbnails. Implementations should be concurrency-safe.
+type Storage interface {
+	// PresignPut signs an upload of exactly size bytes of contentType to key. The upload is refused once an
+	// object is stored at key, so an object never changes after it was inspected or sent.
+	PresignPut(ctx context.Context, key string, contentType string, size int64, expiry time.Duration) (PresignedRequest, error)
+	// PresignGet signs a download of key.
+	PresignGet(ctx context.Context, key string, expiry time.Duration) (PresignedRequest, error)
+	// Stat describes the object at key, or returns ErrNotFound while nothing was uploaded there.
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Open streams the object at key, or returns ErrNotFound. Callers must close the reader.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Put stores size bytes read from r at key, replacing any object already there.
+	Put(ctx context.Context, key string, contentType string, r io.Reader, size int64) error
+}
+
+// Policy limits which files may be uploaded. AllowedTypes holds MIME types, where "type/*" matches a whole family.
+type Policy struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+// Check returns ErrNotAllowed, wrapped with the reason, unless a file of contentType and size is acceptable.
+func (p Policy) Check(contentType string, size int64) error {
+	if size <= 0 {
+		return fmt.Errorf("%w: size must be positive", ErrNotAllowed)
+	}
+	if p.MaxSize > 0 && size > p.MaxSize {
+		return fmt.Errorf("%w: size exceeds %d bytes", ErrNotAllowed, p.MaxSize)
+	}
+	mediaType := NormalizeContentType(contentType)
+	for _, allowed := range p.AllowedTypes {
+		if allowed == mediaType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: content type %q is not accepted", ErrNotAllowed, mediaType)
+}
+
+// NormalizeContentType lower-cases a MIME type and drops its parameters, e.g. "Text/Plain; charset=utf-8" -> "text/plain".
+func NormalizeContentType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
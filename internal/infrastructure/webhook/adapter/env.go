@@ -0,0 +1,24 @@
+package adapter
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// NewHTTPSenderFromEnv constructs an HTTPSender using:
+// - WEBHOOK_TIMEOUT: optional time budget of an attempt, as a Go duration (default 10s)
+// - WEBHOOK_ALLOW_PRIVATE_NETWORKS: optional, "true" lets webhooks reach internal addresses (default false)
+func NewHTTPSenderFromEnv() *HTTPSender {
+	timeout := defaultTimeout
+	if v := strings.TrimSpace(os.Getenv("WEBHOOK_TIMEOUT")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			timeout = d
+		}
+	}
+	allowPrivate, _ := strconv.ParseBool(strings.TrimSpace(os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS")))
+	return NewHTTPSender(timeout, allowPrivate)
+}
@@ -0,0 +1,119 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-chatty/internal/infrastructure/webhook/port"
+)
+
+// Headers of webhook requests. The signature is "sha256=" followed by the hex HMAC-SHA256, keyed with the
+// subscription secret, of the timestamp header, a dot and the body.
+const (
+	HeaderEvent     = "X-Chatty-Event"
+	HeaderDelivery  = "X-Chatty-Delivery"
+	HeaderTimestamp = "X-Chatty-Timestamp"
+	HeaderSignature = "X-Chatty-Signature"
+)
+
+// HTTPSender posts webhook requests signed with their subscription's secret. Redirects are not followed,
+// and unless private networks are allowed, only public addresses are dialed.
+type HTTPSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// Ensure interface compliance at compile time
+var _ port.Sender = (*HTTPSender)(nil)
+
+// NewHTTPSender gives every attempt timeout to complete. allowPrivate lets webhooks reach loopback, private and
+// link-local addresses, which is meant for development setups only.
+func NewHTTPSender(timeout time.Duration, allowPrivate bool) *HTTPSender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		// Checked on the resolved address, so DNS cannot smuggle internal destinations in
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublic(ip) {
+				return port.ErrBlockedAddress
+			}
+			return nil
+		}
+	}
+	transport := &http.Transport{
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, r port.Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, err
+	}
+	ts := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-chatty-webhooks")
+	req.Header.Set(HeaderEvent, r.EventType)
+	req.Header.Set(HeaderDelivery, r.DeliveryID)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, Sign(r.Secret, ts, r.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook: subscriber answered %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the HeaderSignature value of body sent at timestamp (Unix seconds, as in HeaderTimestamp).
+// Receivers recompute it to authenticate requests, and should reject stale timestamps to stop replays.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// isPublic tells whether ip is a globally routable unicast address.
+func isPublic(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsUnspecified() && !sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which net.IP.IsPrivate does not cover.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBlockedAddress is returned by Sender.Send when the URL resolves to an address webhooks may not reach,
+// such as loopback or private networks.
+var ErrBlockedAddress = errors.New("webhook: destination address is not allowed")
+
+// Request is one attempt at delivering an event to a subscriber.
+type Request struct {
+	URL        string
+	Secret     string // signs Body
+	DeliveryID string // stable across the attempts of a delivery
+	EventType  string
+	Body       []byte
+}
+
+// Sender posts signed webhook requests. Implementations should be concurrency-safe.
+type Sender interface {
+	// Send posts r and returns the HTTP status of the answer, or 0 when there was none. The error is nil
+	// only for 2xx answers.
+	Send(ctx context.Context, r Request) (int, error)
+}
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -12,6 +13,35 @@ var (
 	ErrUserBlocked         = errors.New("chat: message not allowed because one of the parties is blocked")
 	ErrBackdatedMessage    = errors.New("chat: message timestamp is backdated")
 	ErrEmptyMessage        = errors.New("chat: empty message (no body or attachment)")
+	ErrCannotBlockSelf     = errors.New("chat: users cannot block themselves")
+	ErrForbidden           = errors.New("chat: action not permitted for this participant")
+	ErrNotGroup            = errors.New("chat: operation requires a group conversation")
+	ErrAlreadyParticipant  = errors.New("chat: user is already a participant in the conversation")
+	ErrInvalidRole         = errors.New("chat: invalid participant role")
+	ErrInvalidKind         = errors.New("chat: invalid conversation kind")
+	ErrLastOwner           = errors.New("chat: the last owner cannot leave while other participants remain")
+	ErrMessageNotFound     = errors.New("chat: message not found")
+	ErrMessageDeleted      = errors.New("chat: message has been deleted")
+	ErrInvalidReaction     = errors.New("chat: invalid reaction emoji")
+	ErrReactionLimit       = errors.New("chat: too many reactions on this message")
+	ErrInvalidThread       = errors.New("chat: invalid reply target for this thread")
+	ErrInvalidAttachment   = errors.New("chat: invalid attachment")
+	ErrAttachmentNotFound  = errors.New("chat: attachment not found")
+	ErrAttachmentMissing   = errors.New("chat: attachment has not been uploaded yet")
+	ErrAttachmentInUse     = errors.New("chat: attachment is already used by another message")
+	ErrAttachmentRejected  = errors.New("chat: attachment content is not an accepted file type")
+
+	ErrInvalidNotificationLevel = errors.New("chat: invalid notification level")
+	ErrInvalidPlatform          = errors.New("chat: invalid device platform")
+	ErrInvalidDeviceToken       = errors.New("chat: invalid device token")
+	ErrDeviceNotFound           = errors.New("chat: device token not found")
+
+	ErrInvalidWebhookURL     = errors.New("chat: webhook url must be an absolute http or https url")
+	ErrInvalidEventType      = errors.New("chat: invalid event type")
+	ErrInvalidDeliveryStatus = errors.New("chat: invalid delivery status")
+	ErrWebhookNotFound       = errors.New("chat: webhook subscription not found")
+	ErrDeliveryNotFound      = errors.New("chat: webhook delivery not found")
+	ErrDeliveryPending       = errors.New("chat: webhook delivery is still being attempted")
 )
 
 // Chat is the domain aggregate for a conversation and its invariants.
@@ -23,8 +53,8 @@ var (
 //   - Persistence is handled by repositories outside the domain; this type only
 //     enforces rules and shapes intent.
 //
-// Future: for group chats, Participants may include many userIDs; block checks
-// should be applied against all other members.
+// For group chats, Participants may include many userIDs; membership changes are
+// permission-checked here and produce system messages for the conversation log.
 type Chat struct {
 	Conversation  Conversation
 	Participants  map[string]Participant // keyed by userID
@@ -46,7 +76,7 @@ func (c *Chat) HasParticipant(userID string) bool {
 // Validations:
 // - Conversation/message identity must match
 // - Sender must be a participant
-// - No blocks between sender and any other participant (bidirectional check)
+// - No blocks between sender and the other participant of a direct conversation (bidirectional check)
 // - Message must not be backdated relative to LastMessageAt (if known)
 // - Non-system messages must include either body or attachment
 //
@@ -54,8 +84,8 @@ func (c *Chat) HasParticipant(userID string) bool {
 // - If m.CreatedAt is zero, it is set to now.
 // - On success, c.LastMessageAt is advanced to m.CreatedAt.
 //
-// The isBlocked function should return true if either direction of block is in effect.
-// If isBlocked is nil, blocks are not checked.
+// Block should be hydrated with any block in either direction between the sender and the
+// other participant; it only prevents messages in direct conversations.
 func (c *Chat) PostMessage(m Message, now time.Time) (Message, error) {
 	// Identity check
 	if m.ConversationID == "" || c.Conversation.ID == "" || m.ConversationID != c.Conversation.ID {
@@ -68,7 +98,7 @@ func (c *Chat) PostMessage(m Message, now time.Time) (Message, error) {
 	}
 
 	// Block checks for DM
-	if c.Block != nil && len(c.Participants) > 2 {
+	if c.Block != nil && c.Conversation.Kind == ConversationKindDirect {
 		return Message{}, ErrUserBlocked
 	}
 
@@ -101,3 +131,327 @@ func (c *Chat) PostMessage(m Message, now time.Time) (Message, error) {
 
 	return m, nil
 }
+
+// EditMessage replaces the body of m on behalf of actorID and returns the edited message along
+// with the edit-history entry holding the previous body. Only the sender may edit, and system
+// messages and tombstones cannot be edited.
+func (c *Chat) EditMessage(actorID string, m Message, body string, now time.Time) (Message, MessageEdit, error) {
+	if err := c.checkMessage(actorID, m); err != nil {
+		return Message{}, MessageEdit{}, err
+	}
+	if m.SenderID != actorID || m.MsgType == MessageTypeSystem {
+		return Message{}, MessageEdit{}, ErrForbidden
+	}
+	body = strings.TrimSpace(body)
+	if body == "" && m.AttachmentURL == nil {
+		return Message{}, MessageEdit{}, ErrEmptyMessage
+	}
+
+	ts := normalizeNow(now)
+	edit := MessageEdit{MessageID: m.ID, EditorID: actorID, PreviousBody: m.Body, EditedAt: ts}
+	if body == "" {
+		m.Body = nil
+	} else {
+		m.Body = &body
+	}
+	m.EditedAt = &ts
+	return m, edit, nil
+}
+
+// DeleteMessage turns m into a tombstone on behalf of actorID: body and attachment are blanked
+// and DeletedAt is set. The sender may delete their own messages; in groups admins and owners
+// may delete any non-system message.
+func (c *Chat) DeleteMessage(actorID string, m Message, now time.Time) (Message, error) {
+	if err := c.checkMessage(actorID, m); err != nil {
+		return Message{}, err
+	}
+	if m.MsgType == MessageTypeSystem {
+		return Message{}, ErrForbidden
+	}
+	if m.SenderID != actorID {
+		actor := c.Participants[actorID]
+		if c.Conversation.Kind != ConversationKindGroup || !actor.Role.CanManage() {
+			return Message{}, ErrForbidden
+		}
+	}
+
+	ts := normalizeNow(now)
+	m.Body = nil
+	m.AttachmentURL = nil
+	m.AttachmentMeta = nil
+	m.AttachmentID = nil
+	m.DeletedAt = &ts
+	m.DeletedBy = &actorID
+	return m, nil
+}
+
+// React adds actorID's emoji to m. existing holds the reactions already stored for m; they are
+// used to enforce MaxReactionsPerUser and MaxReactionEmojisPerMessage. Reacting twice with the
+// same emoji is not an error: the stored reaction is returned with added=false.
+func (c *Chat) React(actorID string, m Message, emoji string, existing []Reaction, now time.Time) (Reaction, bool, error) {
+	if err := c.checkMessage(actorID, m); err != nil {
+		return Reaction{}, false, err
+	}
+	if m.MsgType == MessageTypeSystem {
+		return Reaction{}, false, ErrForbidden
+	}
+	emoji, err := NormalizeEmoji(emoji)
+	if err != nil {
+		return Reaction{}, false, err
+	}
+
+	mine := 0
+	emojis := make(map[string]struct{})
+	for _, r := range existing {
+		if r.UserID == actorID {
+			if r.Emoji == emoji {
+				return r, false, nil
+			}
+			mine++
+		}
+		emojis[r.Emoji] = struct{}{}
+	}
+	if mine >= MaxReactionsPerUser {
+		return Reaction{}, false, ErrReactionLimit
+	}
+	if _, ok := emojis[emoji]; !ok && len(emojis) >= MaxReactionEmojisPerMessage {
+		return Reaction{}, false, ErrReactionLimit
+	}
+
+	return Reaction{MessageID: m.ID, UserID: actorID, Emoji: emoji, CreatedAt: normalizeNow(now)}, true, nil
+}
+
+// Unreact validates the removal of actorID's emoji from m.
+func (c *Chat) Unreact(actorID string, m Message, emoji string) (Reaction, error) {
+	if err := c.checkMessage(actorID, m); err != nil {
+		return Reaction{}, err
+	}
+	emoji, err := NormalizeEmoji(emoji)
+	if err != nil {
+		return Reaction{}, err
+	}
+	return Reaction{MessageID: m.ID, UserID: actorID, Emoji: emoji}, nil
+}
+
+// CheckReply validates the quoted message and thread root a new message from senderID refers to; either may be nil.
+// Threads are one level deep: the root must be a top-level message, and a quote inside a thread must come from that
+// thread, while a quote outside threads must not reach into one.
+func (c *Chat) CheckReply(senderID string, replyTo *Message, root *Message) error {
+	for _, ref := range []*Message{replyTo, root} {
+		if ref == nil {
+			continue
+		}
+		if err := c.checkMessage(senderID, *ref); err != nil {
+			return err
+		}
+		if !c.CanView(senderID, *ref) {
+			return ErrMessageNotFound
+		}
+	}
+
+	if root != nil && (root.ThreadRootID != nil || root.MsgType == MessageTypeSystem) {
+		return ErrInvalidThread
+	}
+	if replyTo == nil {
+		return nil
+	}
+	if root == nil {
+		if replyTo.ThreadRootID != nil {
+			return ErrInvalidThread
+		}
+		return nil
+	}
+	if replyTo.ID != root.ID && (replyTo.ThreadRootID == nil || *replyTo.ThreadRootID != root.ID) {
+		return ErrInvalidThread
+	}
+	return nil
+}
+
+// CanView tells whether userID may see m: newcomers to groups that hide history only see messages
+// sent after they joined.
+func (c *Chat) CanView(userID string, m Message) bool {
+	p, ok := c.Participants[userID]
+	if !ok {
+		return false
+	}
+	return !c.Conversation.IsHistoryHiddenFromNewcomers() || !m.CreatedAt.Before(p.JoinedAt)
+}
+
+// checkMessage ensures m belongs to this chat, actorID is a member and m is not a tombstone.
+func (c *Chat) checkMessage(actorID string, m Message) error {
+	if m.ConversationID != c.Conversation.ID {
+		return ErrMessageNotFound
+	}
+	if !c.HasParticipant(actorID) {
+		return ErrNotParticipant
+	}
+	if m.IsDeleted() {
+		return ErrMessageDeleted
+	}
+	return nil
+}
+
+// AddParticipant lets a group admin or owner add userID with the given role.
+// Actors cannot grant a role above their own.
+func (c *Chat) AddParticipant(actorID string, userID string, role ParticipantRole, now time.Time) (Participant, Message, error) {
+	actor, err := c.manager(actorID)
+	if err != nil {
+		return Participant{}, Message{}, err
+	}
+	if userID == "" || !role.Valid() {
+		return Participant{}, Message{}, ErrInvalidRole
+	}
+	if c.HasParticipant(userID) {
+		return Participant{}, Message{}, ErrAlreadyParticipant
+	}
+	if role > actor.Role {
+		return Participant{}, Message{}, ErrForbidden
+	}
+
+	ts := normalizeNow(now)
+	p := Participant{ConversationID: c.Conversation.ID, UserID: userID, Role: role, JoinedAt: ts}
+	c.Participants[userID] = p
+
+	msg := NewSystemMessage(c.Conversation.ID, SystemEvent{
+		Event:   SystemEventParticipantAdded,
+		ActorID: actorID,
+		UserID:  userID,
+		Role:    role.String(),
+	}, ts)
+	return p, msg, nil
+}
+
+// RemoveParticipant lets a group admin or owner remove a participant of lower privilege.
+// Participants leave on their own through Leave.
+func (c *Chat) RemoveParticipant(actorID string, userID string, now time.Time) (Message, error) {
+	actor, err := c.manager(actorID)
+	if err != nil {
+		return Message{}, err
+	}
+	target, ok := c.Participants[userID]
+	if !ok {
+		return Message{}, ErrNotParticipant
+	}
+	if actorID == userID || !actor.outranks(target) {
+		return Message{}, ErrForbidden
+	}
+
+	delete(c.Participants, userID)
+
+	return NewSystemMessage(c.Conversation.ID, SystemEvent{
+		Event:   SystemEventParticipantRemoved,
+		ActorID: actorID,
+		UserID:  userID,
+	}, normalizeNow(now)), nil
+}
+
+// ChangeRole lets a group admin or owner change the role of a participant of lower privilege.
+// Owners may act on anyone but themselves; nobody can grant a role above their own.
+func (c *Chat) ChangeRole(actorID string, userID string, role ParticipantRole, now time.Time) (Participant, Message, error) {
+	actor, err := c.manager(actorID)
+	if err != nil {
+		return Participant{}, Message{}, err
+	}
+	if !role.Valid() {
+		return Participant{}, Message{}, ErrInvalidRole
+	}
+	target, ok := c.Participants[userID]
+	if !ok {
+		return Participant{}, Message{}, ErrNotParticipant
+	}
+	if actorID == userID || !actor.outranks(target) || role > actor.Role {
+		return Participant{}, Message{}, ErrForbidden
+	}
+
+	target.Role = role
+	c.Participants[userID] = target
+
+	msg := NewSystemMessage(c.Conversation.ID, SystemEvent{
+		Event:   SystemEventRoleChanged,
+		ActorID: actorID,
+		UserID:  userID,
+		Role:    role.String(),
+	}, normalizeNow(now))
+	return target, msg, nil
+}
+
+// UpdateProfile lets a group admin or owner rename the group and/or change its avatar.
+// Nil arguments leave the corresponding field untouched.
+func (c *Chat) UpdateProfile(actorID string, title *string, avatarURL *string, now time.Time) (Message, error) {
+	if _, err := c.manager(actorID); err != nil {
+		return Message{}, err
+	}
+	if title != nil {
+		c.Conversation.Title = title
+	}
+	if avatarURL != nil {
+		c.Conversation.AvatarURL = avatarURL
+	}
+
+	return NewSystemMessage(c.Conversation.ID, SystemEvent{
+		Event:     SystemEventConversationUpdated,
+		ActorID:   actorID,
+		Title:     title,
+		AvatarURL: avatarURL,
+	}, normalizeNow(now)), nil
+}
+
+// Leave removes userID from a group. The last owner must hand over ownership first
+// unless they are the last participant.
+func (c *Chat) Leave(userID string, now time.Time) (Message, error) {
+	if c.Conversation.Kind != ConversationKindGroup {
+		return Message{}, ErrNotGroup
+	}
+	p, ok := c.Participants[userID]
+	if !ok {
+		return Message{}, ErrNotParticipant
+	}
+	if p.Role == ParticipantRoleOwner && len(c.Participants) > 1 && c.countRole(ParticipantRoleOwner) == 1 {
+		return Message{}, ErrLastOwner
+	}
+
+	delete(c.Participants, userID)
+
+	return NewSystemMessage(c.Conversation.ID, SystemEvent{
+		Event:   SystemEventParticipantLeft,
+		ActorID: userID,
+		UserID:  userID,
+	}, normalizeNow(now)), nil
+}
+
+// manager returns the acting participant if the chat is a group and they may administer it.
+func (c *Chat) manager(actorID string) (Participant, error) {
+	if c.Conversation.Kind != ConversationKindGroup {
+		return Participant{}, ErrNotGroup
+	}
+	actor, ok := c.Participants[actorID]
+	if !ok {
+		return Participant{}, ErrNotParticipant
+	}
+	if !actor.Role.CanManage() {
+		return Participant{}, ErrForbidden
+	}
+	return actor, nil
+}
+
+func (c *Chat) countRole(role ParticipantRole) int {
+	n := 0
+	for _, p := range c.Participants {
+		if p.Role == role {
+			n++
+		}
+	}
+	return n
+}
+
+// outranks tells whether p may act on target: owners act on anyone, others only on lower roles.
+func (p Participant) outranks(target Participant) bool {
+	return p.Role == ParticipantRoleOwner || p.Role > target.Role
+}
+
+func normalizeNow(now time.Time) time.Time {
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return now.UTC()
+}
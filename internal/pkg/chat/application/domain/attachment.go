@@ -0,0 +1,92 @@
+package chat
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxFileNameLength bounds the file name kept for an attachment, in bytes.
+const maxFileNameLength = 255
+
+// Attachment is a file uploaded to object storage for use in a message (chat.attachment).
+// It is registered before the upload with the name, type and size the client declares, and
+// StorageKey locates the object. An attachment backs at most one message, sent by its owner.
+// Once the upload has been inspected in the background, ProcessedAt is set along with what was learned;
+// Rejected then tells that the sniffed type is one the upload policy does not accept.
+type Attachment struct {
+	ID          string    `db:"id"`
+	OwnerID     string    `db:"owner_id"`
+	StorageKey  string    `db:"storage_key"`
+	FileName    string    `db:"file_name"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size_bytes"`
+	CreatedAt   time.Time `db:"created_at"`
+
+	SniffedType  *string    `db:"sniffed_type"` // type detected from the content, which may differ from ContentType
+	Checksum     *string    `db:"checksum"`     // hex-encoded SHA-256 of the content
+	Width        *int       `db:"width"`        // pixels, for images
+	Height       *int       `db:"height"`
+	ThumbnailKey *string    `db:"thumbnail_key"`
+	ProcessedAt  *time.Time `db:"processed_at"`
+	Rejected     bool       `db:"rejected"`
+}
+
+// NewAttachment validates the declared file and returns an attachment ready to persist.
+// Only the base name of fileName is kept, so client paths never end up in storage or history.
+func NewAttachment(a Attachment) (*Attachment, error) {
+	if a.OwnerID == "" || a.StorageKey == "" {
+		return nil, ErrInvalidAttachment
+	}
+	name := path.Base(strings.ReplaceAll(strings.TrimSpace(a.FileName), "\\", "/"))
+	if name == "." || name == "/" || len(name) > maxFileNameLength || !utf8.ValidString(name) {
+		return nil, ErrInvalidAttachment
+	}
+	a.FileName = name
+	if a.ContentType == "" || a.Size <= 0 {
+		return nil, ErrInvalidAttachment
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	}
+	return &a, nil
+}
+
+// URL is the API path participants use to get a fresh download link for the attachment.
+func (a Attachment) URL() string {
+	return "/api/v1/attachments/" + a.ID
+}
+
+// Metadata renders the attachment_meta JSON of a message carrying the attachment.
+// Processing results are only included once available.
+func (a Attachment) Metadata() string {
+	raw, _ := json.Marshal(struct {
+		AttachmentID string  `json:"attachmentId"`
+		FileName     string  `json:"fileName"`
+		ContentType  string  `json:"contentType"`
+		Size         int64   `json:"size"`
+		Processed    bool    `json:"processed,omitempty"`
+		SniffedType  *string `json:"sniffedType,omitempty"`
+		Checksum     *string `json:"sha256,omitempty"`
+		Width        *int    `json:"width,omitempty"`
+		Height       *int    `json:"height,omitempty"`
+		Thumbnail    bool    `json:"thumbnail,omitempty"`
+		Rejected     bool    `json:"rejected,omitempty"`
+	}{a.ID, a.FileName, a.ContentType, a.Size, a.ProcessedAt != nil, a.SniffedType, a.Checksum, a.Width, a.Height, a.ThumbnailKey != nil, a.Rejected})
+	return string(raw)
+}
+
+// MessageType is the message type that fits the attachment: images for pictures, files otherwise.
+// The sniffed type wins over the declared one, so a file posing as a picture is not shown as one.
+func (a Attachment) MessageType() MessageType {
+	contentType := a.ContentType
+	if a.SniffedType != nil {
+		contentType = *a.SniffedType
+	}
+	if strings.HasPrefix(contentType, "image/") {
+		return MessageTypeImage
+	}
+	return MessageTypeFile
+}
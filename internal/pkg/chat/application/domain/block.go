@@ -3,9 +3,11 @@ package chat
 import "time"
 
 // Block represents a 1:1 block (future-proof for groups)
+// A block prevents direct conversations and direct messages in either direction,
+// and hides the blocker's realtime events from the blocked user in groups.
 type Block struct {
 	ID        string    `db:"id"`
-	CreatedAt time.Time `db:"created_at"`
+	CreatedAt time.Time `db:"at"`
 	BlockerId string    `db:"blocker_id"`
 	BlockedId string    `db:"blocked_id"`
 }
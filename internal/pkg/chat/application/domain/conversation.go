@@ -1,10 +1,64 @@
 package chat
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
-// Conversation represents a 1:1 thread (future-proof for groups)
+// ConversationKind distinguishes 1:1 threads from group conversations
+// 0=direct, 1=group
+type ConversationKind int16
+
+const (
+	ConversationKindDirect ConversationKind = 0
+	ConversationKindGroup  ConversationKind = 1
+)
+
+// ParseConversationKind maps the API representation ("direct", "group") to a kind.
+func ParseConversationKind(s string) (ConversationKind, error) {
+	switch s {
+	case "direct":
+		return ConversationKindDirect, nil
+	case "group":
+		return ConversationKindGroup, nil
+	}
+	return 0, ErrInvalidKind
+}
+
+// String returns the API representation of the kind.
+func (k ConversationKind) String() string {
+	if k == ConversationKindGroup {
+		return "group"
+	}
+	return "direct"
+}
+
+// Conversation represents a 1:1 thread or a group conversation
 type Conversation struct {
-	ID        string    `db:"id"`
-	CreatedAt time.Time `db:"created_at"`
-	TenantID  string    `db:"tenant_id"`
+	ID            string           `db:"id"`
+	CreatedAt     time.Time        `db:"created_at"`
+	TenantID      string           `db:"tenant_id"`
+	Kind          ConversationKind `db:"kind"`
+	HistoryHidden bool             `db:"history_hidden"` // groups only: newcomers cannot read messages sent before they joined
+	Title         *string          `db:"title"`          // groups only
+	AvatarURL     *string          `db:"avatar_url"`     // groups only
+	PairKey       string           `db:"pair_key"`       // direct only: canonical key of the two participants
+	// Denormalized by the repository when a top-level message is stored
+	LastMessageID  *string   `db:"last_message_id"`
+	LastActivityAt time.Time `db:"last_activity_at"` // time of the latest top-level message, or creation time
+}
+
+// DirectPairKey returns the canonical key of an unordered pair of users.
+// Direct conversations are unique per tenant and pair key.
+func DirectPairKey(userA string, userB string) string {
+	a, b := strings.ToLower(userA), strings.ToLower(userB)
+	if b < a {
+		a, b = b, a
+	}
+	return a + ":" + b
+}
+
+// IsHistoryHiddenFromNewcomers tells whether members only see messages sent after they joined.
+func (c Conversation) IsHistoryHiddenFromNewcomers() bool {
+	return c.Kind == ConversationKindGroup && c.HistoryHidden
 }
@@ -0,0 +1,112 @@
+package chat
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("chat: invalid cursor")
+
+// MessageCursor is a keyset position in a conversation's message log.
+// Messages are totally ordered by (CreatedAt, ID).
+type MessageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorOf returns the keyset position of m.
+func CursorOf(m Message) MessageCursor {
+	return MessageCursor{CreatedAt: m.CreatedAt, ID: m.ID}
+}
+
+// Encode returns the opaque, URL-safe representation of the cursor.
+func (c MessageCursor) Encode() string {
+	return encodeKeyset(c.CreatedAt, c.ID)
+}
+
+// DecodeMessageCursor parses a cursor produced by MessageCursor.Encode.
+func DecodeMessageCursor(s string) (MessageCursor, error) {
+	createdAt, id, err := decodeKeyset(s)
+	if err != nil {
+		return MessageCursor{}, err
+	}
+	return MessageCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// ConversationCursor is a keyset position in a user's inbox.
+// Conversations are totally ordered by (LastActivityAt, ID).
+type ConversationCursor struct {
+	LastActivityAt time.Time
+	ID             string
+}
+
+// ConversationCursorOf returns the inbox position of c.
+func ConversationCursorOf(c Conversation) ConversationCursor {
+	return ConversationCursor{LastActivityAt: c.LastActivityAt, ID: c.ID}
+}
+
+// Encode returns the opaque, URL-safe representation of the cursor.
+func (c ConversationCursor) Encode() string {
+	return encodeKeyset(c.LastActivityAt, c.ID)
+}
+
+// DecodeConversationCursor parses a cursor produced by ConversationCursor.Encode.
+func DecodeConversationCursor(s string) (ConversationCursor, error) {
+	at, id, err := decodeKeyset(s)
+	if err != nil {
+		return ConversationCursor{}, err
+	}
+	return ConversationCursor{LastActivityAt: at, ID: id}, nil
+}
+
+// DeliveryCursor is a keyset position in a webhook subscription's deliveries.
+// Deliveries are totally ordered by (CreatedAt, ID).
+type DeliveryCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// DeliveryCursorOf returns the keyset position of d.
+func DeliveryCursorOf(d WebhookDelivery) DeliveryCursor {
+	return DeliveryCursor{CreatedAt: d.CreatedAt, ID: d.ID}
+}
+
+// Encode returns the opaque, URL-safe representation of the cursor.
+func (c DeliveryCursor) Encode() string {
+	return encodeKeyset(c.CreatedAt, c.ID)
+}
+
+// DecodeDeliveryCursor parses a cursor produced by DeliveryCursor.Encode.
+func DecodeDeliveryCursor(s string) (DeliveryCursor, error) {
+	createdAt, id, err := decodeKeyset(s)
+	if err != nil {
+		return DeliveryCursor{}, err
+	}
+	return DeliveryCursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+func encodeKeyset(t time.Time, id string) string {
+	raw := t.UTC().Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeKeyset(s string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	ts, id, found := strings.Cut(string(raw), "|")
+	if !found || uuid.Validate(id) != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	return t, id, nil
+}
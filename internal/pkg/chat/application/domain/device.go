@@ -0,0 +1,44 @@
+package chat
+
+import "time"
+
+// DevicePlatform is the push service behind a device token
+// 0 = fcm, 1 = apns
+type DevicePlatform int16
+
+const (
+	DevicePlatformFCM  DevicePlatform = 0
+	DevicePlatformAPNs DevicePlatform = 1
+)
+
+// MaxDeviceTokenLength bounds the tokens accepted at registration; FCM and APNs tokens are far shorter.
+const MaxDeviceTokenLength = 4096
+
+// ParseDevicePlatform maps the API representation ("fcm", "apns") to a platform.
+func ParseDevicePlatform(s string) (DevicePlatform, error) {
+	switch s {
+	case "fcm":
+		return DevicePlatformFCM, nil
+	case "apns":
+		return DevicePlatformAPNs, nil
+	}
+	return 0, ErrInvalidPlatform
+}
+
+// String returns the API representation of the platform.
+func (p DevicePlatform) String() string {
+	if p == DevicePlatformAPNs {
+		return "apns"
+	}
+	return "fcm"
+}
+
+// DeviceToken is the push address of one of a user's app installs (chat.device_token).
+type DeviceToken struct {
+	Token     string         `db:"token"`
+	UserID    string         `db:"user_id"`
+	Platform  DevicePlatform `db:"platform"`
+	DeviceID  *string        `db:"device_id"` // the install's X-Device-ID, when it sent one
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
@@ -0,0 +1,164 @@
+package chat
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType names a change announced to other services, e.g. through webhooks.
+type EventType string
+
+const (
+	EventMessageCreated         EventType = "message.created"
+	EventConversationCreated    EventType = "conversation.created"
+	EventParticipantAdded       EventType = "participant.added"
+	EventParticipantRemoved     EventType = "participant.removed"
+	EventParticipantLeft        EventType = "participant.left"
+	EventParticipantRoleChanged EventType = "participant.role_changed"
+)
+
+// EventTypes lists every announced change.
+var EventTypes = []EventType{
+	EventMessageCreated,
+	EventConversationCreated,
+	EventParticipantAdded,
+	EventParticipantRemoved,
+	EventParticipantLeft,
+	EventParticipantRoleChanged,
+}
+
+// Valid tells whether t is one of EventTypes.
+func (t EventType) Valid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a change of a tenant's conversations as announced outside the service. ID is unique per change,
+// so receivers can discard the duplicates at-least-once delivery produces. Data is the JSON detail of the change.
+type Event struct {
+	ID             string          `json:"id"`
+	Type           EventType       `json:"type"`
+	TenantID       string          `json:"tenantId,omitempty"`
+	ConversationID string          `json:"conversationId"`
+	OccurredAt     time.Time       `json:"occurredAt"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// eventMessage is the Data of message events.
+type eventMessage struct {
+	ID             string      `json:"id"`
+	ConversationID string      `json:"conversationId"`
+	SenderID       string      `json:"senderId"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	Body           *string     `json:"body,omitempty"`
+	MsgType        MessageType `json:"msgType"`
+	AttachmentID   *string     `json:"attachmentId,omitempty"`
+	ReplyToID      *string     `json:"replyToId,omitempty"`
+	ThreadRootID   *string     `json:"threadRootId,omitempty"`
+	Seq            int64       `json:"seq"`
+}
+
+// eventConversation is the Data of conversation events.
+type eventConversation struct {
+	ID            string             `json:"id"`
+	Kind          string             `json:"kind"`
+	Title         *string            `json:"title,omitempty"`
+	AvatarURL     *string            `json:"avatarUrl,omitempty"`
+	HistoryHidden bool               `json:"historyHidden"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	Participants  []eventParticipant `json:"participants"`
+}
+
+type eventParticipant struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// eventMembership is the Data of participant events: the change as recorded by the system message MessageID.
+type eventMembership struct {
+	MessageID string `json:"messageId"`
+	ActorID   string `json:"actorId"`
+	UserID    string `json:"userId"`
+	Role      string `json:"role,omitempty"`
+	Seq       int64  `json:"seq"`
+}
+
+// membershipEvents maps the system messages of membership changes to the events announcing them.
+var membershipEvents = map[SystemEventType]EventType{
+	SystemEventParticipantAdded:   EventParticipantAdded,
+	SystemEventParticipantRemoved: EventParticipantRemoved,
+	SystemEventParticipantLeft:    EventParticipantLeft,
+	SystemEventRoleChanged:        EventParticipantRoleChanged,
+}
+
+// NewMessageCreatedEvent announces the stored message m of conversation conv.
+func NewMessageCreatedEvent(conv Conversation, m Message) Event {
+	return newEvent(EventMessageCreated, conv, m.CreatedAt, eventMessage{
+		ID:             m.ID,
+		ConversationID: m.ConversationID,
+		SenderID:       m.SenderID,
+		CreatedAt:      m.CreatedAt,
+		Body:           m.Body,
+		MsgType:        m.MsgType,
+		AttachmentID:   m.AttachmentID,
+		ReplyToID:      m.ReplyToID,
+		ThreadRootID:   m.ThreadRootID,
+		Seq:            m.Seq,
+	})
+}
+
+// NewConversationCreatedEvent announces the new conversation conv and its initial participants.
+func NewConversationCreatedEvent(conv Conversation, participants []Participant) Event {
+	members := make([]eventParticipant, 0, len(participants))
+	for _, p := range participants {
+		members = append(members, eventParticipant{UserID: p.UserID, Role: p.Role.String()})
+	}
+	return newEvent(EventConversationCreated, conv, conv.CreatedAt, eventConversation{
+		ID:            conv.ID,
+		Kind:          conv.Kind.String(),
+		Title:         conv.Title,
+		AvatarURL:     conv.AvatarURL,
+		HistoryHidden: conv.HistoryHidden,
+		CreatedAt:     conv.CreatedAt,
+		Participants:  members,
+	})
+}
+
+// NewMembershipEvent announces the membership change recorded by the stored system message m of conv.
+// It reports false for messages recording anything else.
+func NewMembershipEvent(conv Conversation, m Message) (Event, bool) {
+	e, ok := SystemEventOf(m)
+	if !ok {
+		return Event{}, false
+	}
+	t, ok := membershipEvents[e.Event]
+	if !ok {
+		return Event{}, false
+	}
+	return newEvent(t, conv, m.CreatedAt, eventMembership{
+		MessageID: m.ID,
+		ActorID:   e.ActorID,
+		UserID:    e.UserID,
+		Role:      e.Role,
+		Seq:       m.Seq,
+	}), true
+}
+
+func newEvent(t EventType, conv Conversation, at time.Time, data any) Event {
+	// The detail types hold plain values only, so marshalling cannot fail
+	raw, _ := json.Marshal(data)
+	return Event{
+		ID:             uuid.NewString(),
+		Type:           t,
+		TenantID:       conv.TenantID,
+		ConversationID: conv.ID,
+		OccurredAt:     at.UTC(),
+		Data:           raw,
+	}
+}
@@ -0,0 +1,11 @@
+package chat
+
+// InboxEntry is one conversation of a user's inbox, as seen by that user.
+type InboxEntry struct {
+	Conversation Conversation
+	Membership   Participant // the user's own row: role, read watermark, mute state
+	LastMessage  *Message    // latest top-level message; nil when there is none or the user may not see it
+	UnreadCount  int
+	OtherIDs     []string // the other participants, longest-standing first; may be a sample of large groups
+	MemberCount  int      // every participant, the user included
+}
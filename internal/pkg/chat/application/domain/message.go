@@ -17,7 +17,12 @@ const (
 	MessageTypeSystem MessageType = 3
 )
 
-// Message is an immutable log entry in a conversation
+// Message is an entry in a conversation log. Entries are never removed: the sender may edit
+// the body, and a deleted message stays as a tombstone with its content blanked.
+// ReplyToID quotes another message; ThreadRootID places the message in the thread under that
+// top-level message, which in turn tracks ReplyCount and LastReplyAt.
+// Seq is the message's number in the conversation's sequence and ChangeSeq the number of its
+// latest change; both are assigned by the repository.
 type Message struct {
 	ID             string      `db:"id"`
 	ConversationID string      `db:"conversation_id"`
@@ -27,7 +32,36 @@ type Message struct {
 	MsgType        MessageType `db:"msg_type"`
 	AttachmentURL  *string     `db:"attachment_url"`
 	AttachmentMeta *string     `db:"attachment_meta"` // JSON string; nil if absent
+	AttachmentID   *string     `db:"attachment_id"`   // uploaded attachment behind AttachmentURL, if any
 	DedupeKey      *string     `db:"dedupe_key"`
+	EditedAt       *time.Time  `db:"edited_at"`
+	DeletedAt      *time.Time  `db:"deleted_at"`
+	DeletedBy      *string     `db:"deleted_by"`
+	ReplyToID      *string     `db:"reply_to_id"`
+	ThreadRootID   *string     `db:"thread_root_id"`
+	ReplyCount     int         `db:"thread_reply_count"`
+	LastReplyAt    *time.Time  `db:"thread_last_reply_at"`
+	Seq            int64       `db:"seq"`
+	ChangeSeq      int64       `db:"change_seq"`
+}
+
+// IsDeleted tells whether the message has been deleted for everyone.
+func (m Message) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
+// IsThreadReply tells whether the message belongs to a thread rather than the main timeline.
+func (m Message) IsThreadReply() bool {
+	return m.ThreadRootID != nil
+}
+
+// MessageEdit keeps the body a message had before an edit (chat.message_edit).
+type MessageEdit struct {
+	ID           string    `db:"id"`
+	MessageID    string    `db:"message_id"`
+	EditorID     string    `db:"editor_id"`
+	PreviousBody *string   `db:"previous_body"`
+	EditedAt     time.Time `db:"edited_at"`
 }
 
 func NewMessage(m Message) (*Message, error) {
@@ -0,0 +1,74 @@
+package chat
+
+import (
+	"strings"
+	"time"
+)
+
+// NotificationLevel expresses which messages of a conversation a participant is notified about
+// 0 = all (default), 1 = mentions only, 2 = none
+type NotificationLevel int16
+
+const (
+	NotificationLevelAll      NotificationLevel = 0
+	NotificationLevelMentions NotificationLevel = 1
+	NotificationLevelNone     NotificationLevel = 2
+)
+
+// MutedIndefinitely is the MutedUntil of a mute without an end.
+var MutedIndefinitely = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// ParseNotificationLevel maps the API representation ("all", "mentions", "none") to a level.
+func ParseNotificationLevel(s string) (NotificationLevel, error) {
+	switch s {
+	case "all":
+		return NotificationLevelAll, nil
+	case "mentions":
+		return NotificationLevelMentions, nil
+	case "none":
+		return NotificationLevelNone, nil
+	}
+	return 0, ErrInvalidNotificationLevel
+}
+
+// String returns the API representation of the level.
+func (l NotificationLevel) String() string {
+	switch l {
+	case NotificationLevelMentions:
+		return "mentions"
+	case NotificationLevelNone:
+		return "none"
+	default:
+		return "all"
+	}
+}
+
+// Valid tells whether l is a known level.
+func (l NotificationLevel) Valid() bool {
+	return l >= NotificationLevelAll && l <= NotificationLevelNone
+}
+
+// Mentions tells whether the body of m mentions userID, written as "@<userId>".
+func (m Message) Mentions(userID string) bool {
+	if m.Body == nil || userID == "" {
+		return false
+	}
+	return strings.Contains(strings.ToLower(*m.Body), "@"+strings.ToLower(userID))
+}
+
+// WantsNotification tells whether the participant is to be notified of m at time t, e.g. with a push.
+// Senders are never notified of their own messages, nor are participants who muted the conversation;
+// system events and tombstones notify nobody. Every message still reaches the inbox and open sessions.
+func (p Participant) WantsNotification(m Message, t time.Time) bool {
+	if m.SenderID == p.UserID || m.MsgType == MessageTypeSystem || m.IsDeleted() || p.IsMutedAt(t) {
+		return false
+	}
+	switch p.NotifyLevel {
+	case NotificationLevelAll:
+		return true
+	case NotificationLevelMentions:
+		return m.Mentions(p.UserID)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,46 @@
+package chat
+
+import "time"
+
+// OutboxKind names the change an outbox entry announces.
+type OutboxKind int16
+
+const (
+	OutboxMessageCreated      OutboxKind = 0
+	OutboxConversationCreated OutboxKind = 1
+)
+
+const (
+	// MaxOutboxAttempts bounds the attempts at publishing an entry before it is dead-lettered.
+	MaxOutboxAttempts = 12
+	// OutboxClaimLease is how long a relay holds the entry it is publishing, and with it the entry's
+	// conversation. Publishing has to end well within it, or another relay may publish the entry again.
+	OutboxClaimLease = time.Minute
+
+	outboxRetryBase = time.Second
+	outboxRetryMax  = 5 * time.Minute
+)
+
+// OutboxEntry is a stored change waiting to be published. Entries are written in the transaction storing the
+// change, so none is lost, and published at least once. EventID is the id of the events announcing the entry,
+// stable across republishing so receivers can discard duplicates.
+type OutboxEntry struct {
+	ID             int64
+	EventID        string
+	Kind           OutboxKind
+	ConversationID string
+	MessageID      *string // OutboxMessageCreated only
+	CreatedAt      time.Time
+	Attempts       int // failed publishing attempts so far
+}
+
+// OutboxRetryDelay is the wait after the given number of failed publishing attempts: it doubles from 1s up to 5m.
+// Later entries of the conversation wait as well, so they are not published out of order, until the entry is
+// published or dead-lettered after MaxOutboxAttempts attempts.
+func OutboxRetryDelay(attempts int) time.Duration {
+	d := outboxRetryBase
+	for i := 1; i < attempts && d < outboxRetryMax; i++ {
+		d *= 2
+	}
+	return min(d, outboxRetryMax)
+}
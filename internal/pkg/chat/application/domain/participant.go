@@ -3,19 +3,69 @@ package chat
 import "time"
 
 // ParticipantRole expresses the role within a conversation
-// 0 = member (default); extra values reserved for future group roles
+// 0 = member (default), 1 = admin, 2 = owner; roles are ordered by privilege
 type ParticipantRole int16
 
 const (
 	ParticipantRoleMember ParticipantRole = 0
+	ParticipantRoleAdmin  ParticipantRole = 1
+	ParticipantRoleOwner  ParticipantRole = 2
 )
 
+// ParseParticipantRole maps the API representation ("member", "admin", "owner") to a role.
+func ParseParticipantRole(s string) (ParticipantRole, error) {
+	switch s {
+	case "member":
+		return ParticipantRoleMember, nil
+	case "admin":
+		return ParticipantRoleAdmin, nil
+	case "owner":
+		return ParticipantRoleOwner, nil
+	}
+	return 0, ErrInvalidRole
+}
+
+// String returns the API representation of the role.
+func (r ParticipantRole) String() string {
+	switch r {
+	case ParticipantRoleAdmin:
+		return "admin"
+	case ParticipantRoleOwner:
+		return "owner"
+	default:
+		return "member"
+	}
+}
+
+// Valid tells whether r is a known role.
+func (r ParticipantRole) Valid() bool {
+	return r >= ParticipantRoleMember && r <= ParticipantRoleOwner
+}
+
+// CanManage tells whether the role may administer the group (membership, roles, profile).
+func (r ParticipantRole) CanManage() bool {
+	return r >= ParticipantRoleAdmin
+}
+
 // Participant captures membership and read/mute state
 // Primary key: (ConversationID, UserID)
 type Participant struct {
-	ConversationID string          `db:"conversation_id"`
-	UserID         string          `db:"user_id"`
-	Role           ParticipantRole `db:"role"`
-	LastReadMsg    *string         `db:"last_read_msg"`
-	MutedUntil     *time.Time      `db:"muted_until"`
+	ConversationID string            `db:"conversation_id"`
+	UserID         string            `db:"user_id"`
+	Role           ParticipantRole   `db:"role"`
+	LastReadMsg    *string           `db:"last_read_msg"`
+	MutedUntil     *time.Time        `db:"muted_until"`
+	JoinedAt       time.Time         `db:"joined_at"`
+	NotifyLevel    NotificationLevel `db:"notify_level"`
+}
+
+// IsMutedAt tells whether the participant has muted the conversation at time t.
+// Muting silences notifications at every level; it does not hide messages.
+func (p Participant) IsMutedAt(t time.Time) bool {
+	return p.MutedUntil != nil && p.MutedUntil.After(t)
+}
+
+// IsMutedIndefinitely tells whether the participant muted the conversation without an end.
+func (p Participant) IsMutedIndefinitely() bool {
+	return p.MutedUntil != nil && !p.MutedUntil.Before(MutedIndefinitely)
 }
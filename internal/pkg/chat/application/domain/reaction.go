@@ -0,0 +1,112 @@
+package chat
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxReactionEmojisPerMessage caps how many distinct emojis a message can collect.
+	MaxReactionEmojisPerMessage = 20
+	// MaxReactionsPerUser caps how many emojis one user can put on a single message.
+	MaxReactionsPerUser = 5
+	// maxEmojiLength bounds an emoji in bytes; it leaves room for skin tones and ZWJ sequences.
+	maxEmojiLength = 64
+)
+
+// Reaction is a user's emoji on a message (chat.reaction)
+// Primary key: (MessageID, UserID, Emoji)
+type Reaction struct {
+	MessageID string    `db:"message_id"`
+	UserID    string    `db:"user_id"`
+	Emoji     string    `db:"emoji"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ReactionCount aggregates the reactions of a message for one emoji.
+// Reacted tells whether the viewer the counts were computed for is among the reactors.
+type ReactionCount struct {
+	Emoji   string
+	Count   int
+	Reacted bool
+}
+
+// NormalizeEmoji trims e and checks that it is a single emoji, modifiers and ZWJ sequences included, or a
+// shortcode such as ":party_parrot:". The server does not keep an emoji catalogue; clients decide what they render.
+func NormalizeEmoji(e string) (string, error) {
+	e = strings.TrimSpace(e)
+	if e == "" || len(e) > maxEmojiLength || !utf8.ValidString(e) {
+		return "", ErrInvalidReaction
+	}
+	if !isShortcode(e) && !isEmoji([]rune(e)) {
+		return "", ErrInvalidReaction
+	}
+	return e, nil
+}
+
+// isShortcode tells whether e is a colon-delimited name of lowercase letters, digits, '_', '+' and '-'.
+func isShortcode(e string) bool {
+	if len(e) < 3 || e[0] != ':' || e[len(e)-1] != ':' {
+		return false
+	}
+	for _, r := range e[1 : len(e)-1] {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '+' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	zeroWidthJoiner = '\u200D'
+	keycapMark      = '\u20E3'
+)
+
+// isEmoji tells whether runes form one emoji: a flag of two regional indicators, a keycap, or pictographs
+// joined by ZWJ, each optionally followed by a variation selector, skin tone or tag characters.
+func isEmoji(runes []rune) bool {
+	if len(runes) == 2 && isRegionalIndicator(runes[0]) && isRegionalIndicator(runes[1]) {
+		return true
+	}
+	if isKeycap(runes) {
+		return true
+	}
+	expectBase := true
+	for _, r := range runes {
+		switch {
+		case expectBase:
+			if !unicode.Is(unicode.So, r) || isRegionalIndicator(r) {
+				return false
+			}
+			expectBase = false
+		case r == zeroWidthJoiner:
+			expectBase = true
+		case !isEmojiModifier(r):
+			return false
+		}
+	}
+	return !expectBase
+}
+
+// isKeycap tells whether runes are a digit, '#' or '*', an optional emoji presentation selector and the keycap mark.
+func isKeycap(runes []rune) bool {
+	if len(runes) < 2 || len(runes) > 3 || runes[len(runes)-1] != keycapMark {
+		return false
+	}
+	if len(runes) == 3 && runes[1] != '\uFE0F' {
+		return false
+	}
+	b := runes[0]
+	return b >= '0' && b <= '9' || b == '#' || b == '*'
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isEmojiModifier tells whether r may follow a pictograph: variation selectors, skin tones and tag characters.
+func isEmojiModifier(r rune) bool {
+	return r == '\uFE0E' || r == '\uFE0F' || r >= 0x1F3FB && r <= 0x1F3FF || r >= 0xE0020 && r <= 0xE007F
+}
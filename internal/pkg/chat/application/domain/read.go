@@ -0,0 +1,14 @@
+package chat
+
+import "time"
+
+// ReadReceipt records that UserID has read a conversation up to and including MessageID.
+// Read state is a per-participant watermark (participant.last_read_msg) that only moves forward.
+// Seq is the number the watermark move took in the conversation's sequence.
+type ReadReceipt struct {
+	ConversationID string
+	UserID         string
+	MessageID      string
+	At             time.Time
+	Seq            int64
+}
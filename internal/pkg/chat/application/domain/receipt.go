@@ -0,0 +1,31 @@
+package chat
+
+import "time"
+
+// ReceiptStatus is the per-recipient state of a message
+// 0 = delivered, 1 = read
+type ReceiptStatus int16
+
+const (
+	ReceiptStatusDelivered ReceiptStatus = 0
+	ReceiptStatusRead      ReceiptStatus = 1
+)
+
+// String returns the API representation of the status.
+func (s ReceiptStatus) String() string {
+	if s == ReceiptStatusRead {
+		return "read"
+	}
+	return "delivered"
+}
+
+// Receipt records that a message reached a recipient (chat.receipt).
+// ConversationID and SenderID are not stored; they route the receipt back to the sender.
+type Receipt struct {
+	MessageID      string        `db:"message_id"`
+	UserID         string        `db:"user_id"`
+	Status         ReceiptStatus `db:"status"`
+	At             time.Time     `db:"at"`
+	ConversationID string        `db:"-"`
+	SenderID       string        `db:"-"`
+}
@@ -0,0 +1,15 @@
+package chat
+
+// Highlight markers delimit the matched terms in a search snippet. They are control characters, which the
+// repository strips from message text before highlighting, so any marker in a snippet is one it placed and
+// presenters can escape a snippet first and then swap the markers for markup.
+const (
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// MessageSearchHit is a message matching a search, with an excerpt of its body around the matched terms.
+type MessageSearchHit struct {
+	Message Message
+	Snippet string
+}
@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SystemEventType names a change recorded in the conversation log as a system message
+type SystemEventType string
+
+const (
+	SystemEventParticipantAdded    SystemEventType = "participant_added"
+	SystemEventParticipantRemoved  SystemEventType = "participant_removed"
+	SystemEventParticipantLeft     SystemEventType = "participant_left"
+	SystemEventRoleChanged         SystemEventType = "role_changed"
+	SystemEventConversationUpdated SystemEventType = "conversation_updated"
+)
+
+// SystemEvent is the JSON body of a MessageTypeSystem message
+type SystemEvent struct {
+	Event     SystemEventType `json:"event"`
+	ActorID   string          `json:"actorId"`
+	UserID    string          `json:"userId,omitempty"`
+	Role      string          `json:"role,omitempty"`
+	Title     *string         `json:"title,omitempty"`
+	AvatarURL *string         `json:"avatarUrl,omitempty"`
+}
+
+// NewSystemMessage builds the system message recording e, authored by the acting user.
+func NewSystemMessage(conversationID string, e SystemEvent, now time.Time) Message {
+	// Marshalling a struct of strings cannot fail
+	raw, _ := json.Marshal(e)
+	body := string(raw)
+	return Message{
+		ConversationID: conversationID,
+		SenderID:       e.ActorID,
+		CreatedAt:      now.UTC(),
+		Body:           &body,
+		MsgType:        MessageTypeSystem,
+	}
+}
+
+// SystemEventOf decodes the change recorded by the system message m. It reports false for other messages.
+func SystemEventOf(m Message) (SystemEvent, bool) {
+	if m.MsgType != MessageTypeSystem || m.Body == nil {
+		return SystemEvent{}, false
+	}
+	var e SystemEvent
+	if err := json.Unmarshal([]byte(*m.Body), &e); err != nil {
+		return SystemEvent{}, false
+	}
+	return e, true
+}
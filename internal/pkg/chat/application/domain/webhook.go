@@ -0,0 +1,166 @@
+package chat
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxWebhookAttempts bounds the deliveries of an event to a subscriber before it is dead-lettered.
+	MaxWebhookAttempts = 8
+	// MaxWebhookURLLength bounds subscriber URLs.
+	MaxWebhookURLLength = 2048
+
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = 6 * time.Hour
+	// WebhookStallGrace is how late a pending attempt may be before it is deemed lost: it is then queued
+	// again by the sweep, and can be replayed.
+	WebhookStallGrace = 15 * time.Minute
+)
+
+// WebhookSubscription is an endpoint of a tenant notified of its conversations' events (chat.webhook_subscription).
+// Events filters the notified changes; empty means every change. Secret signs the deliveries.
+type WebhookSubscription struct {
+	ID          string      `db:"id"`
+	TenantID    string      `db:"tenant_id"`
+	URL         string      `db:"url"`
+	Secret      string      `db:"secret"`
+	Events      []EventType `db:"events"`
+	Description *string     `db:"description"`
+	CreatedAt   time.Time   `db:"created_at"`
+	UpdatedAt   time.Time   `db:"updated_at"`
+}
+
+// Wants tells whether the subscription is notified of events of type t.
+func (s WebhookSubscription) Wants(t EventType) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWebhookURL accepts absolute http and https URLs without credentials.
+func ValidateWebhookURL(raw string) error {
+	if raw == "" || len(raw) > MaxWebhookURLLength {
+		return ErrInvalidWebhookURL
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return ErrInvalidWebhookURL
+	}
+	if s := strings.ToLower(u.Scheme); s != "http" && s != "https" {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// DeliveryStatus is where a webhook delivery stands
+// 0 = pending, 1 = delivered, 2 = dead
+type DeliveryStatus int16
+
+const (
+	DeliveryStatusPending   DeliveryStatus = 0
+	DeliveryStatusDelivered DeliveryStatus = 1
+	DeliveryStatusDead      DeliveryStatus = 2
+)
+
+// ParseDeliveryStatus maps the API representation ("pending", "delivered", "dead") to a status.
+func ParseDeliveryStatus(s string) (DeliveryStatus, error) {
+	switch s {
+	case "pending":
+		return DeliveryStatusPending, nil
+	case "delivered":
+		return DeliveryStatusDelivered, nil
+	case "dead":
+		return DeliveryStatusDead, nil
+	}
+	return 0, ErrInvalidDeliveryStatus
+}
+
+// String returns the API representation of the status.
+func (s DeliveryStatus) String() string {
+	switch s {
+	case DeliveryStatusDelivered:
+		return "delivered"
+	case DeliveryStatusDead:
+		return "dead"
+	default:
+		return "pending"
+	}
+}
+
+// WebhookDelivery is an event on its way to one subscription (chat.webhook_delivery). Payload is the exact
+// request body, kept so that retries and replays send what the first attempt sent.
+type WebhookDelivery struct {
+	ID             string         `db:"id"`
+	SubscriptionID string         `db:"subscription_id"`
+	EventID        string         `db:"event_id"`
+	EventType      EventType      `db:"event_type"`
+	Payload        []byte         `db:"payload"`
+	Status         DeliveryStatus `db:"status"`
+	Attempts       int            `db:"attempts"`
+	LastStatusCode *int           `db:"last_status_code"` // HTTP status of the latest attempt, if it got an answer
+	LastError      *string        `db:"last_error"`
+	NextAttemptAt  *time.Time     `db:"next_attempt_at"` // pending deliveries only
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+	DeliveredAt    *time.Time     `db:"delivered_at"`
+}
+
+// WebhookRetryDelay is the wait after the given number of failed attempts: it doubles from 30s up to 6h.
+func WebhookRetryDelay(attempts int) time.Duration {
+	d := webhookRetryBase
+	for i := 1; i < attempts && d < webhookRetryMax; i++ {
+		d *= 2
+	}
+	return min(d, webhookRetryMax)
+}
+
+// RecordSuccess marks the delivery as acknowledged by the subscriber with statusCode.
+func (d *WebhookDelivery) RecordSuccess(statusCode int, now time.Time) {
+	now = now.UTC()
+	d.Attempts++
+	d.Status = DeliveryStatusDelivered
+	d.LastStatusCode = &statusCode
+	d.LastError = nil
+	d.NextAttemptAt = nil
+	d.DeliveredAt = &now
+	d.UpdatedAt = now
+}
+
+// RecordFailure counts a failed attempt, answered with statusCode when not nil. The delivery is retried after
+// WebhookRetryDelay, or dead-lettered once MaxWebhookAttempts attempts failed.
+func (d *WebhookDelivery) RecordFailure(statusCode *int, reason string, now time.Time) {
+	now = now.UTC()
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = &reason
+	d.UpdatedAt = now
+	if d.Attempts >= MaxWebhookAttempts {
+		d.Status = DeliveryStatusDead
+		d.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(WebhookRetryDelay(d.Attempts))
+	d.NextAttemptAt = &next
+}
+
+// Replay sends a finished delivery again, with a fresh allowance of attempts. Pending deliveries are still
+// being attempted and cannot be replayed, unless their next attempt is so overdue that it must have been lost.
+func (d *WebhookDelivery) Replay(now time.Time) error {
+	if d.Status == DeliveryStatusPending && (d.NextAttemptAt == nil || now.Sub(*d.NextAttemptAt) < WebhookStallGrace) {
+		return ErrDeliveryPending
+	}
+	now = now.UTC()
+	d.Status = DeliveryStatusPending
+	d.Attempts = 0
+	d.NextAttemptAt = &now
+	d.UpdatedAt = now
+	return nil
+}
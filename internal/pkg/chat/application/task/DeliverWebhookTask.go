@@ -0,0 +1,80 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	webhookport "go-chatty/internal/infrastructure/webhook/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DeliverWebhookTaskType is the queue task name for one attempt at delivering an event to a webhook subscriber.
+const DeliverWebhookTaskType = "chat:deliver_webhook"
+
+// DeliverWebhookTaskPayload is the JSON payload transported via the queue. Attempt is the number of attempts
+// the delivery had when the task was queued, which tells stale tasks apart.
+type DeliverWebhookTaskPayload struct {
+	DeliveryID string `json:"deliveryId"`
+	Attempt    int    `json:"attempt"`
+}
+
+// EnqueueWebhookDelivery queues the next attempt of the pending delivery d, due at its NextAttemptAt.
+func EnqueueWebhookDelivery(ctx context.Context, client qport.Client, d chat.WebhookDelivery) error {
+	b, err := json.Marshal(DeliverWebhookTaskPayload{DeliveryID: d.ID, Attempt: d.Attempts})
+	if err != nil {
+		return err
+	}
+	opts := qport.EnqueueOption{Queue: "chat", MaxRetry: 3, UniqueTTL: 10 * time.Minute}
+	if d.NextAttemptAt != nil && d.NextAttemptAt.After(time.Now()) {
+		opts.ProcessAt = *d.NextAttemptAt
+		// The lock has to outlive the wait, or the same attempt could be queued twice
+		opts.UniqueTTL += time.Until(*d.NextAttemptAt)
+	}
+	_, err = client.Enqueue(ctx, qport.Task{Type: DeliverWebhookTaskType, Payload: b}, opts)
+	if errors.Is(err, qport.ErrDuplicate) {
+		return nil
+	}
+	return err
+}
+
+// RegisterDeliverWebhookTask binds the task handler to the provided server.
+// The handler runs the DeliverWebhookUseCase through sender and queues the next attempt of deliveries that
+// failed but are not dead yet. Retries are scheduled by the use case's backoff rather than by the queue,
+// which only retries the handler when the database is unavailable.
+func RegisterDeliverWebhookTask(srv qport.Server, pool *pgxpool.Pool, client qport.Client, sender webhookport.Sender) {
+	srv.Register(DeliverWebhookTaskType, func(ctx context.Context, t qport.Task) error {
+		var p DeliverWebhookTaskPayload
+		if err := json.Unmarshal(t.Payload, &p); err != nil {
+			// malformed payload: do not retry indefinitely
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		uc := usecase.NewDeliverWebhookUseCase(repoAdapter.NewPgWebhookRepository(pool), sender)
+		out, err := uc.Execute(ctx, usecase.DeliverWebhookInput{DeliveryID: p.DeliveryID, Attempt: p.Attempt})
+		if err != nil {
+			return err
+		}
+		d := out.Delivery
+		if d == nil || d.Status != chat.DeliveryStatusPending {
+			return nil
+		}
+		// The attempt is recorded: retrying this task would be skipped as stale. A lost retry leaves the
+		// delivery overdue, and the WebhookSweeper queues it again.
+		if err := EnqueueWebhookDelivery(ctx, client, *d); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "chat: enqueue retry of webhook delivery %s: %v\n", d.ID, err)
+		}
+		return nil
+	})
+}
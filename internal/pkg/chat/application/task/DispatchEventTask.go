@@ -0,0 +1,80 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DispatchEventTaskType is the queue task name for turning a published event into webhook deliveries.
+const DispatchEventTaskType = "chat:dispatch_event"
+
+// DispatchEventTaskPayload is the JSON payload transported via the queue.
+// Kept decoupled from domain types to avoid tight coupling with JSON tags.
+type DispatchEventTaskPayload struct {
+	ID             string          `json:"id"`
+	Type           string          `json:"type"`
+	TenantID       string          `json:"tenantId,omitempty"`
+	ConversationID string          `json:"conversationId"`
+	OccurredAt     time.Time       `json:"occurredAt"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// EnqueueDispatchEvent queues the dispatch of e to the webhook subscriptions of its tenant.
+func EnqueueDispatchEvent(ctx context.Context, client qport.Client, e chat.Event) error {
+	b, err := json.Marshal(DispatchEventTaskPayload{
+		ID:             e.ID,
+		Type:           string(e.Type),
+		TenantID:       e.TenantID,
+		ConversationID: e.ConversationID,
+		OccurredAt:     e.OccurredAt,
+		Data:           e.Data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.Enqueue(ctx, qport.Task{Type: DispatchEventTaskType, Payload: b}, qport.EnqueueOption{Queue: "chat", MaxRetry: 10})
+	return err
+}
+
+// RegisterDispatchEventTask binds the task handler to the provided server.
+// The handler runs the DispatchEventUseCase and queues the first attempt of every pending delivery through client.
+func RegisterDispatchEventTask(srv qport.Server, pool *pgxpool.Pool, client qport.Client) {
+	srv.Register(DispatchEventTaskType, func(ctx context.Context, t qport.Task) error {
+		var p DispatchEventTaskPayload
+		if err := json.Unmarshal(t.Payload, &p); err != nil {
+			// malformed payload: do not retry indefinitely
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		uc := usecase.NewDispatchEventUseCase(repoAdapter.NewPgWebhookRepository(pool))
+		out, err := uc.Execute(ctx, usecase.DispatchEventInput{Event: chat.Event{
+			ID:             p.ID,
+			Type:           chat.EventType(p.Type),
+			TenantID:       p.TenantID,
+			ConversationID: p.ConversationID,
+			OccurredAt:     p.OccurredAt,
+			Data:           p.Data,
+		}})
+		if err != nil {
+			return err
+		}
+		// A retry finds the deliveries recorded and queues them again; duplicate attempts are skipped
+		for _, d := range out.Pending {
+			if err := EnqueueWebhookDelivery(ctx, client, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
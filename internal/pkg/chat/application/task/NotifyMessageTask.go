@@ -0,0 +1,79 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NotifyMessageTaskType is the queue task name for fanning the push notifications of a new message out to its recipients.
+const NotifyMessageTaskType = "chat:notify_message"
+
+// NotifyMessageTaskPayload is the JSON payload transported via the queue.
+type NotifyMessageTaskPayload struct {
+	MessageID string `json:"messageId"`
+}
+
+// EnqueueNotifyMessage queues the push fan-out of msg once it is stored and broadcast. System messages notify
+// nobody and are ignored.
+func EnqueueNotifyMessage(ctx context.Context, client qport.Client, msg chat.Message) error {
+	if msg.MsgType == chat.MessageTypeSystem {
+		return nil
+	}
+	b, err := json.Marshal(NotifyMessageTaskPayload{MessageID: msg.ID})
+	if err != nil {
+		return err
+	}
+	// Retried sends of the same message must not notify twice
+	opts := qport.EnqueueOption{Queue: "chat", MaxRetry: 5, UniqueTTL: 10 * time.Minute}
+	_, err = client.Enqueue(ctx, qport.Task{Type: NotifyMessageTaskType, Payload: b}, opts)
+	if errors.Is(err, qport.ErrDuplicate) {
+		return nil
+	}
+	return err
+}
+
+// RegisterNotifyMessageTask binds the task handler to the provided server.
+// The handler picks the participants who want to be notified of the message and queues a PushNotificationTask
+// for each through client. Pushes to a recipient are held back for collapseWindow so a burst in a conversation
+// ends up as one; by then, recipients with an open session have a delivery receipt and are skipped.
+func RegisterNotifyMessageTask(srv qport.Server, pool *pgxpool.Pool, client qport.Client, collapseWindow time.Duration) {
+	srv.Register(NotifyMessageTaskType, func(ctx context.Context, t qport.Task) error {
+		var p NotifyMessageTaskPayload
+		if err := json.Unmarshal(t.Payload, &p); err != nil {
+			// malformed payload: do not retry indefinitely
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		uc := usecase.NewListNotificationRecipientsUseCase(repoAdapter.NewPgChatRepository(pool))
+		out, err := uc.Execute(ctx, usecase.ListNotificationRecipientsInput{MessageID: p.MessageID})
+		if errors.Is(err, chat.ErrMessageNotFound) || errors.Is(err, chat.ErrNotParticipant) {
+			// The message or its conversation is gone; there is nothing left to notify about
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(out.Recipients) == 0 {
+			return nil
+		}
+
+		for _, r := range out.Recipients {
+			if err := EnqueuePushNotification(ctx, client, r.UserID, out.Message.ConversationID, collapseWindow); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
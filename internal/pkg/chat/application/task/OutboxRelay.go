@@ -0,0 +1,208 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// outboxBatchSize bounds the conversations picked per round, and the entries relayed per conversation
+	outboxBatchSize      = 100
+	outboxWorkers        = 4
+	outboxPollInterval   = time.Second
+	outboxPublishTimeout = 10 * time.Second
+	outboxListenBackoff  = 5 * time.Second
+)
+
+// MessagePublisher announces new messages to the open sessions that should see them.
+type MessagePublisher interface {
+	MessageCreated(ctx context.Context, msg chat.Message, threadRoot *chat.Message) error
+}
+
+// OutboxRelay publishes the changes stored with an outbox entry: new messages to open sessions through
+// publisher, and to attachment processing, push notifications and webhooks through queue tasks. It wakes up
+// as soon as entries are queued, and polls for retries and for entries queued while it was not listening.
+//
+// Every replica runs a relay, which works on several conversations at once; the repository lets a single
+// relay publish a conversation's entries at a time, which keeps them in order. Each entry is committed on its
+// own, so a slow or failing entry holds back its conversation only, and one failing chat.MaxOutboxAttempts
+// times is dead-lettered. Delivery is at least once: an entry that failed half-way is published again in
+// full, so sessions may see a message twice and tell by its id.
+//
+// Webhook events leave the relay in order, but each delivery is then sent and retried on its own, so
+// subscribers may receive the events of a conversation out of order; they carry its sequence number.
+type OutboxRelay struct {
+	outbox    repository.OutboxRepository
+	resolveUC *usecase.ResolveOutboxEntryUseCase
+	client    qport.Client
+	publisher MessagePublisher
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewOutboxRelay constructs a relay that enqueues through client. Call Start to run it and Close on shutdown.
+func NewOutboxRelay(pool *pgxpool.Pool, client qport.Client, publisher MessagePublisher) *OutboxRelay {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxRelay{
+		outbox:    repoAdapter.NewPgOutboxRepository(pool),
+		resolveUC: usecase.NewResolveOutboxEntryUseCase(repoAdapter.NewPgChatRepository(pool)),
+		client:    client,
+		publisher: publisher,
+		wake:      make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Start launches the listen and relay loops.
+func (r *OutboxRelay) Start() {
+	r.wg.Add(2)
+	go r.listen()
+	go r.loop()
+}
+
+// Close stops both loops. Entries interrupted half-way stay queued and are published again.
+func (r *OutboxRelay) Close() {
+	r.once.Do(func() {
+		r.cancel()
+		r.wg.Wait()
+	})
+}
+
+func (r *OutboxRelay) listen() {
+	defer r.wg.Done()
+	for {
+		err := r.outbox.ListenOutbox(r.ctx, r.kick)
+		if r.ctx.Err() != nil {
+			return
+		}
+		// Polling carries on meanwhile
+		_, _ = fmt.Fprintf(os.Stderr, "chat: listen for outbox entries: %v\n", err)
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(outboxListenBackoff):
+		}
+	}
+}
+
+func (r *OutboxRelay) kick() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *OutboxRelay) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.relay()
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// relay works through the conversations with due entries, outboxWorkers at a time, until a round
+// makes no progress: nothing is due, or the other replicas hold what is.
+func (r *OutboxRelay) relay() {
+	for r.ctx.Err() == nil {
+		ids, err := r.outbox.ListOutboxConversations(r.ctx, outboxBatchSize)
+		if err != nil {
+			if r.ctx.Err() == nil {
+				_, _ = fmt.Fprintf(os.Stderr, "chat: list outbox conversations: %v\n", err)
+			}
+			return
+		}
+
+		var (
+			wg       sync.WaitGroup
+			progress atomic.Bool
+		)
+		slots := make(chan struct{}, outboxWorkers)
+		for _, id := range ids {
+			slots <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+				n, err := r.outbox.RelayConversationOutbox(r.ctx, id, outboxBatchSize, r.publishWithin)
+				if err != nil && r.ctx.Err() == nil {
+					_, _ = fmt.Fprintf(os.Stderr, "chat: relay outbox of conversation %s: %v\n", id, err)
+				}
+				if n > 0 {
+					progress.Store(true)
+				}
+			}()
+		}
+		wg.Wait()
+		if !progress.Load() {
+			return
+		}
+	}
+}
+
+// publishWithin is publish bounded by outboxPublishTimeout, well within chat.OutboxClaimLease: an entry
+// taking longer counts as failed.
+func (r *OutboxRelay) publishWithin(ctx context.Context, e chat.OutboxEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	defer cancel()
+	return r.publish(ctx, e)
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, e chat.OutboxEntry) error {
+	out, err := r.resolveUC.Execute(ctx, usecase.ResolveOutboxEntryInput{Entry: e})
+	if errors.Is(err, chat.ErrNotParticipant) || errors.Is(err, chat.ErrMessageNotFound) {
+		// Removed before it was published: there is nothing left to announce
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if out.Message != nil {
+		if err := r.publisher.MessageCreated(ctx, *out.Message, out.ThreadRoot); err != nil {
+			return err
+		}
+		// Attachments are inspected in the background; the room hears about the results as message_updated
+		if err := EnqueueProcessAttachment(ctx, r.client, *out.Message); err != nil {
+			return err
+		}
+		// Recipients without an open session are reached by push
+		if err := EnqueueNotifyMessage(ctx, r.client, *out.Message); err != nil {
+			return err
+		}
+	}
+	for _, event := range out.Events {
+		if err := EnqueueDispatchEvent(ctx, r.client, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	"go-chatty/internal/infrastructure/realtime"
+)
+
+const (
+	presenceBufferSize     = 1024
+	presenceEnqueueTimeout = 3 * time.Second
+)
+
+// PresenceNotifier implements realtime.PresenceListener. Changes are handed to a background loop
+// that enqueues one PresenceTask each, so the router never waits on the queue.
+//
+// Notifications are best-effort: when the buffer is full or enqueueing fails the change is dropped
+// and logged; clients can still look presence up over HTTP.
+type PresenceNotifier struct {
+	client  qport.Client
+	changes chan realtime.Presence
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewPresenceNotifier constructs a notifier that enqueues through client. Call Start before use and Close on shutdown.
+func NewPresenceNotifier(client qport.Client) *PresenceNotifier {
+	return &PresenceNotifier{
+		client:  client,
+		changes: make(chan realtime.Presence, presenceBufferSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Ensure interface compliance at compile time
+var _ realtime.PresenceListener = (*PresenceNotifier)(nil)
+
+// PresenceChanged queues p for announcement, dropping it if the buffer is full or the notifier is closed.
+func (n *PresenceNotifier) PresenceChanged(p realtime.Presence) {
+	select {
+	case <-n.stop:
+		return
+	default:
+	}
+	select {
+	case n.changes <- p:
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "chat: presence buffer full, dropping change of %s\n", p.UserID)
+	}
+}
+
+// Start launches the enqueue loop.
+func (n *PresenceNotifier) Start() {
+	go n.loop()
+}
+
+// Close stops the loop after enqueueing the changes already buffered.
+func (n *PresenceNotifier) Close() {
+	n.once.Do(func() {
+		close(n.stop)
+		<-n.done
+	})
+}
+
+func (n *PresenceNotifier) loop() {
+	defer close(n.done)
+	for {
+		select {
+		case p := <-n.changes:
+			n.announce(p)
+		case <-n.stop:
+			for {
+				select {
+				case p := <-n.changes:
+					n.announce(p)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (n *PresenceNotifier) announce(p realtime.Presence) {
+	if err := n.enqueue(p); err != nil {
+		// Best-effort log to stderr without introducing logging deps
+		_, _ = fmt.Fprintf(os.Stderr, "chat: enqueue presence of %s: %v\n", p.UserID, err)
+	}
+}
+
+func (n *PresenceNotifier) enqueue(p realtime.Presence) error {
+	payload, err := json.Marshal(PresenceTaskPayload{UserID: p.UserID, Status: string(p.Status), LastSeen: p.LastSeen})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceEnqueueTimeout)
+	defer cancel()
+
+	opts := qport.EnqueueOption{Queue: "chat", MaxRetry: 3}
+	_, err = n.client.Enqueue(ctx, qport.Task{Type: PresenceTaskType, Payload: payload}, opts)
+	return err
+}
@@ -0,0 +1,62 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PresenceTaskType is the queue task name for announcing a presence change to a user's contacts.
+const PresenceTaskType = "chat:presence_changed"
+
+// PresenceTaskPayload is the JSON payload transported via the queue.
+type PresenceTaskPayload struct {
+	UserID   string     `json:"userId"`
+	Status   string     `json:"status"`
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// presenceFrame is the websocket frame sent to the contacts of a user whose presence changed.
+type presenceFrame struct {
+	Type     string     `json:"type"`
+	UserID   string     `json:"userId"`
+	Status   string     `json:"status"`
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// RegisterPresenceTask binds the task handler to the provided server.
+// The change is pushed through router to every session of the user's presence audience.
+func RegisterPresenceTask(srv qport.Server, pool *pgxpool.Pool, router *realtime.Router) {
+	srv.Register(PresenceTaskType, func(ctx context.Context, t qport.Task) error {
+		var p PresenceTaskPayload
+		if err := json.Unmarshal(t.Payload, &p); err != nil {
+			// malformed payload: do not retry indefinitely
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		repo := repoAdapter.NewPgChatRepository(pool)
+		audience, err := usecase.NewListPresenceAudienceUseCase(repo).Execute(ctx, usecase.ListPresenceAudienceInput{UserID: p.UserID})
+		if err != nil {
+			return err
+		}
+
+		frame, err := json.Marshal(presenceFrame{Type: "presence", UserID: p.UserID, Status: p.Status, LastSeen: p.LastSeen})
+		if err != nil {
+			return err
+		}
+		for _, userID := range audience {
+			router.NotifyUser(userID, frame)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,89 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	mediaport "go-chatty/internal/infrastructure/media/port"
+	qport "go-chatty/internal/infrastructure/queue/port"
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProcessAttachmentTaskType is the queue task name for inspecting an uploaded attachment.
+const ProcessAttachmentTaskType = "chat:process_attachment"
+
+// ProcessAttachmentTaskPayload is the JSON payload transported via the queue.
+type ProcessAttachmentTaskPayload struct {
+	AttachmentID string `json:"attachmentId"`
+}
+
+// MessageUpdateNotifier announces a message whose content changed in the background.
+type MessageUpdateNotifier interface {
+	MessageUpdated(ctx context.Context, msg chat.Message) error
+}
+
+// EnqueueAttachmentProcessing queues the processing of an attachment once its upload is known to have landed.
+func EnqueueAttachmentProcessing(ctx context.Context, client qport.Client, attachmentID string) error {
+	b, err := json.Marshal(ProcessAttachmentTaskPayload{AttachmentID: attachmentID})
+	if err != nil {
+		return err
+	}
+	// Repeated completions and retried sends must not queue the work twice while it is pending
+	opts := qport.EnqueueOption{Queue: "chat", MaxRetry: 5, UniqueTTL: 10 * time.Minute}
+	_, err = client.Enqueue(ctx, qport.Task{Type: ProcessAttachmentTaskType, Payload: b}, opts)
+	if errors.Is(err, qport.ErrDuplicate) {
+		return nil
+	}
+	return err
+}
+
+// EnqueueProcessAttachment queues the processing of the attachment carried by msg; messages without one are ignored.
+// Clients that never reported their upload complete get it processed here, and a message sent while processing
+// ran gets the results it missed.
+func EnqueueProcessAttachment(ctx context.Context, client qport.Client, msg chat.Message) error {
+	if msg.AttachmentID == nil {
+		return nil
+	}
+	return EnqueueAttachmentProcessing(ctx, client, *msg.AttachmentID)
+}
+
+// RegisterProcessAttachmentTask binds the task handler to the provided server.
+// The handler runs the ProcessAttachmentUseCase and, when a message carries the attachment, announces
+// its refreshed metadata through notifier.
+func RegisterProcessAttachmentTask(srv qport.Server, pool *pgxpool.Pool, storage storageport.Storage, analyzer mediaport.Analyzer, policy storageport.Policy, notifier MessageUpdateNotifier) {
+	srv.Register(ProcessAttachmentTaskType, func(ctx context.Context, t qport.Task) error {
+		var p ProcessAttachmentTaskPayload
+		if err := json.Unmarshal(t.Payload, &p); err != nil {
+			// malformed payload: do not retry indefinitely
+			return err
+		}
+
+		// Downloading and decoding a large image takes a while
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+
+		uc := usecase.NewProcessAttachmentUseCase(repoAdapter.NewPgChatRepository(pool), storage, analyzer, policy)
+		out, err := uc.Execute(ctx, usecase.ProcessAttachmentInput{AttachmentID: p.AttachmentID})
+		if err != nil {
+			return err
+		}
+		if out.Message == nil {
+			return nil
+		}
+
+		// The results are stored: a failed announcement is not worth processing the file again
+		if err := notifier.MessageUpdated(ctx, *out.Message); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "chat: announce processed attachment of %s: %v\n", out.Message.ID, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,61 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	pushport "go-chatty/internal/infrastructure/push/port"
+	qport "go-chatty/internal/infrastructure/queue/port"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PushNotificationTaskType is the queue task name for pushing a user's news in one conversation to their devices.
+const PushNotificationTaskType = "chat:push_notification"
+
+// PushNotificationTaskPayload is the JSON payload transported via the queue. It deliberately names no message:
+// identical payloads are what collapses a burst into a single task.
+type PushNotificationTaskPayload struct {
+	UserID         string `json:"userId"`
+	ConversationID string `json:"conversationId"`
+}
+
+// EnqueuePushNotification queues a push to userID about conversationID to run after collapseWindow. While
+// one is pending, further requests for the same user and conversation are absorbed by it.
+func EnqueuePushNotification(ctx context.Context, client qport.Client, userID string, conversationID string, collapseWindow time.Duration) error {
+	b, err := json.Marshal(PushNotificationTaskPayload{UserID: userID, ConversationID: conversationID})
+	if err != nil {
+		return err
+	}
+	// The lock outlives the window so the pending task absorbs requests until it ran; asynq releases it on success
+	opts := qport.EnqueueOption{Queue: "chat", MaxRetry: 5, ProcessIn: collapseWindow, UniqueTTL: collapseWindow + time.Minute}
+	_, err = client.Enqueue(ctx, qport.Task{Type: PushNotificationTaskType, Payload: b}, opts)
+	if errors.Is(err, qport.ErrDuplicate) {
+		return nil
+	}
+	return err
+}
+
+// RegisterPushNotificationTask binds the task handler to the provided server.
+// The handler runs the SendPushNotificationUseCase through provider.
+func RegisterPushNotificationTask(srv qport.Server, pool *pgxpool.Pool, provider pushport.Provider) {
+	srv.Register(PushNotificationTaskType, func(ctx context.Context, t qport.Task) error {
+		var p PushNotificationTaskPayload
+		if err := json.Unmarshal(t.Payload, &p); err != nil {
+			// malformed payload: do not retry indefinitely
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		uc := usecase.NewSendPushNotificationUseCase(repoAdapter.NewPgChatRepository(pool), repoAdapter.NewPgReceiptRepository(pool),
+			repoAdapter.NewPgDeviceRepository(pool), provider)
+		_, err := uc.Execute(ctx, usecase.SendPushNotificationInput{UserID: p.UserID, ConversationID: p.ConversationID})
+		return err
+	})
+}
@@ -0,0 +1,89 @@
+package task
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+)
+
+// uniqueClient queues tasks in memory and, like asynq, refuses a task identical to one whose UniqueTTL
+// has not elapsed yet.
+type uniqueClient struct {
+	now    time.Time
+	locks  map[string]time.Time
+	queued []queuedTask
+}
+
+type queuedTask struct {
+	task qport.Task
+	opts qport.EnqueueOption
+}
+
+func (c *uniqueClient) Enqueue(_ context.Context, t qport.Task, opts ...qport.EnqueueOption) (string, error) {
+	var o qport.EnqueueOption
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.UniqueTTL > 0 {
+		key := t.Type + ":" + string(t.Payload)
+		if until, ok := c.locks[key]; ok && c.now.Before(until) {
+			return "", qport.ErrDuplicate
+		}
+		c.locks[key] = c.now.Add(o.UniqueTTL)
+	}
+	c.queued = append(c.queued, queuedTask{task: t, opts: o})
+	return t.Type, nil
+}
+
+func (c *uniqueClient) Close() error { return nil }
+
+func TestEnqueuePushNotificationCollapsesBurst(t *testing.T) {
+	client := &uniqueClient{now: time.Now(), locks: make(map[string]time.Time)}
+	ctx := context.Background()
+	window := 10 * time.Second
+
+	// Three messages within the window: the pending task absorbs the later requests
+	for i := 0; i < 3; i++ {
+		if err := EnqueuePushNotification(ctx, client, "alice", "conv-1", window); err != nil {
+			t.Fatalf("EnqueuePushNotification: %v", err)
+		}
+		client.now = client.now.Add(2 * time.Second)
+	}
+	// Other recipients and conversations are collapsed separately
+	if err := EnqueuePushNotification(ctx, client, "bob", "conv-1", window); err != nil {
+		t.Fatalf("EnqueuePushNotification: %v", err)
+	}
+	if err := EnqueuePushNotification(ctx, client, "alice", "conv-2", window); err != nil {
+		t.Fatalf("EnqueuePushNotification: %v", err)
+	}
+
+	if len(client.queued) != 3 {
+		t.Fatalf("queued %d tasks, want 3", len(client.queued))
+	}
+	for _, q := range client.queued {
+		if q.task.Type != PushNotificationTaskType || q.opts.ProcessIn != window || q.opts.UniqueTTL <= window {
+			t.Fatalf("queued %s with %+v", q.task.Type, q.opts)
+		}
+	}
+}
+
+func TestEnqueueNotifyMessageSkipsSystemMessages(t *testing.T) {
+	client := &uniqueClient{now: time.Now(), locks: make(map[string]time.Time)}
+	ctx := context.Background()
+
+	if err := EnqueueNotifyMessage(ctx, client, chat.Message{ID: "m1", MsgType: chat.MessageTypeSystem}); err != nil {
+		t.Fatalf("EnqueueNotifyMessage: %v", err)
+	}
+	// A retried send of the same message queues its fan-out once
+	for i := 0; i < 2; i++ {
+		if err := EnqueueNotifyMessage(ctx, client, chat.Message{ID: "m2", MsgType: chat.MessageTypeText}); err != nil {
+			t.Fatalf("EnqueueNotifyMessage: %v", err)
+		}
+	}
+	if len(client.queued) != 1 || client.queued[0].task.Type != NotifyMessageTaskType {
+		t.Fatalf("queued %+v, want one fan-out of m2", client.queued)
+	}
+}
@@ -0,0 +1,154 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	"go-chatty/internal/infrastructure/realtime"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+)
+
+const (
+	defaultReceiptBatchSize     = 200
+	defaultReceiptFlushInterval = 500 * time.Millisecond
+	receiptEnqueueTimeout       = 3 * time.Second
+)
+
+// ReceiptBatcher implements realtime.DeliveryRecorder. It buffers deliveries in memory and
+// enqueues them as RecordReceiptTask batches, either every flush interval or as soon as a
+// batch is full, so the websocket fan-out path never waits on Postgres.
+//
+// Receipts are best-effort: a batch that cannot be enqueued is dropped and logged, and
+// clients can still acknowledge deliveries explicitly.
+type ReceiptBatcher struct {
+	client    qport.Client
+	batchSize int
+	interval  time.Duration
+
+	mu      sync.Mutex
+	pending map[receiptKey]ReceiptItem
+
+	full chan struct{}
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+type receiptKey struct {
+	messageID string
+	userID    string
+}
+
+// NewReceiptBatcher constructs a batcher that enqueues through client. Call Start before use and Close on shutdown.
+func NewReceiptBatcher(client qport.Client) *ReceiptBatcher {
+	return &ReceiptBatcher{
+		client:    client,
+		batchSize: defaultReceiptBatchSize,
+		interval:  defaultReceiptFlushInterval,
+		pending:   make(map[receiptKey]ReceiptItem),
+		full:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Ensure interface compliance at compile time
+var _ realtime.DeliveryRecorder = (*ReceiptBatcher)(nil)
+
+// RecordDelivery buffers d; duplicates of a pending (message, recipient) pair are collapsed.
+func (b *ReceiptBatcher) RecordDelivery(d realtime.Delivery) {
+	b.mu.Lock()
+	key := receiptKey{messageID: d.MessageID, userID: d.RecipientID}
+	if _, ok := b.pending[key]; !ok {
+		b.pending[key] = ReceiptItem{
+			ConversationID: d.ConversationID,
+			MessageID:      d.MessageID,
+			SenderID:       d.SenderID,
+			UserID:         d.RecipientID,
+			Status:         int16(chat.ReceiptStatusDelivered),
+			At:             d.At,
+		}
+	}
+	isFull := len(b.pending) >= b.batchSize
+	b.mu.Unlock()
+
+	if isFull {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Start launches the flush loop.
+func (b *ReceiptBatcher) Start() {
+	go b.loop()
+}
+
+// Close stops the flush loop after enqueueing whatever is still pending.
+func (b *ReceiptBatcher) Close() {
+	b.once.Do(func() {
+		close(b.stop)
+		<-b.done
+	})
+}
+
+func (b *ReceiptBatcher) loop() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			b.flush()
+			return
+		case <-ticker.C:
+			b.flush()
+		case <-b.full:
+			b.flush()
+		}
+	}
+}
+
+func (b *ReceiptBatcher) flush() {
+	b.mu.Lock()
+	if len(b.pending) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	items := make([]ReceiptItem, 0, len(b.pending))
+	for _, item := range b.pending {
+		items = append(items, item)
+	}
+	b.pending = make(map[receiptKey]ReceiptItem)
+	b.mu.Unlock()
+
+	for start := 0; start < len(items); start += b.batchSize {
+		end := min(start+b.batchSize, len(items))
+		if err := b.enqueue(items[start:end]); err != nil {
+			// Best-effort log to stderr without introducing logging deps
+			_, _ = fmt.Fprintf(os.Stderr, "chat: enqueue %d receipts: %v\n", end-start, err)
+		}
+	}
+}
+
+func (b *ReceiptBatcher) enqueue(items []ReceiptItem) error {
+	payload, err := json.Marshal(RecordReceiptTaskPayload{Receipts: items})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), receiptEnqueueTimeout)
+	defer cancel()
+
+	opts := qport.EnqueueOption{Queue: "chat", MaxRetry: 5}
+	_, err = b.client.Enqueue(ctx, qport.Task{Type: RecordReceiptTaskType, Payload: payload}, opts)
+	return err
+}
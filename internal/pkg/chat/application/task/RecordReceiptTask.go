@@ -0,0 +1,97 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	"go-chatty/internal/infrastructure/realtime"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RecordReceiptTaskType is the queue task name for persisting a batch of delivery receipts.
+const RecordReceiptTaskType = "chat:record_receipts"
+
+// RecordReceiptTaskPayload is the JSON payload transported via the queue.
+type RecordReceiptTaskPayload struct {
+	Receipts []ReceiptItem `json:"receipts"`
+}
+
+// ReceiptItem is one receipt in a RecordReceiptTaskPayload.
+type ReceiptItem struct {
+	ConversationID string    `json:"conversationId"`
+	MessageID      string    `json:"messageId"`
+	SenderID       string    `json:"senderId"`
+	UserID         string    `json:"userId"`
+	Status         int16     `json:"status"`
+	At             time.Time `json:"at"`
+}
+
+// receiptFrame is the websocket frame sent to a message's author when a recipient's receipt is stored.
+type receiptFrame struct {
+	Type           string    `json:"type"`
+	ConversationID string    `json:"conversationId"`
+	MessageID      string    `json:"messageId"`
+	UserID         string    `json:"userId"`
+	Status         string    `json:"status"`
+	At             time.Time `json:"at"`
+}
+
+// RegisterRecordReceiptTask binds the task handler to the provided server.
+// New receipts are stored with the RecordReceiptUseCase and reported to each sender through router.
+func RegisterRecordReceiptTask(srv qport.Server, pool *pgxpool.Pool, router *realtime.Router) {
+	srv.Register(RecordReceiptTaskType, func(ctx context.Context, t qport.Task) error {
+		var p RecordReceiptTaskPayload
+		if err := json.Unmarshal(t.Payload, &p); err != nil {
+			// malformed payload: do not retry indefinitely
+			return err
+		}
+
+		receipts := make([]chat.Receipt, 0, len(p.Receipts))
+		for _, item := range p.Receipts {
+			receipts = append(receipts, chat.Receipt{
+				MessageID:      item.MessageID,
+				UserID:         item.UserID,
+				Status:         chat.ReceiptStatus(item.Status),
+				At:             item.At,
+				ConversationID: item.ConversationID,
+				SenderID:       item.SenderID,
+			})
+		}
+
+		uc := usecase.NewRecordReceiptUseCase(repoAdapter.NewPgReceiptRepository(pool))
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		saved, err := uc.Execute(ctx, usecase.RecordReceiptInput{Receipts: receipts})
+		if err != nil {
+			return err
+		}
+
+		// Retries only re-notify receipts that were not stored before, since SaveReceipts skips existing rows
+		for _, rc := range saved {
+			if rc.SenderID == "" {
+				continue
+			}
+			frame, err := json.Marshal(receiptFrame{
+				Type:           "receipt",
+				ConversationID: rc.ConversationID,
+				MessageID:      rc.MessageID,
+				UserID:         rc.UserID,
+				Status:         rc.Status.String(),
+				At:             rc.At,
+			})
+			if err != nil {
+				continue
+			}
+			router.NotifyUser(rc.SenderID, frame)
+		}
+		return nil
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	qport "go-chatty/internal/infrastructure/queue/port"
+	storageport "go-chatty/internal/infrastructure/storage/port"
 	chat "go-chatty/internal/pkg/chat/application/domain"
 	"go-chatty/internal/pkg/chat/application/usecase"
 	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
@@ -23,14 +24,16 @@ type SendMessageTaskPayload struct {
 	SenderID       string  `json:"senderId"`
 	Body           *string `json:"body"`
 	MsgType        int16   `json:"msgType"`
-	AttachmentURL  *string `json:"attachmentUrl"`
-	AttachmentMeta *string `json:"attachmentMeta"`
+	AttachmentID   *string `json:"attachmentId,omitempty"`
 	DedupeKey      *string `json:"dedupeKey"`
+	ReplyToID      *string `json:"replyToId,omitempty"`
+	ThreadRootID   *string `json:"threadRootId,omitempty"`
 }
 
 // RegisterSendMessageTask binds the task handler to the provided server.
-// The handler will execute the SendMessageUseCase using the provided DB pool.
-func RegisterSendMessageTask(srv qport.Server, pool *pgxpool.Pool) {
+// The handler will execute the SendMessageUseCase using the provided DB pool, resolving attachments through storage.
+// The outbox relay announces the stored message to open sessions, push notifications and webhooks.
+func RegisterSendMessageTask(srv qport.Server, pool *pgxpool.Pool, storage storageport.Storage, policy storageport.Policy) {
 	srv.Register(SendMessageTaskType, func(ctx context.Context, t qport.Task) error {
 		var p SendMessageTaskPayload
 		if err := json.Unmarshal(t.Payload, &p); err != nil {
@@ -40,16 +43,17 @@ func RegisterSendMessageTask(srv qport.Server, pool *pgxpool.Pool) {
 
 		// Construct use case with repository adapter
 		repo := repoAdapter.NewPgChatRepository(pool)
-		uc := usecase.NewSendMessageUseCase(repo)
+		uc := usecase.NewSendMessageUseCase(repo, storage, policy)
 
 		in := usecase.SendMessageInput{
 			ConversationID: p.ConversationID,
 			SenderID:       p.SenderID,
 			Body:           p.Body,
 			MsgType:        chat.MessageType(p.MsgType),
-			AttachmentURL:  p.AttachmentURL,
-			AttachmentMeta: p.AttachmentMeta,
+			AttachmentID:   p.AttachmentID,
 			DedupeKey:      p.DedupeKey,
+			ReplyToID:      p.ReplyToID,
+			ThreadRootID:   p.ThreadRootID,
 		}
 
 		// give DB a reasonable time budget per task execution
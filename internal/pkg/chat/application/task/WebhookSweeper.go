@@ -0,0 +1,97 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	qport "go-chatty/internal/infrastructure/queue/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	webhookSweepInterval  = time.Minute
+	webhookSweepBatchSize = 100
+	webhookSweepTimeout   = 30 * time.Second
+)
+
+// WebhookSweeper queues again the pending webhook deliveries whose next attempt is overdue by more than
+// chat.WebhookStallGrace, i.e. whose task was lost: enqueueing it failed after an attempt, or the queue
+// dropped it. Every replica runs a sweeper; the task's unique lock and its attempt number make the
+// occasional duplicate harmless.
+type WebhookSweeper struct {
+	webhooks repository.WebhookRepository
+	client   qport.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewWebhookSweeper constructs a sweeper that enqueues through client. Call Start to run it and Close on shutdown.
+func NewWebhookSweeper(pool *pgxpool.Pool, client qport.Client) *WebhookSweeper {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookSweeper{
+		webhooks: repoAdapter.NewPgWebhookRepository(pool),
+		client:   client,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start launches the sweep loop.
+func (s *WebhookSweeper) Start() {
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// Close stops the sweep loop.
+func (s *WebhookSweeper) Close() {
+	s.once.Do(func() {
+		s.cancel()
+		s.wg.Wait()
+	})
+}
+
+func (s *WebhookSweeper) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(webhookSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+// sweep queues one batch of stalled deliveries; a larger backlog is worked off over the next sweeps.
+func (s *WebhookSweeper) sweep() {
+	ctx, cancel := context.WithTimeout(s.ctx, webhookSweepTimeout)
+	defer cancel()
+
+	stalled, err := s.webhooks.ListOverdueWebhookDeliveries(ctx, time.Now().UTC().Add(-chat.WebhookStallGrace), webhookSweepBatchSize)
+	if err != nil {
+		if s.ctx.Err() == nil {
+			_, _ = fmt.Fprintf(os.Stderr, "chat: list stalled webhook deliveries: %v\n", err)
+		}
+		return
+	}
+	for _, d := range stalled {
+		if err := EnqueueWebhookDelivery(ctx, s.client, d); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "chat: requeue webhook delivery %s: %v\n", d.ID, err)
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// AckDeliveryInput is a client acknowledgement that UserID received MessageID.
+type AckDeliveryInput struct {
+	ConversationID string
+	UserID         string
+	MessageID      string
+}
+
+// AckDeliveryUseCase validates a client acknowledgement and turns it into a delivered receipt.
+// The receipt is not stored here; callers hand it to the batched receipt pipeline.
+type AckDeliveryUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewAckDeliveryUseCase(repo repository.ChatRepository) *AckDeliveryUseCase {
+	return &AckDeliveryUseCase{Repo: repo}
+}
+
+func (uc *AckDeliveryUseCase) Execute(ctx context.Context, in AckDeliveryInput) (*chat.Receipt, error) {
+	if in.ConversationID == "" || in.UserID == "" || in.MessageID == "" {
+		return nil, fmt.Errorf("conversationId, userId and messageId are required")
+	}
+
+	ok, err := uc.Repo.IsParticipant(ctx, in.ConversationID, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if !ok {
+		return nil, chat.ErrNotParticipant
+	}
+
+	msg, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chat.Receipt{
+		MessageID:      msg.ID,
+		UserID:         in.UserID,
+		Status:         chat.ReceiptStatusDelivered,
+		At:             time.Now().UTC(),
+		ConversationID: msg.ConversationID,
+		SenderID:       msg.SenderID,
+	}, nil
+}
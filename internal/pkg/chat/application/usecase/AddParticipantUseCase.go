@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// AddParticipantInput carries a request to add a user to a group conversation.
+type AddParticipantInput struct {
+	ConversationID string
+	ActorID        string
+	UserID         string
+	Role           chat.ParticipantRole
+}
+
+// AddParticipantUseCase adds a member to a group on behalf of an admin or owner.
+type AddParticipantUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewAddParticipantUseCase(repo repository.ChatRepository) *AddParticipantUseCase {
+	return &AddParticipantUseCase{Repo: repo}
+}
+
+// Execute adds the participant and returns the system message recording the change.
+func (uc *AddParticipantUseCase) Execute(ctx context.Context, in AddParticipantInput) (*chat.Message, error) {
+	if in.ConversationID == "" || in.ActorID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId, actorId and userId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, msg, err := c.AddParticipant(in.ActorID, in.UserID, in.Role, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	stored, err := uc.Repo.AddParticipant(ctx, p, msg)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, chat.ErrNotParticipant
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &stored, nil
+}
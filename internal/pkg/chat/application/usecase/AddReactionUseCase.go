@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// AddReactionInput carries a request from UserID to react to a message with Emoji.
+type AddReactionInput struct {
+	ConversationID string
+	MessageID      string
+	UserID         string
+	Emoji          string
+}
+
+// AddReactionOutput holds the message, the stored reaction and how many users now reacted with its emoji.
+// Added is false when the user had already reacted with that emoji; otherwise Seq is the sequence number
+// of the change.
+type AddReactionOutput struct {
+	Message  chat.Message
+	Reaction chat.Reaction
+	Count    int
+	Added    bool
+	Seq      int64
+}
+
+// AddReactionUseCase adds an emoji reaction within the per-message limits.
+type AddReactionUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewAddReactionUseCase(repo repository.ChatRepository) *AddReactionUseCase {
+	return &AddReactionUseCase{Repo: repo}
+}
+
+func (uc *AddReactionUseCase) Execute(ctx context.Context, in AddReactionInput) (*AddReactionOutput, error) {
+	if in.ConversationID == "" || in.MessageID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId, messageId and userId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := uc.Repo.ListReactions(ctx, in.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	reaction, added, err := c.React(in.UserID, msg, in.Emoji, existing, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	var seq int64
+	if added {
+		// The snapshot above may be stale: the repository enforces the limits again, and a concurrent
+		// identical request may have won, in which case only the first insert counts as added
+		seq, added, err = uc.Repo.AddReaction(ctx, in.ConversationID, reaction, chat.MaxReactionsPerUser, chat.MaxReactionEmojisPerMessage)
+		switch {
+		case errors.Is(err, repository.ErrLimitReached):
+			return nil, chat.ErrReactionLimit
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, chat.ErrMessageDeleted
+		case err != nil:
+			return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+		}
+	}
+
+	count, err := reactionCount(ctx, uc.Repo, reaction)
+	if err != nil {
+		return nil, err
+	}
+	return &AddReactionOutput{Message: msg, Reaction: reaction, Count: count, Added: added, Seq: seq}, nil
+}
+
+// reactionCount returns how many users reacted to r's message with r's emoji.
+func reactionCount(ctx context.Context, repo repository.ChatRepository, r chat.Reaction) (int, error) {
+	counts, err := repo.CountReactions(ctx, []string{r.MessageID}, r.UserID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	for _, rc := range counts[r.MessageID] {
+		if rc.Emoji == r.Emoji {
+			return rc.Count, nil
+		}
+	}
+	return 0, nil
+}
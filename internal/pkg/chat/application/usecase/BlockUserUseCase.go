@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// BlockUserInput carries a request from BlockerID to block BlockedID.
+type BlockUserInput struct {
+	BlockerID string
+	BlockedID string
+}
+
+// BlockUserUseCase records a block; blocking twice is a no-op.
+type BlockUserUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewBlockUserUseCase(repo repository.ChatRepository) *BlockUserUseCase {
+	return &BlockUserUseCase{Repo: repo}
+}
+
+func (uc *BlockUserUseCase) Execute(ctx context.Context, in BlockUserInput) (*chat.Block, error) {
+	if in.BlockerID == "" || in.BlockedID == "" {
+		return nil, fmt.Errorf("blockerId and blockedId are required")
+	}
+	if in.BlockerID == in.BlockedID {
+		return nil, chat.ErrCannotBlockSelf
+	}
+
+	b := chat.Block{
+		CreatedAt: time.Now().UTC(),
+		BlockerId: in.BlockerID,
+		BlockedId: in.BlockedID,
+	}
+	if err := uc.Repo.BlockUser(ctx, b); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &b, nil
+}
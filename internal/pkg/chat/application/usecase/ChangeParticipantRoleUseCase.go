@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ChangeParticipantRoleInput carries a request to change a member's role in a group.
+type ChangeParticipantRoleInput struct {
+	ConversationID string
+	ActorID        string
+	UserID         string
+	Role           chat.ParticipantRole
+}
+
+// ChangeParticipantRoleUseCase promotes or demotes a group member.
+type ChangeParticipantRoleUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewChangeParticipantRoleUseCase(repo repository.ChatRepository) *ChangeParticipantRoleUseCase {
+	return &ChangeParticipantRoleUseCase{Repo: repo}
+}
+
+// Execute updates the role and returns the system message recording the change.
+func (uc *ChangeParticipantRoleUseCase) Execute(ctx context.Context, in ChangeParticipantRoleInput) (*chat.Message, error) {
+	if in.ConversationID == "" || in.ActorID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId, actorId and userId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, msg, err := c.ChangeRole(in.ActorID, in.UserID, in.Role, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	stored, err := uc.Repo.UpdateParticipantRole(ctx, in.ConversationID, in.UserID, p.Role, msg)
+	switch {
+	case errors.Is(err, repository.ErrLastOwner):
+		return nil, chat.ErrLastOwner
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, chat.ErrNotParticipant
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &stored, nil
+}
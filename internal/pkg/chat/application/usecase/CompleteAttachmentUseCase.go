@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// CompleteAttachmentInput reports that OwnerID finished uploading an attachment.
+type CompleteAttachmentInput struct {
+	AttachmentID string
+	OwnerID      string
+}
+
+// CompleteAttachmentUseCase checks that an attachment landed in storage as declared, so its processing can
+// start before any message carries it.
+type CompleteAttachmentUseCase struct {
+	Repo    repository.ChatRepository
+	Storage storageport.Storage
+}
+
+func NewCompleteAttachmentUseCase(repo repository.ChatRepository, storage storageport.Storage) *CompleteAttachmentUseCase {
+	return &CompleteAttachmentUseCase{Repo: repo, Storage: storage}
+}
+
+func (uc *CompleteAttachmentUseCase) Execute(ctx context.Context, in CompleteAttachmentInput) (*chat.Attachment, error) {
+	if in.AttachmentID == "" || in.OwnerID == "" {
+		return nil, fmt.Errorf("attachmentId and ownerId are required")
+	}
+	a, err := loadAttachment(ctx, uc.Repo, in.AttachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.OwnerID != in.OwnerID {
+		return nil, chat.ErrAttachmentNotFound
+	}
+	if err := checkUpload(ctx, uc.Storage, a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// checkUpload verifies that the object of a was uploaded with the declared size and type.
+func checkUpload(ctx context.Context, storage storageport.Storage, a chat.Attachment) error {
+	info, err := storage.Stat(ctx, a.StorageKey)
+	if errors.Is(err, storageport.ErrNotFound) {
+		return chat.ErrAttachmentMissing
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	if info.Size != a.Size || storageport.NormalizeContentType(info.ContentType) != a.ContentType {
+		return fmt.Errorf("%w: uploaded content does not match the declared type and size", chat.ErrInvalidAttachment)
+	}
+	return nil
+}
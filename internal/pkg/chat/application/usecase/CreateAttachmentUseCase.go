@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/google/uuid"
+)
+
+// AttachmentUploadExpiry is how long a presigned upload stays valid.
+const AttachmentUploadExpiry = 15 * time.Minute
+
+// CreateAttachmentInput declares a file the owner is about to upload.
+type CreateAttachmentInput struct {
+	OwnerID     string
+	FileName    string
+	ContentType string
+	Size        int64
+}
+
+// CreateAttachmentOutput is the registered attachment and the request that uploads its content.
+type CreateAttachmentOutput struct {
+	Attachment chat.Attachment
+	Upload     storageport.PresignedRequest
+}
+
+// CreateAttachmentUseCase registers an attachment and signs its upload, enforcing the size and type allow-lists.
+// The content goes straight from the client to storage; sending a message with the attachment checks it arrived.
+type CreateAttachmentUseCase struct {
+	Repo    repository.ChatRepository
+	Storage storageport.Storage
+	Policy  storageport.Policy
+}
+
+func NewCreateAttachmentUseCase(repo repository.ChatRepository, storage storageport.Storage, policy storageport.Policy) *CreateAttachmentUseCase {
+	return &CreateAttachmentUseCase{Repo: repo, Storage: storage, Policy: policy}
+}
+
+func (uc *CreateAttachmentUseCase) Execute(ctx context.Context, in CreateAttachmentInput) (*CreateAttachmentOutput, error) {
+	if in.OwnerID == "" {
+		return nil, fmt.Errorf("ownerId is required")
+	}
+	contentType := storageport.NormalizeContentType(in.ContentType)
+	if err := uc.Policy.Check(contentType, in.Size); err != nil {
+		return nil, err
+	}
+
+	a, err := chat.NewAttachment(chat.Attachment{
+		OwnerID:     in.OwnerID,
+		StorageKey:  "attachments/" + uuid.NewString(),
+		FileName:    in.FileName,
+		ContentType: contentType,
+		Size:        in.Size,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uc.Repo.SaveAttachment(ctx, *a)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	a.ID = id
+
+	upload, err := uc.Storage.PresignPut(ctx, a.StorageKey, a.ContentType, a.Size, AttachmentUploadExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	return &CreateAttachmentOutput{Attachment: *a, Upload: upload}, nil
+}
@@ -2,19 +2,32 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	chat "go-chatty/internal/pkg/chat/application/domain"
 	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+	"strings"
 	"time"
 )
 
 // CreateChatInput carries the required data to open a new conversation
 // Note: variables with multiple items use plural naming per guideline
-// Domain constraints for participants and block logic are minimal for now
-// (no dedup of existing conversations, etc.).
+// The creator is always added as a participant; in groups they become the owner.
 type CreateChatInput struct {
 	TenantID       string
+	CreatorID      string
 	ParticipantIDs []string
+	Kind           *chat.ConversationKind // nil infers direct for exactly two users, group otherwise
+	Title          *string                // groups only
+	AvatarURL      *string                // groups only
+	HistoryHidden  bool                   // groups only
+}
+
+// CreateChatOutput is the resulting conversation. Created is false when an existing
+// direct conversation between the same two users was returned instead of a new one.
+type CreateChatOutput struct {
+	Conversation chat.Conversation
+	Created      bool
 }
 
 // CreateChatUseCase handles creation of a new conversation and its participants
@@ -28,34 +41,108 @@ func NewCreateChatUseCase(repo repository.ChatRepository) *CreateChatUseCase {
 	return &CreateChatUseCase{Repo: repo}
 }
 
-// Execute persists a conversation and registers participants
-func (uc *CreateChatUseCase) Execute(ctx context.Context, in CreateChatInput) (*chat.Conversation, error) {
-	if len(in.ParticipantIDs) == 0 {
+// Execute persists a conversation and registers participants atomically.
+// Direct conversations are idempotent per tenant and pair of users.
+func (uc *CreateChatUseCase) Execute(ctx context.Context, in CreateChatInput) (*CreateChatOutput, error) {
+	userIDs := uniqueUserIDs(in.CreatorID, in.ParticipantIDs)
+	if len(userIDs) == 0 {
 		return nil, fmt.Errorf("participantIds must include at least one user id")
 	}
 
+	kind := chat.ConversationKindGroup
+	if len(userIDs) == 2 {
+		kind = chat.ConversationKindDirect
+	}
+	if in.Kind != nil {
+		kind = *in.Kind
+	}
+
 	now := time.Now().UTC()
-	conv := chat.Conversation{CreatedAt: now, TenantID: in.TenantID}
+	conv := chat.Conversation{CreatedAt: now, TenantID: in.TenantID, Kind: kind}
+
+	switch kind {
+	case chat.ConversationKindDirect:
+		if len(userIDs) != 2 {
+			return nil, fmt.Errorf("direct conversations need exactly two distinct participants")
+		}
+		if in.Title != nil || in.AvatarURL != nil || in.HistoryHidden {
+			return nil, fmt.Errorf("title, avatarUrl and historyHidden only apply to group conversations")
+		}
+		conv.PairKey = chat.DirectPairKey(userIDs[0], userIDs[1])
+		if err := uc.ensureNotBlocked(ctx, userIDs[0], userIDs[1]); err != nil {
+			return nil, err
+		}
+	case chat.ConversationKindGroup:
+		if in.CreatorID == "" {
+			return nil, fmt.Errorf("creatorId is required for group conversations")
+		}
+		if in.Title != nil {
+			trimmed := strings.TrimSpace(*in.Title)
+			if trimmed != "" {
+				conv.Title = &trimmed
+			}
+		}
+		conv.AvatarURL = in.AvatarURL
+		conv.HistoryHidden = in.HistoryHidden
+	default:
+		return nil, chat.ErrInvalidKind
+	}
+
+	participants := make([]chat.Participant, 0, len(userIDs))
+	for _, uid := range userIDs {
+		role := chat.ParticipantRoleMember
+		if kind == chat.ConversationKindGroup && uid == in.CreatorID {
+			role = chat.ParticipantRoleOwner
+		}
+		participants = append(participants, chat.Participant{
+			UserID:   uid,
+			Role:     role,
+			JoinedAt: now,
+		})
+	}
 
-	id, err := uc.Repo.CreateConversation(ctx, conv)
+	id, created, err := uc.Repo.CreateConversation(ctx, conv, participants)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
 	}
+	if !created {
+		existing, err := uc.Repo.GetConversation(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+		}
+		return &CreateChatOutput{Conversation: existing, Created: false}, nil
+	}
 	conv.ID = id
 
-	for _, uid := range in.ParticipantIDs {
+	return &CreateChatOutput{Conversation: conv, Created: true}, nil
+}
+
+// ensureNotBlocked rejects direct conversations between users with a block in either direction.
+func (uc *CreateChatUseCase) ensureNotBlocked(ctx context.Context, userA string, userB string) error {
+	_, err := uc.Repo.FindBlock(ctx, userA, []string{userB})
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return chat.ErrUserBlocked
+}
+
+// uniqueUserIDs returns the creator followed by the other ids, without blanks or duplicates.
+func uniqueUserIDs(creatorID string, ids []string) []string {
+	seen := make(map[string]struct{}, len(ids)+1)
+	res := make([]string, 0, len(ids)+1)
+	for _, uid := range append([]string{creatorID}, ids...) {
+		uid = strings.TrimSpace(uid)
 		if uid == "" {
 			continue
 		}
-		p := chat.Participant{
-			ConversationID: id,
-			UserID:         uid,
-			Role:           chat.ParticipantRoleMember,
-		}
-		if err := uc.Repo.AddParticipant(ctx, p); err != nil {
-			return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+		if _, ok := seen[uid]; ok {
+			continue
 		}
+		seen[uid] = struct{}{}
+		res = append(res, uid)
 	}
-
-	return &conv, nil
+	return res
 }
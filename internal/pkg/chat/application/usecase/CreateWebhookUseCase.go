@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MinWebhookSecretLength is the shortest secret accepted from callers, in bytes.
+	MinWebhookSecretLength = 16
+	maxWebhookSecretLength = 256
+	maxWebhookDescription  = 255
+)
+
+// CreateWebhookInput subscribes URL to the events of TenantID's conversations. Events filters the event types,
+// all of them when empty. Without a Secret, one is generated.
+type CreateWebhookInput struct {
+	TenantID    string
+	URL         string
+	Secret      *string
+	Events      []string
+	Description *string
+}
+
+// CreateWebhookUseCase registers a webhook subscription for a tenant.
+type CreateWebhookUseCase struct {
+	Webhooks repository.WebhookRepository
+}
+
+func NewCreateWebhookUseCase(webhooks repository.WebhookRepository) *CreateWebhookUseCase {
+	return &CreateWebhookUseCase{Webhooks: webhooks}
+}
+
+// Execute returns the stored subscription, including its secret.
+func (uc *CreateWebhookUseCase) Execute(ctx context.Context, in CreateWebhookInput) (*chat.WebhookSubscription, error) {
+	if in.TenantID != "" && uuid.Validate(in.TenantID) != nil {
+		return nil, fmt.Errorf("tenant id must be a UUID")
+	}
+	target := strings.TrimSpace(in.URL)
+	if err := chat.ValidateWebhookURL(target); err != nil {
+		return nil, err
+	}
+
+	var secret string
+	if in.Secret != nil {
+		secret = *in.Secret
+		if len(secret) < MinWebhookSecretLength || len(secret) > maxWebhookSecretLength {
+			return nil, fmt.Errorf("secret must be between %d and %d bytes", MinWebhookSecretLength, maxWebhookSecretLength)
+		}
+	} else {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(raw)
+	}
+
+	var events []chat.EventType
+	seen := make(map[chat.EventType]bool, len(in.Events))
+	for _, e := range in.Events {
+		t := chat.EventType(strings.TrimSpace(e))
+		if !t.Valid() {
+			return nil, fmt.Errorf("%w: %q", chat.ErrInvalidEventType, e)
+		}
+		if !seen[t] {
+			seen[t] = true
+			events = append(events, t)
+		}
+	}
+
+	var description *string
+	if in.Description != nil {
+		trimmed := strings.TrimSpace(*in.Description)
+		if utf8.RuneCountInString(trimmed) > maxWebhookDescription {
+			return nil, fmt.Errorf("description must be at most %d characters", maxWebhookDescription)
+		}
+		if trimmed != "" {
+			description = &trimmed
+		}
+	}
+
+	now := time.Now().UTC()
+	s := chat.WebhookSubscription{
+		TenantID:    in.TenantID,
+		URL:         target,
+		Secret:      secret,
+		Events:      events,
+		Description: description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	id, err := uc.Webhooks.CreateWebhookSubscription(ctx, s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	s.ID = id
+	return &s, nil
+}
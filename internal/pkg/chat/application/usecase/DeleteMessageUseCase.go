@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// DeleteMessageInput carries a request from ActorID to delete a message for everyone.
+type DeleteMessageInput struct {
+	ConversationID string
+	MessageID      string
+	ActorID        string
+}
+
+// DeleteMessageOutput is the resulting tombstone. Deleted is false when the message was
+// already deleted, in which case nothing changed and no event should be emitted. ThreadRoot is
+// the root of a deleted thread reply with its new counters.
+type DeleteMessageOutput struct {
+	Message    chat.Message
+	ThreadRoot *chat.Message
+	Deleted    bool
+}
+
+// DeleteMessageUseCase turns a message into a tombstone; deleting twice is a no-op.
+type DeleteMessageUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewDeleteMessageUseCase(repo repository.ChatRepository) *DeleteMessageUseCase {
+	return &DeleteMessageUseCase{Repo: repo}
+}
+
+func (uc *DeleteMessageUseCase) Execute(ctx context.Context, in DeleteMessageInput) (*DeleteMessageOutput, error) {
+	if in.ConversationID == "" || in.MessageID == "" || in.ActorID == "" {
+		return nil, fmt.Errorf("conversationId, messageId and actorId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstone, err := c.DeleteMessage(in.ActorID, msg, time.Now())
+	if errors.Is(err, chat.ErrMessageDeleted) {
+		return &DeleteMessageOutput{Message: msg}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seq, root, err := uc.Repo.DeleteMessage(ctx, tombstone)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// Lost a race with another delete; report the stored tombstone
+			stored, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID)
+			if err != nil {
+				return nil, err
+			}
+			return &DeleteMessageOutput{Message: stored}, nil
+		}
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	tombstone.ChangeSeq = seq
+	return &DeleteMessageOutput{Message: tombstone, ThreadRoot: root, Deleted: true}, nil
+}
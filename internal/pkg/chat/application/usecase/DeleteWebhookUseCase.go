@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/google/uuid"
+)
+
+// DeleteWebhookInput names a subscription of TenantID to remove.
+type DeleteWebhookInput struct {
+	TenantID  string
+	WebhookID string
+}
+
+// DeleteWebhookUseCase unsubscribes a webhook; its pending deliveries are dropped with it.
+type DeleteWebhookUseCase struct {
+	Webhooks repository.WebhookRepository
+}
+
+func NewDeleteWebhookUseCase(webhooks repository.WebhookRepository) *DeleteWebhookUseCase {
+	return &DeleteWebhookUseCase{Webhooks: webhooks}
+}
+
+func (uc *DeleteWebhookUseCase) Execute(ctx context.Context, in DeleteWebhookInput) error {
+	if uuid.Validate(in.WebhookID) != nil {
+		return chat.ErrWebhookNotFound
+	}
+	err := uc.Webhooks.DeleteWebhookSubscription(ctx, in.TenantID, in.WebhookID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.ErrWebhookNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return nil
+}
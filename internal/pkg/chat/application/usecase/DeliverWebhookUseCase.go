@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	webhookport "go-chatty/internal/infrastructure/webhook/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// DeliverWebhookInput names a delivery and the number of attempts it had when this one was scheduled.
+type DeliverWebhookInput struct {
+	DeliveryID string
+	Attempt    int
+}
+
+// DeliverWebhookOutput is the delivery after the attempt, nil when there was nothing to attempt. A delivery
+// still pending has its next attempt due at NextAttemptAt.
+type DeliverWebhookOutput struct {
+	Delivery *chat.WebhookDelivery
+}
+
+// DeliverWebhookUseCase makes one attempt at delivering an event to its subscriber and records the outcome:
+// delivered, retried later with an exponential delay, or dead once its attempts are exhausted. Attempts
+// scheduled before the delivery moved on, e.g. duplicates or superseded by a replay, are skipped.
+type DeliverWebhookUseCase struct {
+	Webhooks repository.WebhookRepository
+	Sender   webhookport.Sender
+}
+
+func NewDeliverWebhookUseCase(webhooks repository.WebhookRepository, sender webhookport.Sender) *DeliverWebhookUseCase {
+	return &DeliverWebhookUseCase{Webhooks: webhooks, Sender: sender}
+}
+
+func (uc *DeliverWebhookUseCase) Execute(ctx context.Context, in DeliverWebhookInput) (*DeliverWebhookOutput, error) {
+	d, err := uc.Webhooks.GetWebhookDelivery(ctx, in.DeliveryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Removed with its subscription
+		return &DeliverWebhookOutput{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if d.Status != chat.DeliveryStatusPending || d.Attempts != in.Attempt {
+		return &DeliverWebhookOutput{}, nil
+	}
+	s, err := uc.Webhooks.GetWebhookSubscription(ctx, d.SubscriptionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return &DeliverWebhookOutput{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	status, sendErr := uc.Sender.Send(ctx, webhookport.Request{
+		URL:        s.URL,
+		Secret:     s.Secret,
+		DeliveryID: d.ID,
+		EventType:  string(d.EventType),
+		Body:       d.Payload,
+	})
+	prevAttempts := d.Attempts
+	if sendErr == nil {
+		d.RecordSuccess(status, time.Now())
+	} else {
+		var code *int
+		if status != 0 {
+			code = &status
+		}
+		d.RecordFailure(code, sendErr.Error(), time.Now())
+	}
+
+	err = uc.Webhooks.UpdateWebhookDelivery(ctx, d, chat.DeliveryStatusPending, prevAttempts)
+	if errors.Is(err, repository.ErrConflict) {
+		// Another attempt or a replay got there first and owns what comes next
+		return &DeliverWebhookOutput{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &DeliverWebhookOutput{Delivery: &d}, nil
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// DispatchEventInput carries a published event.
+type DispatchEventInput struct {
+	Event chat.Event
+}
+
+// DispatchEventOutput lists the deliveries of the event still waiting for an attempt.
+type DispatchEventOutput struct {
+	Pending []chat.WebhookDelivery
+}
+
+// DispatchEventUseCase records a delivery of an event for every webhook subscription of its tenant that wants it.
+// Dispatching an event again records nothing new, so retries are safe.
+type DispatchEventUseCase struct {
+	Webhooks repository.WebhookRepository
+}
+
+func NewDispatchEventUseCase(webhooks repository.WebhookRepository) *DispatchEventUseCase {
+	return &DispatchEventUseCase{Webhooks: webhooks}
+}
+
+func (uc *DispatchEventUseCase) Execute(ctx context.Context, in DispatchEventInput) (*DispatchEventOutput, error) {
+	if in.Event.ID == "" || !in.Event.Type.Valid() {
+		return nil, fmt.Errorf("%w: %q", chat.ErrInvalidEventType, in.Event.Type)
+	}
+	// Every subscriber receives the same body, which retries and replays send again as is
+	payload, err := json.Marshal(in.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := uc.Webhooks.CreateWebhookDeliveries(ctx, in.Event, payload, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	out := &DispatchEventOutput{}
+	for _, d := range deliveries {
+		if d.Status == chat.DeliveryStatusPending {
+			out.Pending = append(out.Pending, d)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// EditMessageInput carries a request from EditorID to replace the body of a message.
+type EditMessageInput struct {
+	ConversationID string
+	MessageID      string
+	EditorID       string
+	Body           string
+}
+
+// EditMessageUseCase lets a sender correct their message; the previous body goes to the edit history.
+type EditMessageUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewEditMessageUseCase(repo repository.ChatRepository) *EditMessageUseCase {
+	return &EditMessageUseCase{Repo: repo}
+}
+
+func (uc *EditMessageUseCase) Execute(ctx context.Context, in EditMessageInput) (*chat.Message, error) {
+	if in.ConversationID == "" || in.MessageID == "" || in.EditorID == "" {
+		return nil, fmt.Errorf("conversationId, messageId and editorId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	edited, edit, err := c.EditMessage(in.EditorID, msg, in.Body, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	seq, err := uc.Repo.EditMessage(ctx, edited, edit)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, chat.ErrMessageDeleted
+		}
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	edited.ChangeSeq = seq
+	return &edited, nil
+}
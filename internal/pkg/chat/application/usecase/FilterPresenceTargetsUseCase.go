@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// MaxPresenceLookup caps how many users a single presence lookup may ask about.
+const MaxPresenceLookup = 100
+
+// FilterPresenceTargetsInput lists the users whose presence RequesterID asks for.
+type FilterPresenceTargetsInput struct {
+	RequesterID string
+	UserIDs     []string
+}
+
+// FilterPresenceTargetsUseCase keeps the users whose presence the requester may see: the requester
+// themself and users sharing a conversation with them who have not blocked them. This mirrors
+// ListPresenceAudienceUseCase from the other side.
+type FilterPresenceTargetsUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewFilterPresenceTargetsUseCase(repo repository.ChatRepository) *FilterPresenceTargetsUseCase {
+	return &FilterPresenceTargetsUseCase{Repo: repo}
+}
+
+func (uc *FilterPresenceTargetsUseCase) Execute(ctx context.Context, in FilterPresenceTargetsInput) ([]string, error) {
+	if in.RequesterID == "" {
+		return nil, fmt.Errorf("requesterId is required")
+	}
+	if len(in.UserIDs) > MaxPresenceLookup {
+		return nil, fmt.Errorf("at most %d userIds may be looked up at once", MaxPresenceLookup)
+	}
+
+	contacts, err := uc.Repo.ListContactIDs(ctx, in.RequesterID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	blockers, err := uc.Repo.ListBlockedBy(ctx, in.RequesterID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	visible := make(map[string]bool, len(contacts)+1)
+	visible[in.RequesterID] = true
+	for _, id := range contacts {
+		visible[id] = true
+	}
+	for _, b := range blockers {
+		delete(visible, b.BlockerId)
+	}
+
+	targets := make([]string, 0, len(in.UserIDs))
+	seen := make(map[string]struct{}, len(in.UserIDs))
+	for _, id := range in.UserIDs {
+		if _, dup := seen[id]; dup || !visible[id] {
+			continue
+		}
+		seen[id] = struct{}{}
+		targets = append(targets, id)
+	}
+	return targets, nil
+}
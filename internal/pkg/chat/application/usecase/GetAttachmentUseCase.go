@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// AttachmentDownloadExpiry is how long a presigned download stays valid.
+const AttachmentDownloadExpiry = 15 * time.Minute
+
+// GetAttachmentInput asks for a download link to an attachment on behalf of RequesterID.
+type GetAttachmentInput struct {
+	AttachmentID string
+	RequesterID  string
+}
+
+// GetAttachmentOutput is the attachment and a fresh request that downloads it, plus one for its thumbnail
+// once processing generated one.
+type GetAttachmentOutput struct {
+	Attachment chat.Attachment
+	Download   storageport.PresignedRequest
+	Thumbnail  *storageport.PresignedRequest
+}
+
+// GetAttachmentUseCase signs downloads for the owner of an attachment and for the participants who can
+// see the message it backs, unless processing rejected it. Everyone else is told it does not exist.
+type GetAttachmentUseCase struct {
+	Repo    repository.ChatRepository
+	Storage storageport.Storage
+}
+
+func NewGetAttachmentUseCase(repo repository.ChatRepository, storage storageport.Storage) *GetAttachmentUseCase {
+	return &GetAttachmentUseCase{Repo: repo, Storage: storage}
+}
+
+func (uc *GetAttachmentUseCase) Execute(ctx context.Context, in GetAttachmentInput) (*GetAttachmentOutput, error) {
+	if in.AttachmentID == "" || in.RequesterID == "" {
+		return nil, fmt.Errorf("attachmentId and requesterId are required")
+	}
+	a, err := loadAttachment(ctx, uc.Repo, in.AttachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.OwnerID != in.RequesterID {
+		if err := uc.checkViewer(ctx, a, in.RequesterID); err != nil {
+			return nil, err
+		}
+		// Files whose real type the deployment does not accept are not handed out
+		if a.Rejected {
+			return nil, chat.ErrAttachmentRejected
+		}
+	}
+
+	download, err := uc.Storage.PresignGet(ctx, a.StorageKey, AttachmentDownloadExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	out := &GetAttachmentOutput{Attachment: a, Download: download}
+	if a.ThumbnailKey != nil {
+		thumbnail, err := uc.Storage.PresignGet(ctx, *a.ThumbnailKey, AttachmentDownloadExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+		}
+		out.Thumbnail = &thumbnail
+	}
+	return out, nil
+}
+
+// checkViewer lets userID through when they can see the live message carrying a.
+func (uc *GetAttachmentUseCase) checkViewer(ctx context.Context, a chat.Attachment, userID string) error {
+	msg, err := uc.Repo.GetMessageByAttachment(ctx, a.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	c, err := loadChat(ctx, uc.Repo, msg.ConversationID)
+	if err != nil {
+		return err
+	}
+	if !c.CanView(userID, msg) {
+		return chat.ErrAttachmentNotFound
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	chat "go-chatty/internal/pkg/chat/application/domain"
 	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
@@ -12,8 +13,20 @@ import (
 // Variables with multiple items use plural (e.g., messages slice in the return)
 type GetMessageInput struct {
 	ConversationID string
+	RequesterID    string // authenticated reader; must be a participant
 	Limit          int
-	Offset         int
+	Offset         int    // legacy paging; ignored when a cursor is given
+	Before         string // opaque cursor: page of messages older than it
+	After          string // opaque cursor: page of messages newer than it
+	Around         string // opaque cursor: page centered on it (inclusive)
+}
+
+// GetMessageOutput is a page of messages, newest first, with cursors to continue paging
+type GetMessageOutput struct {
+	Messages   []chat.Message
+	NextCursor *string                         // pass as Before to fetch older messages; nil when no older page exists
+	PrevCursor *string                         // pass as After to fetch newer messages; nil when the page is empty
+	Reactions  map[string][]chat.ReactionCount // per message id, as seen by the requester
 }
 
 // GetMessageUseCase fetches messages for a given conversation
@@ -27,14 +40,113 @@ func NewGetMessageUseCase(repo repository.ChatRepository) *GetMessageUseCase {
 	return &GetMessageUseCase{Repo: repo}
 }
 
-// Execute returns messages for the conversation honoring limit/offset
-func (uc *GetMessageUseCase) Execute(ctx context.Context, in GetMessageInput) ([]chat.Message, error) {
-	if in.ConversationID == "" {
-		return nil, fmt.Errorf("conversationId is required")
+// Execute returns messages for the conversation honoring cursors or limit/offset.
+// Only participants may read; in groups that hide history from newcomers,
+// messages sent before the requester joined are left out. Thread replies are not part of
+// the main timeline; roots carry their reply count and GetThreadUseCase pages the replies.
+func (uc *GetMessageUseCase) Execute(ctx context.Context, in GetMessageInput) (*GetMessageOutput, error) {
+	if in.ConversationID == "" || in.RequesterID == "" {
+		return nil, fmt.Errorf("conversationId and requesterId are required")
+	}
+
+	q := repository.MessageQuery{
+		ConversationID: in.ConversationID,
+		Limit:          in.Limit,
+		Offset:         in.Offset,
+	}
+	if err := applyCursors(&q, in.Before, in.After, in.Around); err != nil {
+		return nil, err
+	}
+
+	participant, err := loadParticipant(ctx, uc.Repo, in.ConversationID, in.RequesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The conversation may have been deleted since the membership was read
+	conv, err := uc.Repo.GetConversation(ctx, in.ConversationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, chat.ErrNotParticipant
 	}
-	msgs, err := uc.Repo.GetMessagesByConversation(ctx, in.ConversationID, in.Limit, in.Offset)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
 	}
-	return msgs, nil
+
+	if conv.IsHistoryHiddenFromNewcomers() {
+		q.Since = &participant.JoinedAt
+	}
+
+	return fetchPage(ctx, uc.Repo, q, in.RequesterID)
+}
+
+// fetchPage runs q and adds cursors and the requester's view of reactions; reactions of extraIDs
+// (e.g. a thread root shown above the page) are counted along with the page's.
+func fetchPage(ctx context.Context, repo repository.ChatRepository, q repository.MessageQuery, requesterID string, extraIDs ...string) (*GetMessageOutput, error) {
+	msgs, err := repo.GetMessagesByConversation(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	out := &GetMessageOutput{Messages: msgs}
+	ids := append([]string(nil), extraIDs...)
+	for _, m := range msgs {
+		ids = append(ids, m.ID)
+	}
+	if len(ids) > 0 {
+		out.Reactions, err = repo.CountReactions(ctx, ids, requesterID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+		}
+	}
+	if len(msgs) == 0 {
+		return out, nil
+	}
+
+	prev := chat.CursorOf(msgs[0]).Encode()
+	out.PrevCursor = &prev
+	// A short page going backwards means the start of the conversation was reached
+	if q.After != nil || q.Around != nil || len(msgs) >= effectiveLimit(q.Limit) {
+		next := chat.CursorOf(msgs[len(msgs)-1]).Encode()
+		out.NextCursor = &next
+	}
+	return out, nil
+}
+
+func applyCursors(q *repository.MessageQuery, before string, after string, around string) error {
+	set := 0
+	for _, raw := range []string{before, after, around} {
+		if raw != "" {
+			set++
+		}
+	}
+	if set == 0 {
+		return nil
+	}
+	if set > 1 {
+		return fmt.Errorf("only one of before, after or around may be set")
+	}
+
+	raw := before + after + around
+	cursor, err := chat.DecodeMessageCursor(raw)
+	if err != nil {
+		return err
+	}
+	switch {
+	case before != "":
+		q.Before = &cursor
+	case after != "":
+		q.After = &cursor
+	default:
+		q.Around = &cursor
+	}
+	q.Offset = 0
+	return nil
+}
+
+// effectiveLimit mirrors the repository default so page-fullness checks agree with it
+func effectiveLimit(limit int) int {
+	if limit <= 0 {
+		return 50
+	}
+	return limit
 }
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// goneConversationRepo still finds the membership of a conversation that is deleted meanwhile.
+type goneConversationRepo struct {
+	repository.ChatRepository
+}
+
+func (goneConversationRepo) GetParticipant(_ context.Context, conversationID string, userID string) (chat.Participant, error) {
+	return chat.Participant{ConversationID: conversationID, UserID: userID}, nil
+}
+
+func (goneConversationRepo) GetConversation(context.Context, string) (chat.Conversation, error) {
+	return chat.Conversation{}, repository.ErrNotFound
+}
+
+func TestGetMessageHidesMissingConversations(t *testing.T) {
+	uc := NewGetMessageUseCase(goneConversationRepo{})
+	for _, id := range []string{"not-a-uuid", pushTestConversation} {
+		_, err := uc.Execute(context.Background(), GetMessageInput{ConversationID: id, RequesterID: "alice"})
+		if !errors.Is(err, chat.ErrNotParticipant) {
+			t.Fatalf("Execute(%q): err = %v, want ErrNotParticipant", id, err)
+		}
+	}
+}
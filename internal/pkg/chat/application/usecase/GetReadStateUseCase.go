@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// GetReadStateInput identifies the conversation and the participant asking for its read state.
+type GetReadStateInput struct {
+	ConversationID string
+	RequesterID    string
+}
+
+// GetReadStateOutput holds the requester's unread count and every member's read watermark.
+type GetReadStateOutput struct {
+	UnreadCount int
+	Members     []chat.Participant
+}
+
+// GetReadStateUseCase returns read positions of a conversation to its participants.
+type GetReadStateUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewGetReadStateUseCase(repo repository.ChatRepository) *GetReadStateUseCase {
+	return &GetReadStateUseCase{Repo: repo}
+}
+
+func (uc *GetReadStateUseCase) Execute(ctx context.Context, in GetReadStateInput) (*GetReadStateOutput, error) {
+	if in.ConversationID == "" || in.RequesterID == "" {
+		return nil, fmt.Errorf("conversationId and requesterId are required")
+	}
+
+	unread, err := uc.Repo.CountUnread(ctx, in.ConversationID, in.RequesterID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, chat.ErrNotParticipant
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	members, err := uc.Repo.ListParticipants(ctx, in.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	return &GetReadStateOutput{UnreadCount: unread, Members: members}, nil
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// GetThreadInput selects a page of the replies in the thread under RootID.
+// Cursors behave as in GetMessageInput.
+type GetThreadInput struct {
+	ConversationID string
+	RootID         string
+	RequesterID    string
+	Limit          int
+	Before         string
+	After          string
+	Around         string
+}
+
+// GetThreadOutput is the thread root with a page of its replies, newest first.
+// Replies.Reactions also covers the root.
+type GetThreadOutput struct {
+	Root    chat.Message
+	Replies *GetMessageOutput
+}
+
+// GetThreadUseCase pages through the replies of a thread.
+type GetThreadUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewGetThreadUseCase(repo repository.ChatRepository) *GetThreadUseCase {
+	return &GetThreadUseCase{Repo: repo}
+}
+
+func (uc *GetThreadUseCase) Execute(ctx context.Context, in GetThreadInput) (*GetThreadOutput, error) {
+	if in.ConversationID == "" || in.RootID == "" || in.RequesterID == "" {
+		return nil, fmt.Errorf("conversationId, messageId and requesterId are required")
+	}
+
+	q := repository.MessageQuery{
+		ConversationID: in.ConversationID,
+		ThreadRootID:   &in.RootID,
+		Limit:          in.Limit,
+	}
+	if err := applyCursors(&q, in.Before, in.After, in.Around); err != nil {
+		return nil, err
+	}
+
+	// Replies are newer than a root the requester may see, so no history cut-off applies to them
+	root, err := loadThreadRoot(ctx, uc.Repo, in.ConversationID, in.RootID, in.RequesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	replies, err := fetchPage(ctx, uc.Repo, q, in.RequesterID, root.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetThreadOutput{Root: root, Replies: replies}, nil
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// JoinThreadInput validates a request to follow the thread under RootID in realtime.
+type JoinThreadInput struct {
+	ConversationID string
+	RootID         string
+	UserID         string
+}
+
+// JoinThreadUseCase ensures the user may read a thread before its session joins the thread's realtime room.
+type JoinThreadUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewJoinThreadUseCase(repo repository.ChatRepository) *JoinThreadUseCase {
+	return &JoinThreadUseCase{Repo: repo}
+}
+
+func (uc *JoinThreadUseCase) Execute(ctx context.Context, in JoinThreadInput) error {
+	if in.ConversationID == "" || in.RootID == "" || in.UserID == "" {
+		return fmt.Errorf("conversationId, messageId and userId are required")
+	}
+	_, err := loadThreadRoot(ctx, uc.Repo, in.ConversationID, in.RootID, in.UserID)
+	return err
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// LeaveConversationInput carries a request from a member to leave a group.
+type LeaveConversationInput struct {
+	ConversationID string
+	UserID         string
+}
+
+// LeaveConversationUseCase removes the requesting member from a group.
+type LeaveConversationUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewLeaveConversationUseCase(repo repository.ChatRepository) *LeaveConversationUseCase {
+	return &LeaveConversationUseCase{Repo: repo}
+}
+
+// Execute removes the member and returns the system message recording the departure.
+func (uc *LeaveConversationUseCase) Execute(ctx context.Context, in LeaveConversationInput) (*chat.Message, error) {
+	if in.ConversationID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId and userId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := c.Leave(in.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	stored, err := uc.Repo.RemoveParticipant(ctx, in.ConversationID, in.UserID, msg)
+	switch {
+	case errors.Is(err, repository.ErrLastOwner):
+		// The membership changed since it was loaded and this change would now leave no owner
+		return nil, chat.ErrLastOwner
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, chat.ErrNotParticipant
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &stored, nil
+}
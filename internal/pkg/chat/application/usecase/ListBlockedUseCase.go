@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ListBlockedInput identifies the user whose blocks are listed.
+type ListBlockedInput struct {
+	BlockerID string
+}
+
+// ListBlockedUseCase returns the blocks created by a user, newest first.
+type ListBlockedUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewListBlockedUseCase(repo repository.ChatRepository) *ListBlockedUseCase {
+	return &ListBlockedUseCase{Repo: repo}
+}
+
+func (uc *ListBlockedUseCase) Execute(ctx context.Context, in ListBlockedInput) ([]chat.Block, error) {
+	if in.BlockerID == "" {
+		return nil, fmt.Errorf("blockerId is required")
+	}
+
+	blocks, err := uc.Repo.ListBlocked(ctx, in.BlockerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return blocks, nil
+}
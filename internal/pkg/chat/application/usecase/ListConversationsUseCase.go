@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+const (
+	// MaxInboxLimit bounds the page size of the inbox.
+	MaxInboxLimit     = 100
+	defaultInboxLimit = 20
+	// inboxOthersLimit caps the other participants listed per conversation; large groups only show a sample.
+	inboxOthersLimit = 10
+)
+
+// ListConversationsInput identifies the user whose inbox is listed. Before is the opaque cursor of the previous page.
+type ListConversationsInput struct {
+	UserID string
+	Before string
+	Limit  int
+}
+
+// ListConversationsOutput is a page of the inbox, most recently active first. NextCursor fetches the
+// following page and is nil on the last one.
+type ListConversationsOutput struct {
+	Entries    []chat.InboxEntry
+	NextCursor *string
+}
+
+// ListConversationsUseCase lists a user's conversations by last activity, each with its latest message,
+// the user's unread count and mute state, and the other participants.
+type ListConversationsUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewListConversationsUseCase(repo repository.ChatRepository) *ListConversationsUseCase {
+	return &ListConversationsUseCase{Repo: repo}
+}
+
+func (uc *ListConversationsUseCase) Execute(ctx context.Context, in ListConversationsInput) (*ListConversationsOutput, error) {
+	if in.UserID == "" {
+		return nil, fmt.Errorf("userId is required")
+	}
+	q := repository.InboxQuery{UserID: in.UserID, Limit: in.Limit, OthersLimit: inboxOthersLimit}
+	if q.Limit <= 0 {
+		q.Limit = defaultInboxLimit
+	}
+	q.Limit = min(q.Limit, MaxInboxLimit)
+	if in.Before != "" {
+		cursor, err := chat.DecodeConversationCursor(in.Before)
+		if err != nil {
+			return nil, err
+		}
+		q.Before = &cursor
+	}
+
+	entries, err := uc.Repo.ListInbox(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	for i, e := range entries {
+		if e.LastMessage == nil {
+			continue
+		}
+		// A newcomer to a group hiding its history gets no preview of a message sent before they joined
+		c := chat.Chat{
+			Conversation: e.Conversation,
+			Participants: map[string]chat.Participant{in.UserID: e.Membership},
+		}
+		if !c.CanView(in.UserID, *e.LastMessage) {
+			entries[i].LastMessage = nil
+		}
+	}
+
+	out := &ListConversationsOutput{Entries: entries}
+	if len(entries) == q.Limit {
+		next := chat.ConversationCursorOf(entries[len(entries)-1].Conversation).Encode()
+		out.NextCursor = &next
+	}
+	return out, nil
+}
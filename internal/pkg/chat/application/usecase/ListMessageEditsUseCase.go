@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ListMessageEditsInput identifies a message and the participant asking for its edit history.
+type ListMessageEditsInput struct {
+	ConversationID string
+	MessageID      string
+	RequesterID    string
+}
+
+// ListMessageEditsUseCase returns the previous bodies of a message, oldest first.
+type ListMessageEditsUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewListMessageEditsUseCase(repo repository.ChatRepository) *ListMessageEditsUseCase {
+	return &ListMessageEditsUseCase{Repo: repo}
+}
+
+func (uc *ListMessageEditsUseCase) Execute(ctx context.Context, in ListMessageEditsInput) ([]chat.MessageEdit, error) {
+	if in.ConversationID == "" || in.MessageID == "" || in.RequesterID == "" {
+		return nil, fmt.Errorf("conversationId, messageId and requesterId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.HasParticipant(in.RequesterID) {
+		return nil, chat.ErrNotParticipant
+	}
+	msg, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	// Newcomers to groups with hidden history must not learn about older messages
+	if !c.CanView(in.RequesterID, msg) {
+		return nil, chat.ErrMessageNotFound
+	}
+
+	edits, err := uc.Repo.ListMessageEdits(ctx, in.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return edits, nil
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/google/uuid"
+)
+
+// ListNotificationRecipientsInput names the stored message whose notifications are fanned out.
+type ListNotificationRecipientsInput struct {
+	MessageID string
+}
+
+// ListNotificationRecipientsOutput is the message and the participants to notify of it, ordered by user id.
+type ListNotificationRecipientsOutput struct {
+	Message    chat.Message
+	Recipients []chat.Participant
+}
+
+// ListNotificationRecipientsUseCase decides who is notified of a new message, e.g. with a push: the participants
+// who can see it and want to hear about it given their mute and notification level. Users the sender blocked,
+// and users who blocked the sender, are left out. Realtime delivery to open sessions does not go through it.
+type ListNotificationRecipientsUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewListNotificationRecipientsUseCase(repo repository.ChatRepository) *ListNotificationRecipientsUseCase {
+	return &ListNotificationRecipientsUseCase{Repo: repo}
+}
+
+func (uc *ListNotificationRecipientsUseCase) Execute(ctx context.Context, in ListNotificationRecipientsInput) (*ListNotificationRecipientsOutput, error) {
+	if _, err := uuid.Parse(in.MessageID); err != nil {
+		return nil, chat.ErrMessageNotFound
+	}
+	msg, err := uc.Repo.GetMessage(ctx, in.MessageID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, chat.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	out := &ListNotificationRecipientsOutput{Message: msg}
+	if msg.MsgType == chat.MessageTypeSystem || msg.IsDeleted() {
+		return out, nil
+	}
+
+	c, err := loadChat(ctx, uc.Repo, msg.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]bool)
+	blocked, err := uc.Repo.ListBlocked(ctx, msg.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	for _, b := range blocked {
+		skip[b.BlockedId] = true
+	}
+	blockers, err := uc.Repo.ListBlockedBy(ctx, msg.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	for _, b := range blockers {
+		skip[b.BlockerId] = true
+	}
+
+	now := time.Now()
+	for userID, p := range c.Participants {
+		if skip[userID] || !c.CanView(userID, msg) || !p.WantsNotification(msg, now) {
+			continue
+		}
+		out.Recipients = append(out.Recipients, p)
+	}
+	sort.Slice(out.Recipients, func(i, j int) bool { return out.Recipients[i].UserID < out.Recipients[j].UserID })
+	return out, nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ListPresenceAudienceInput identifies the user whose presence changed.
+type ListPresenceAudienceInput struct {
+	UserID string
+}
+
+// ListPresenceAudienceUseCase lists who may follow a user's presence: everyone sharing a conversation
+// with them, except the users they blocked.
+type ListPresenceAudienceUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewListPresenceAudienceUseCase(repo repository.ChatRepository) *ListPresenceAudienceUseCase {
+	return &ListPresenceAudienceUseCase{Repo: repo}
+}
+
+func (uc *ListPresenceAudienceUseCase) Execute(ctx context.Context, in ListPresenceAudienceInput) ([]string, error) {
+	if in.UserID == "" {
+		return nil, fmt.Errorf("userId is required")
+	}
+
+	contacts, err := uc.Repo.ListContactIDs(ctx, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	blocks, err := uc.Repo.ListBlocked(ctx, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	blocked := make(map[string]struct{}, len(blocks))
+	for _, b := range blocks {
+		blocked[b.BlockedId] = struct{}{}
+	}
+
+	audience := make([]string, 0, len(contacts))
+	for _, id := range contacts {
+		if _, ok := blocked[id]; !ok {
+			audience = append(audience, id)
+		}
+	}
+	return audience, nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ListReceiptsInput identifies a message and the participant asking for its receipts.
+type ListReceiptsInput struct {
+	ConversationID string
+	MessageID      string
+	RequesterID    string
+}
+
+// ListReceiptsUseCase returns the per-recipient receipts of a message to conversation participants.
+type ListReceiptsUseCase struct {
+	Repo     repository.ChatRepository
+	Receipts repository.ReceiptRepository
+}
+
+func NewListReceiptsUseCase(repo repository.ChatRepository, receipts repository.ReceiptRepository) *ListReceiptsUseCase {
+	return &ListReceiptsUseCase{Repo: repo, Receipts: receipts}
+}
+
+func (uc *ListReceiptsUseCase) Execute(ctx context.Context, in ListReceiptsInput) ([]chat.Receipt, error) {
+	if in.ConversationID == "" || in.MessageID == "" || in.RequesterID == "" {
+		return nil, fmt.Errorf("conversationId, messageId and requesterId are required")
+	}
+
+	ok, err := uc.Repo.IsParticipant(ctx, in.ConversationID, in.RequesterID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if !ok {
+		return nil, chat.ErrNotParticipant
+	}
+
+	if _, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID); err != nil {
+		return nil, err
+	}
+
+	receipts, err := uc.Receipts.ListReceipts(ctx, in.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return receipts, nil
+}
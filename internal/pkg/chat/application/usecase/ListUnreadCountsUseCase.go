@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ListUnreadCountsInput identifies the user whose unread counters are listed.
+type ListUnreadCountsInput struct {
+	UserID string
+}
+
+// ListUnreadCountsUseCase returns the unread message count of every conversation of a user.
+type ListUnreadCountsUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewListUnreadCountsUseCase(repo repository.ChatRepository) *ListUnreadCountsUseCase {
+	return &ListUnreadCountsUseCase{Repo: repo}
+}
+
+func (uc *ListUnreadCountsUseCase) Execute(ctx context.Context, in ListUnreadCountsInput) (map[string]int, error) {
+	if in.UserID == "" {
+		return nil, fmt.Errorf("userId is required")
+	}
+
+	counts, err := uc.Repo.ListUnreadCounts(ctx, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// MaxDeliveryLimit bounds the page size of a delivery listing.
+const (
+	MaxDeliveryLimit     = 100
+	defaultDeliveryLimit = 20
+)
+
+// ListWebhookDeliveriesInput pages through the deliveries of a subscription of TenantID, optionally only those
+// with Status; Before is the opaque cursor of the previous page.
+type ListWebhookDeliveriesInput struct {
+	TenantID  string
+	WebhookID string
+	Status    *chat.DeliveryStatus
+	Before    string
+	Limit     int
+}
+
+// ListWebhookDeliveriesOutput is a page of deliveries, newest first. NextCursor fetches the following page and is nil on the last one.
+type ListWebhookDeliveriesOutput struct {
+	Deliveries []chat.WebhookDelivery
+	NextCursor *string
+}
+
+// ListWebhookDeliveriesUseCase shows what happened to the events sent to a subscription; listing the dead
+// deliveries gives its dead-letter queue.
+type ListWebhookDeliveriesUseCase struct {
+	Webhooks repository.WebhookRepository
+}
+
+func NewListWebhookDeliveriesUseCase(webhooks repository.WebhookRepository) *ListWebhookDeliveriesUseCase {
+	return &ListWebhookDeliveriesUseCase{Webhooks: webhooks}
+}
+
+func (uc *ListWebhookDeliveriesUseCase) Execute(ctx context.Context, in ListWebhookDeliveriesInput) (*ListWebhookDeliveriesOutput, error) {
+	if _, err := loadWebhook(ctx, uc.Webhooks, in.TenantID, in.WebhookID); err != nil {
+		return nil, err
+	}
+
+	q := repository.WebhookDeliveryQuery{SubscriptionID: in.WebhookID, Status: in.Status, Limit: in.Limit}
+	if q.Limit <= 0 {
+		q.Limit = defaultDeliveryLimit
+	}
+	q.Limit = min(q.Limit, MaxDeliveryLimit)
+	if in.Before != "" {
+		cursor, err := chat.DecodeDeliveryCursor(in.Before)
+		if err != nil {
+			return nil, err
+		}
+		q.Before = &cursor
+	}
+
+	deliveries, err := uc.Webhooks.ListWebhookDeliveries(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	out := &ListWebhookDeliveriesOutput{Deliveries: deliveries}
+	if len(deliveries) == q.Limit {
+		next := chat.DeliveryCursorOf(deliveries[len(deliveries)-1]).Encode()
+		out.NextCursor = &next
+	}
+	return out, nil
+}
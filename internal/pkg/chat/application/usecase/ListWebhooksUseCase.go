@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ListWebhooksInput names the tenant whose subscriptions are listed ("" for none).
+type ListWebhooksInput struct {
+	TenantID string
+}
+
+// ListWebhooksUseCase lists a tenant's webhook subscriptions, oldest first.
+type ListWebhooksUseCase struct {
+	Webhooks repository.WebhookRepository
+}
+
+func NewListWebhooksUseCase(webhooks repository.WebhookRepository) *ListWebhooksUseCase {
+	return &ListWebhooksUseCase{Webhooks: webhooks}
+}
+
+func (uc *ListWebhooksUseCase) Execute(ctx context.Context, in ListWebhooksInput) ([]chat.WebhookSubscription, error) {
+	subs, err := uc.Webhooks.ListWebhookSubscriptions(ctx, in.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return subs, nil
+}
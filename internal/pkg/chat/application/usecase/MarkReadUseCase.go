@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// MarkReadInput moves UserID's read watermark in a conversation to MessageID.
+type MarkReadInput struct {
+	ConversationID string
+	UserID         string
+	MessageID      string
+}
+
+// MarkReadOutput tells whether the watermark moved; stale or repeated reads leave it unchanged.
+type MarkReadOutput struct {
+	Receipt  chat.ReadReceipt
+	Advanced bool
+}
+
+// MarkReadUseCase advances a participant's read watermark monotonically.
+type MarkReadUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewMarkReadUseCase(repo repository.ChatRepository) *MarkReadUseCase {
+	return &MarkReadUseCase{Repo: repo}
+}
+
+func (uc *MarkReadUseCase) Execute(ctx context.Context, in MarkReadInput) (*MarkReadOutput, error) {
+	if in.ConversationID == "" || in.UserID == "" || in.MessageID == "" {
+		return nil, fmt.Errorf("conversationId, userId and messageId are required")
+	}
+
+	if _, err := uc.Repo.GetParticipant(ctx, in.ConversationID, in.UserID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, chat.ErrNotParticipant
+		}
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	if _, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID); err != nil {
+		return nil, err
+	}
+
+	at := time.Now().UTC()
+	seq, advanced, err := uc.Repo.AdvanceReadState(ctx, in.ConversationID, in.UserID, in.MessageID, at)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	return &MarkReadOutput{
+		Receipt: chat.ReadReceipt{
+			ConversationID: in.ConversationID,
+			UserID:         in.UserID,
+			MessageID:      in.MessageID,
+			At:             at,
+			Seq:            seq,
+		},
+		Advanced: advanced,
+	}, nil
+}
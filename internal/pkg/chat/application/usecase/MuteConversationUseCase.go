@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// MuteConversationInput carries a participant's request to mute a conversation until a time, or indefinitely when Until is nil.
+type MuteConversationInput struct {
+	ConversationID string
+	UserID         string
+	Until          *time.Time
+}
+
+// MuteConversationUseCase silences the notifications of a conversation for one participant.
+// Messages keep reaching the participant's inbox and open sessions.
+type MuteConversationUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewMuteConversationUseCase(repo repository.ChatRepository) *MuteConversationUseCase {
+	return &MuteConversationUseCase{Repo: repo}
+}
+
+// Execute stores the mute and returns the participant's updated settings.
+func (uc *MuteConversationUseCase) Execute(ctx context.Context, in MuteConversationInput) (*chat.Participant, error) {
+	if in.ConversationID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId and userId are required")
+	}
+	until := chat.MutedIndefinitely
+	if in.Until != nil {
+		if !in.Until.After(time.Now()) {
+			return nil, fmt.Errorf("until must be in the future")
+		}
+		if in.Until.Before(chat.MutedIndefinitely) {
+			until = in.Until.UTC()
+		}
+	}
+
+	p, err := loadParticipant(ctx, uc.Repo, in.ConversationID, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.Repo.SetMuteUntil(ctx, in.ConversationID, in.UserID, &until)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, chat.ErrNotParticipant
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	p.MutedUntil = &until
+	return &p, nil
+}
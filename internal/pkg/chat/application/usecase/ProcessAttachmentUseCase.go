@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	mediaport "go-chatty/internal/infrastructure/media/port"
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ProcessAttachmentInput names the uploaded attachment to inspect.
+type ProcessAttachmentInput struct {
+	AttachmentID string
+}
+
+// ProcessAttachmentOutput is the processed attachment and, when a live message carries it, that message
+// with its refreshed attachment metadata.
+type ProcessAttachmentOutput struct {
+	Attachment chat.Attachment
+	Message    *chat.Message
+}
+
+// ProcessAttachmentUseCase inspects an uploaded attachment in the background: it sniffs the real content type,
+// computes a checksum, measures images and stores a thumbnail next to the original. A sniffed type Policy does
+// not accept marks the attachment rejected. The results end up in the attachment_meta of the message carrying it.
+// Attachments are processed once; later runs only bring a message that was sent meanwhile up to date.
+type ProcessAttachmentUseCase struct {
+	Repo     repository.ChatRepository
+	Storage  storageport.Storage
+	Analyzer mediaport.Analyzer
+	Policy   storageport.Policy
+}
+
+func NewProcessAttachmentUseCase(repo repository.ChatRepository, storage storageport.Storage, analyzer mediaport.Analyzer, policy storageport.Policy) *ProcessAttachmentUseCase {
+	return &ProcessAttachmentUseCase{Repo: repo, Storage: storage, Analyzer: analyzer, Policy: policy}
+}
+
+func (uc *ProcessAttachmentUseCase) Execute(ctx context.Context, in ProcessAttachmentInput) (*ProcessAttachmentOutput, error) {
+	a, err := loadAttachment(ctx, uc.Repo, in.AttachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.ProcessedAt != nil {
+		return uc.refreshMessage(ctx, a)
+	}
+
+	r, err := uc.Storage.Open(ctx, a.StorageKey)
+	if errors.Is(err, storageport.ErrNotFound) {
+		return nil, chat.ErrAttachmentMissing
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+	defer func() { _ = r.Close() }()
+
+	// Only the declared size is inspected, which is all a download may return
+	analysis, err := uc.Analyzer.Analyze(ctx, io.LimitReader(r, a.Size))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+	}
+
+	if t := analysis.Thumbnail; t != nil {
+		key := "thumbnails/" + a.ID
+		if err := uc.Storage.Put(ctx, key, t.ContentType, bytes.NewReader(t.Data), int64(len(t.Data))); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrStorage, err)
+		}
+		a.ThumbnailKey = &key
+	}
+	a.SniffedType = &analysis.ContentType
+	a.Checksum = &analysis.Checksum
+	if analysis.Width > 0 && analysis.Height > 0 {
+		a.Width, a.Height = &analysis.Width, &analysis.Height
+	}
+	a.Rejected = uc.Policy.Check(analysis.ContentType, a.Size) != nil
+	now := time.Now().UTC()
+	a.ProcessedAt = &now
+
+	msg, updated, err := uc.Repo.SaveAttachmentProcessing(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	out := &ProcessAttachmentOutput{Attachment: a}
+	if updated {
+		out.Message = &msg
+	}
+	return out, nil
+}
+
+// refreshMessage stores the results of an already processed attachment again when the live message carrying
+// it was built before they were known, e.g. because it was sent while processing ran.
+func (uc *ProcessAttachmentUseCase) refreshMessage(ctx context.Context, a chat.Attachment) (*ProcessAttachmentOutput, error) {
+	out := &ProcessAttachmentOutput{Attachment: a}
+	msg, err := uc.Repo.GetMessageByAttachment(ctx, a.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if msg.IsDeleted() || (msg.AttachmentMeta != nil && *msg.AttachmentMeta == a.Metadata()) {
+		return out, nil
+	}
+
+	msg, updated, err := uc.Repo.SaveAttachmentProcessing(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if updated {
+		out.Message = &msg
+	}
+	return out, nil
+}
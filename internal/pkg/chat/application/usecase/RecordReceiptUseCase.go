@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/google/uuid"
+)
+
+// RecordReceiptInput carries a batch of receipts collected from the realtime layer.
+type RecordReceiptInput struct {
+	Receipts []chat.Receipt
+}
+
+// RecordReceiptUseCase stores receipts in bulk and returns the ones that were new,
+// so that senders are only notified once per recipient and status.
+type RecordReceiptUseCase struct {
+	Repo repository.ReceiptRepository
+}
+
+func NewRecordReceiptUseCase(repo repository.ReceiptRepository) *RecordReceiptUseCase {
+	return &RecordReceiptUseCase{Repo: repo}
+}
+
+func (uc *RecordReceiptUseCase) Execute(ctx context.Context, in RecordReceiptInput) ([]chat.Receipt, error) {
+	// Drop malformed entries instead of failing the whole batch; a sender never receipts its own message
+	valid := make([]chat.Receipt, 0, len(in.Receipts))
+	for _, rc := range in.Receipts {
+		if rc.UserID == "" || rc.UserID == rc.SenderID || rc.At.IsZero() {
+			continue
+		}
+		if _, err := uuid.Parse(rc.MessageID); err != nil {
+			continue
+		}
+		if _, err := uuid.Parse(rc.UserID); err != nil {
+			continue
+		}
+		valid = append(valid, rc)
+	}
+	if len(valid) == 0 {
+		return nil, nil
+	}
+
+	saved, err := uc.Repo.SaveReceipts(ctx, valid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return saved, nil
+}
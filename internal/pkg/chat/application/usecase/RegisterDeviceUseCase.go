@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// maxDeviceIDLength matches the width of the device_id column.
+const maxDeviceIDLength = 64
+
+// RegisterDeviceInput carries the push token of one of the user's app installs. DeviceID, when set,
+// lets a new token of the same install replace the old one.
+type RegisterDeviceInput struct {
+	UserID   string
+	Token    string
+	Platform chat.DevicePlatform
+	DeviceID *string
+}
+
+// RegisterDeviceUseCase records where to push notifications for a user.
+type RegisterDeviceUseCase struct {
+	Devices repository.DeviceRepository
+}
+
+func NewRegisterDeviceUseCase(devices repository.DeviceRepository) *RegisterDeviceUseCase {
+	return &RegisterDeviceUseCase{Devices: devices}
+}
+
+func (uc *RegisterDeviceUseCase) Execute(ctx context.Context, in RegisterDeviceInput) (*chat.DeviceToken, error) {
+	if in.UserID == "" {
+		return nil, fmt.Errorf("userId is required")
+	}
+	token := strings.TrimSpace(in.Token)
+	if token == "" || len(token) > chat.MaxDeviceTokenLength {
+		return nil, chat.ErrInvalidDeviceToken
+	}
+	if in.Platform != chat.DevicePlatformFCM && in.Platform != chat.DevicePlatformAPNs {
+		return nil, chat.ErrInvalidPlatform
+	}
+	if in.DeviceID != nil && *in.DeviceID == "" {
+		in.DeviceID = nil
+	}
+	if in.DeviceID != nil && len(*in.DeviceID) > maxDeviceIDLength {
+		return nil, fmt.Errorf("deviceId must be at most %d bytes", maxDeviceIDLength)
+	}
+
+	now := time.Now().UTC()
+	d := chat.DeviceToken{
+		Token:     token,
+		UserID:    in.UserID,
+		Platform:  in.Platform,
+		DeviceID:  in.DeviceID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := uc.Devices.SaveDeviceToken(ctx, d); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &d, nil
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// RemoveParticipantInput carries a request to remove a user from a group conversation.
+type RemoveParticipantInput struct {
+	ConversationID string
+	ActorID        string
+	UserID         string
+}
+
+// RemoveParticipantUseCase removes a member from a group on behalf of an admin or owner.
+type RemoveParticipantUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewRemoveParticipantUseCase(repo repository.ChatRepository) *RemoveParticipantUseCase {
+	return &RemoveParticipantUseCase{Repo: repo}
+}
+
+// Execute removes the participant and returns the system message recording the change.
+func (uc *RemoveParticipantUseCase) Execute(ctx context.Context, in RemoveParticipantInput) (*chat.Message, error) {
+	if in.ConversationID == "" || in.ActorID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId, actorId and userId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := c.RemoveParticipant(in.ActorID, in.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	stored, err := uc.Repo.RemoveParticipant(ctx, in.ConversationID, in.UserID, msg)
+	switch {
+	case errors.Is(err, repository.ErrLastOwner):
+		return nil, chat.ErrLastOwner
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, chat.ErrNotParticipant
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &stored, nil
+}
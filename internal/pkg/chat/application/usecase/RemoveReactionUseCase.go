@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// RemoveReactionInput carries a request from UserID to take back an emoji reaction.
+type RemoveReactionInput struct {
+	ConversationID string
+	MessageID      string
+	UserID         string
+	Emoji          string
+}
+
+// RemoveReactionOutput holds the message, the removed reaction and how many users still react with its emoji.
+// Removed is false when there was nothing to remove; otherwise Seq is the sequence number of the change.
+type RemoveReactionOutput struct {
+	Message  chat.Message
+	Reaction chat.Reaction
+	Count    int
+	Removed  bool
+	Seq      int64
+}
+
+// RemoveReactionUseCase removes a user's own reaction; removing a missing reaction is a no-op.
+type RemoveReactionUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewRemoveReactionUseCase(repo repository.ChatRepository) *RemoveReactionUseCase {
+	return &RemoveReactionUseCase{Repo: repo}
+}
+
+func (uc *RemoveReactionUseCase) Execute(ctx context.Context, in RemoveReactionInput) (*RemoveReactionOutput, error) {
+	if in.ConversationID == "" || in.MessageID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId, messageId and userId are required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := loadMessage(ctx, uc.Repo, in.ConversationID, in.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	reaction, err := c.Unreact(in.UserID, msg, in.Emoji)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := true
+	seq, err := uc.Repo.RemoveReaction(ctx, in.ConversationID, reaction.MessageID, reaction.UserID, reaction.Emoji)
+	if errors.Is(err, repository.ErrNotFound) {
+		removed = false
+	} else if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	count, err := reactionCount(ctx, uc.Repo, reaction)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoveReactionOutput{Message: msg, Reaction: reaction, Count: count, Removed: removed, Seq: seq}, nil
+}
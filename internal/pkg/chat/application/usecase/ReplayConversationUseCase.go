@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// MaxReplayChanges caps how many changes a single replay returns; clients that missed more
+// page through the history over HTTP instead.
+const MaxReplayChanges = 500
+
+// ReplayConversationInput asks for the changes to a conversation's message log after AfterSeq.
+type ReplayConversationInput struct {
+	ConversationID string
+	UserID         string
+	AfterSeq       int64
+}
+
+// ReplayChange is one replayed change: a message in its current state, or a participant's read watermark
+// at its latest position. Seq is the sequence number of the change.
+type ReplayChange struct {
+	Seq     int64
+	Message *chat.Message
+	Read    *chat.ReadReceipt
+}
+
+// ReplayConversationOutput lists the changed top-level messages and moved read watermarks in sequence order.
+// Reactions holds the current reaction counts of the replayed messages as seen by the user, keyed by message id.
+// Seq is the sequence number the client has caught up to; Truncated reports that more changes were left out.
+type ReplayConversationOutput struct {
+	Changes   []ReplayChange
+	Reactions map[string][]chat.ReactionCount
+	Seq       int64
+	Truncated bool
+}
+
+// ReplayConversationUseCase lets a reconnecting participant catch up on what it missed.
+// Thread replies are not replayed: the thread roots carry their new counters instead. Reaction changes
+// replay as the message they changed, carrying its current reactions.
+type ReplayConversationUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewReplayConversationUseCase(repo repository.ChatRepository) *ReplayConversationUseCase {
+	return &ReplayConversationUseCase{Repo: repo}
+}
+
+func (uc *ReplayConversationUseCase) Execute(ctx context.Context, in ReplayConversationInput) (*ReplayConversationOutput, error) {
+	if in.ConversationID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId and userId are required")
+	}
+	if in.AfterSeq < 0 {
+		return nil, fmt.Errorf("seq must not be negative")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.HasParticipant(in.UserID) {
+		return nil, chat.ErrNotParticipant
+	}
+
+	msgs, err := uc.Repo.ListMessageChanges(ctx, in.ConversationID, in.AfterSeq, MaxReplayChanges+1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	reads, err := uc.Repo.ListReadChanges(ctx, in.ConversationID, in.AfterSeq, MaxReplayChanges+1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	// Read receipts of users who blocked the replaying user are hidden from them live as well
+	blocks, err := uc.Repo.ListBlockedBy(ctx, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	blockedBy := make(map[string]bool, len(blocks))
+	for _, b := range blocks {
+		blockedBy[b.BlockerId] = true
+	}
+
+	out := &ReplayConversationOutput{Seq: in.AfterSeq}
+	var visible []string
+	// Both lists are in sequence order; merging them keeps the replay in order, and cutting it at
+	// MaxReplayChanges leaves no gap before out.Seq
+	for n := 0; len(msgs) > 0 || len(reads) > 0; n++ {
+		if n == MaxReplayChanges {
+			out.Truncated = true
+			break
+		}
+		if len(reads) == 0 || (len(msgs) > 0 && msgs[0].ChangeSeq < reads[0].Seq) {
+			m := msgs[0]
+			msgs = msgs[1:]
+			// Hidden history still advances the sequence, it is just not shown
+			out.Seq = m.ChangeSeq
+			if c.CanView(in.UserID, m) {
+				out.Changes = append(out.Changes, ReplayChange{Seq: m.ChangeSeq, Message: &m})
+				if !m.IsDeleted() {
+					visible = append(visible, m.ID)
+				}
+			}
+			continue
+		}
+		r := reads[0]
+		reads = reads[1:]
+		out.Seq = r.Seq
+		if !blockedBy[r.UserID] {
+			out.Changes = append(out.Changes, ReplayChange{Seq: r.Seq, Read: &r})
+		}
+	}
+
+	out.Reactions, err = uc.Repo.CountReactions(ctx, visible, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ReplayWebhookDeliveryInput names a delivery of a subscription of TenantID to send again.
+type ReplayWebhookDeliveryInput struct {
+	TenantID   string
+	WebhookID  string
+	DeliveryID string
+}
+
+// ReplayWebhookDeliveryUseCase puts a dead or delivered delivery back in line, with a fresh allowance of attempts.
+// The caller queues its first attempt.
+type ReplayWebhookDeliveryUseCase struct {
+	Webhooks repository.WebhookRepository
+}
+
+func NewReplayWebhookDeliveryUseCase(webhooks repository.WebhookRepository) *ReplayWebhookDeliveryUseCase {
+	return &ReplayWebhookDeliveryUseCase{Webhooks: webhooks}
+}
+
+func (uc *ReplayWebhookDeliveryUseCase) Execute(ctx context.Context, in ReplayWebhookDeliveryInput) (*chat.WebhookDelivery, error) {
+	if _, err := loadWebhook(ctx, uc.Webhooks, in.TenantID, in.WebhookID); err != nil {
+		return nil, err
+	}
+	d, err := loadDelivery(ctx, uc.Webhooks, in.WebhookID, in.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	prevStatus, prevAttempts := d.Status, d.Attempts
+	if err := d.Replay(time.Now()); err != nil {
+		return nil, err
+	}
+	err = uc.Webhooks.UpdateWebhookDelivery(ctx, d, prevStatus, prevAttempts)
+	if errors.Is(err, repository.ErrConflict) {
+		// Replayed concurrently: the other replay queues the attempt
+		return nil, chat.ErrDeliveryPending
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &d, nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// ResolveOutboxEntryInput carries an outbox entry about to be published.
+type ResolveOutboxEntryInput struct {
+	Entry chat.OutboxEntry
+}
+
+// ResolveOutboxEntryOutput is what publishing the entry announces. Message is the new message, nil for other
+// changes and for messages deleted before they were published; ThreadRoot is the root of a thread reply with
+// its current counters. Events are the events for webhooks, carrying the entry's EventID.
+type ResolveOutboxEntryOutput struct {
+	Message    *chat.Message
+	ThreadRoot *chat.Message
+	Events     []chat.Event
+}
+
+// ResolveOutboxEntryUseCase loads the change an outbox entry stands for. Entries only name the stored rows,
+// so a conversation or message removed meanwhile is reported as chat.ErrNotParticipant or chat.ErrMessageNotFound.
+type ResolveOutboxEntryUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewResolveOutboxEntryUseCase(repo repository.ChatRepository) *ResolveOutboxEntryUseCase {
+	return &ResolveOutboxEntryUseCase{Repo: repo}
+}
+
+func (uc *ResolveOutboxEntryUseCase) Execute(ctx context.Context, in ResolveOutboxEntryInput) (*ResolveOutboxEntryOutput, error) {
+	e := in.Entry
+	c, err := loadChat(ctx, uc.Repo, e.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ResolveOutboxEntryOutput{}
+	switch e.Kind {
+	case chat.OutboxConversationCreated:
+		// Members who joined later are announced by their own participant events
+		var initial []chat.Participant
+		for _, p := range c.Participants {
+			if !p.JoinedAt.After(c.Conversation.CreatedAt) {
+				initial = append(initial, p)
+			}
+		}
+		sort.Slice(initial, func(i, j int) bool { return initial[i].UserID < initial[j].UserID })
+		out.Events = append(out.Events, chat.NewConversationCreatedEvent(c.Conversation, initial))
+	case chat.OutboxMessageCreated:
+		if e.MessageID == nil {
+			return nil, chat.ErrMessageNotFound
+		}
+		msg, err := loadMessage(ctx, uc.Repo, e.ConversationID, *e.MessageID)
+		if err != nil {
+			return nil, err
+		}
+		if msg.IsDeleted() {
+			// Whoever could have seen it heard about the deletion already
+			return out, nil
+		}
+		out.Message = &msg
+		if msg.ThreadRootID != nil {
+			root, err := loadMessage(ctx, uc.Repo, e.ConversationID, *msg.ThreadRootID)
+			if err != nil {
+				return nil, err
+			}
+			out.ThreadRoot = &root
+		}
+		if msg.MsgType == chat.MessageTypeSystem {
+			if event, ok := chat.NewMembershipEvent(c.Conversation, msg); ok {
+				out.Events = append(out.Events, event)
+			}
+		} else {
+			out.Events = append(out.Events, chat.NewMessageCreatedEvent(c.Conversation, msg))
+		}
+	default:
+		return nil, fmt.Errorf("unknown outbox entry kind %d", e.Kind)
+	}
+
+	// Publishing an entry again announces the same events
+	for i := range out.Events {
+		out.Events[i].ID = e.EventID
+	}
+	return out, nil
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxSearchQueryLength bounds the search text, in characters.
+	MaxSearchQueryLength = 256
+	// MaxSearchLimit bounds the page size of a search.
+	MaxSearchLimit     = 100
+	defaultSearchLimit = 20
+)
+
+// SearchMessagesInput describes a full-text search on behalf of RequesterID. Optional filters narrow it down
+// to a conversation, a sender, a creation time range [From, To) and a message type; Before is the opaque
+// cursor of the previous page.
+type SearchMessagesInput struct {
+	RequesterID    string
+	Query          string
+	ConversationID string
+	SenderID       string
+	From           *time.Time
+	To             *time.Time
+	MsgType        *chat.MessageType
+	Before         string
+	Limit          int
+}
+
+// SearchMessagesOutput is a page of matches, newest first. NextCursor fetches the following page and is nil on the last one.
+type SearchMessagesOutput struct {
+	Hits       []chat.MessageSearchHit
+	NextCursor *string
+}
+
+// SearchMessagesUseCase finds messages by their text in the conversations the requester participates in,
+// honouring the same visibility rules as the history.
+type SearchMessagesUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewSearchMessagesUseCase(repo repository.ChatRepository) *SearchMessagesUseCase {
+	return &SearchMessagesUseCase{Repo: repo}
+}
+
+func (uc *SearchMessagesUseCase) Execute(ctx context.Context, in SearchMessagesInput) (*SearchMessagesOutput, error) {
+	if in.RequesterID == "" {
+		return nil, fmt.Errorf("requesterId is required")
+	}
+	text := strings.TrimSpace(in.Query)
+	if text == "" {
+		return nil, fmt.Errorf("q is required")
+	}
+	if utf8.RuneCountInString(text) > MaxSearchQueryLength {
+		return nil, fmt.Errorf("q must be at most %d characters", MaxSearchQueryLength)
+	}
+	if in.From != nil && in.To != nil && !in.From.Before(*in.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	q := repository.MessageSearchQuery{
+		UserID:  in.RequesterID,
+		Text:    text,
+		From:    in.From,
+		To:      in.To,
+		MsgType: in.MsgType,
+		Limit:   in.Limit,
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultSearchLimit
+	}
+	q.Limit = min(q.Limit, MaxSearchLimit)
+
+	if in.SenderID != "" {
+		if uuid.Validate(in.SenderID) != nil {
+			return nil, fmt.Errorf("senderId must be a UUID")
+		}
+		q.SenderID = &in.SenderID
+	}
+	if in.ConversationID != "" {
+		if uuid.Validate(in.ConversationID) != nil {
+			return nil, fmt.Errorf("conversationId must be a UUID")
+		}
+		// Searching a conversation one is not part of is refused rather than answered with nothing
+		_, err := uc.Repo.GetParticipant(ctx, in.ConversationID, in.RequesterID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, chat.ErrNotParticipant
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+		}
+		q.ConversationID = &in.ConversationID
+	}
+	if in.Before != "" {
+		cursor, err := chat.DecodeMessageCursor(in.Before)
+		if err != nil {
+			return nil, err
+		}
+		q.Before = &cursor
+	}
+
+	hits, err := uc.Repo.SearchMessages(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	out := &SearchMessagesOutput{Hits: hits}
+	if len(hits) == q.Limit {
+		next := chat.CursorOf(hits[len(hits)-1].Message).Encode()
+		out.NextCursor = &next
+	}
+	return out, nil
+}
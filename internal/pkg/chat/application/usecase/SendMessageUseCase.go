@@ -2,9 +2,12 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	storageport "go-chatty/internal/infrastructure/storage/port"
 	chat "go-chatty/internal/pkg/chat/application/domain"
 	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+	"time"
 )
 
 // SendMessageInput carries the data needed to send a new message
@@ -15,34 +18,60 @@ type SendMessageInput struct {
 	SenderID       string
 	Body           *string
 	MsgType        chat.MessageType
-	AttachmentURL  *string
-	AttachmentMeta *string
+	AttachmentID   *string // uploaded attachment owned by the sender, optional
 	DedupeKey      *string
+	ReplyToID      *string // quoted message, optional
+	ThreadRootID   *string // top-level message whose thread this message joins, optional
+}
+
+// SendMessageOutput is the stored message. The outbox relay announces it once the transaction storing it commits.
+type SendMessageOutput struct {
+	Message chat.Message
 }
 
 // SendMessageUseCase handles the SendMessage application service
-// Hexagonal: depends on repository port, returns domain entity
+// Hexagonal: depends on repository and storage ports, returns domain entity
 // One class per use case (own file)
 type SendMessageUseCase struct {
-	Repo repository.ChatRepository
+	Repo    repository.ChatRepository
+	Storage storageport.Storage // resolves attachments; messages with one are rejected when nil
+	Policy  storageport.Policy
 }
 
-func NewSendMessageUseCase(repo repository.ChatRepository) *SendMessageUseCase {
-	return &SendMessageUseCase{Repo: repo}
+func NewSendMessageUseCase(repo repository.ChatRepository, storage storageport.Storage, policy storageport.Policy) *SendMessageUseCase {
+	return &SendMessageUseCase{Repo: repo, Storage: storage, Policy: policy}
 }
 
 // Execute sends/persists a new message for a conversation
-func (uc *SendMessageUseCase) Execute(ctx context.Context, in SendMessageInput) (*chat.Message, error) {
+func (uc *SendMessageUseCase) Execute(ctx context.Context, in SendMessageInput) (*SendMessageOutput, error) {
 	if in.ConversationID == "" || in.SenderID == "" {
 		return nil, fmt.Errorf("conversationId and senderId are required")
 	}
+	if in.DedupeKey != nil && len(*in.DedupeKey) > 64 {
+		return nil, fmt.Errorf("dedupeKey must be at most 64 characters")
+	}
+	// System messages are produced by the domain for membership changes only
+	if in.MsgType == chat.MessageTypeSystem {
+		return nil, fmt.Errorf("system messages cannot be sent by clients")
+	}
 
-	isParticipant, err := uc.Repo.IsParticipant(ctx, in.ConversationID, in.SenderID)
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+		return nil, err
 	}
-	if !isParticipant {
-		return nil, chat.ErrNotParticipant
+	// A resend of a message already stored is answered with it, before checks whose outcome may have
+	// changed since: a block placed meanwhile, or the attachment now backing the stored message
+	if in.DedupeKey != nil {
+		stored, err := uc.Repo.GetMessageByDedupeKey(ctx, in.ConversationID, in.SenderID, *in.DedupeKey)
+		if err == nil {
+			return &SendMessageOutput{Message: stored}, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+		}
+	}
+	if err := uc.hydrateBlock(ctx, c, in.SenderID); err != nil {
+		return nil, err
 	}
 
 	msgInput := chat.Message{
@@ -50,21 +79,119 @@ func (uc *SendMessageUseCase) Execute(ctx context.Context, in SendMessageInput)
 		SenderID:       in.SenderID,
 		Body:           in.Body,
 		MsgType:        in.MsgType,
-		AttachmentURL:  in.AttachmentURL,
-		AttachmentMeta: in.AttachmentMeta,
 		DedupeKey:      in.DedupeKey,
+		ReplyToID:      in.ReplyToID,
+		ThreadRootID:   in.ThreadRootID,
+	}
+	if in.AttachmentID != nil {
+		if err := uc.resolveAttachment(ctx, in.SenderID, *in.AttachmentID, &msgInput); err != nil {
+			return nil, err
+		}
 	}
 
 	msg, err := chat.NewMessage(msgInput)
 	if err != nil {
 		return nil, err
 	}
+	if err := uc.checkReply(ctx, c, in); err != nil {
+		return nil, err
+	}
 
-	// Persist letting DB generate the ID
-	id, err := uc.Repo.SaveMessage(ctx, *msg)
+	// Domain rules: membership, blocks in direct conversations, content presence
+	validated, err := c.PostMessage(*msg, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	msg = &validated
+
+	// Persist letting DB generate the ID and sequence number; the same dedupe key seen before
+	// (client resend or queue retry) hands back the stored message
+	stored, _, err := uc.Repo.SaveMessage(ctx, *msg)
+	if errors.Is(err, repository.ErrConflict) {
+		return nil, chat.ErrAttachmentInUse
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
 	}
-	msg.ID = id
-	return msg, nil
+	return &SendMessageOutput{Message: stored}, nil
+}
+
+// resolveAttachment checks that the sender's attachment was uploaded as declared, is still acceptable and
+// was not rejected by processing, then points m at it. Text messages carrying an attachment become image or file messages.
+func (uc *SendMessageUseCase) resolveAttachment(ctx context.Context, senderID string, attachmentID string, m *chat.Message) error {
+	if uc.Storage == nil {
+		return fmt.Errorf("attachments are not supported here")
+	}
+	a, err := loadAttachment(ctx, uc.Repo, attachmentID)
+	if err != nil {
+		return err
+	}
+	if a.OwnerID != senderID {
+		return chat.ErrAttachmentNotFound
+	}
+	if a.Rejected {
+		return chat.ErrAttachmentRejected
+	}
+
+	if err := checkUpload(ctx, uc.Storage, a); err != nil {
+		return err
+	}
+	if err := uc.Policy.Check(a.ContentType, a.Size); err != nil {
+		return err
+	}
+
+	url, meta := a.URL(), a.Metadata()
+	m.AttachmentID = &a.ID
+	m.AttachmentURL = &url
+	m.AttachmentMeta = &meta
+	if m.MsgType == chat.MessageTypeText {
+		m.MsgType = a.MessageType()
+	}
+	return nil
+}
+
+// checkReply loads the quoted message and thread root of in, if any, and lets the aggregate validate them.
+func (uc *SendMessageUseCase) checkReply(ctx context.Context, c *chat.Chat, in SendMessageInput) error {
+	if in.ReplyToID == nil && in.ThreadRootID == nil {
+		return nil
+	}
+	var replyTo, root *chat.Message
+	if in.ReplyToID != nil {
+		m, err := loadMessage(ctx, uc.Repo, in.ConversationID, *in.ReplyToID)
+		if err != nil {
+			return err
+		}
+		replyTo = &m
+	}
+	if in.ThreadRootID != nil {
+		m, err := loadMessage(ctx, uc.Repo, in.ConversationID, *in.ThreadRootID)
+		if err != nil {
+			return err
+		}
+		root = &m
+	}
+	return c.CheckReply(in.SenderID, replyTo, root)
+}
+
+// hydrateBlock loads any block between the sender and the other member of a direct conversation.
+func (uc *SendMessageUseCase) hydrateBlock(ctx context.Context, c *chat.Chat, senderID string) error {
+	if c.Conversation.Kind != chat.ConversationKindDirect || !c.HasParticipant(senderID) {
+		return nil
+	}
+	otherIDs := make([]string, 0, len(c.Participants))
+	for uid := range c.Participants {
+		if uid != senderID {
+			otherIDs = append(otherIDs, uid)
+		}
+	}
+
+	b, err := uc.Repo.FindBlock(ctx, senderID, otherIDs)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	c.Block = &b
+	return nil
 }
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// blockedDirectRepo holds a direct conversation between alice and bob that bob has blocked alice in since,
+// and alice's earlier message stored under the dedupe key "resend".
+type blockedDirectRepo struct {
+	repository.ChatRepository
+	stored chat.Message
+}
+
+func (blockedDirectRepo) GetConversation(_ context.Context, conversationID string) (chat.Conversation, error) {
+	return chat.Conversation{ID: conversationID, Kind: chat.ConversationKindDirect}, nil
+}
+
+func (blockedDirectRepo) ListParticipants(_ context.Context, conversationID string) ([]chat.Participant, error) {
+	return []chat.Participant{
+		{ConversationID: conversationID, UserID: "alice", Role: chat.ParticipantRoleMember},
+		{ConversationID: conversationID, UserID: "bob", Role: chat.ParticipantRoleMember},
+	}, nil
+}
+
+func (blockedDirectRepo) FindBlock(_ context.Context, _ string, _ []string) (chat.Block, error) {
+	return chat.Block{BlockerId: "bob", BlockedId: "alice"}, nil
+}
+
+func (r blockedDirectRepo) GetMessageByDedupeKey(_ context.Context, conversationID string, senderID string, dedupeKey string) (chat.Message, error) {
+	if conversationID != r.stored.ConversationID || senderID != r.stored.SenderID || dedupeKey != *r.stored.DedupeKey {
+		return chat.Message{}, repository.ErrNotFound
+	}
+	return r.stored, nil
+}
+
+func TestSendMessageResendReturnsStoredMessage(t *testing.T) {
+	key, body := "resend", "hello"
+	attachment := "0b7c3e9a-1d2f-4a5b-8c6d-7e8f9a0b1c2d"
+	repo := blockedDirectRepo{stored: chat.Message{
+		ID: "m1", ConversationID: pushTestConversation, SenderID: "alice", DedupeKey: &key, AttachmentID: &attachment,
+	}}
+	// No storage: checking the attachment again would fail as well
+	uc := NewSendMessageUseCase(repo, nil, storageport.Policy{})
+
+	in := SendMessageInput{
+		ConversationID: pushTestConversation, SenderID: "alice", Body: &body, MsgType: chat.MessageTypeText,
+		DedupeKey: &key, AttachmentID: &attachment,
+	}
+	out, err := uc.Execute(context.Background(), in)
+	if err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	if out.Message.ID != "m1" {
+		t.Fatalf("resend returned %+v, want the stored m1", out.Message)
+	}
+
+	// A new message goes through the checks
+	other := "new"
+	in.DedupeKey, in.AttachmentID = &other, nil
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, chat.ErrUserBlocked) {
+		t.Fatalf("new message: err = %v, want ErrUserBlocked", err)
+	}
+}
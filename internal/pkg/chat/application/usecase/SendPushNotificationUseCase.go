@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	pushport "go-chatty/internal/infrastructure/push/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// pushPreviewLength bounds the excerpt of a message body shown in a push, in characters.
+const pushPreviewLength = 120
+
+// SendPushNotificationInput names the recipient and the conversation they have news in.
+type SendPushNotificationInput struct {
+	UserID         string
+	ConversationID string
+}
+
+// SendPushNotificationOutput tells how many devices the push reached and how many stale tokens were dropped.
+type SendPushNotificationOutput struct {
+	Sent    int
+	Removed int
+}
+
+// pushCandidateLimit bounds how many of the newest unread messages are searched for one worth a push.
+const pushCandidateLimit = 50
+
+// SendPushNotificationUseCase pushes a single notification summing up what a user has not read in a
+// conversation yet, to every device of theirs. The preview is the newest unread message the user is to be
+// notified of, from a sender neither blocking nor blocked by them. Nothing is sent when there is none (the
+// user left, muted the conversation, read everything in the meantime, ...), or when that message already
+// reached one of the user's sessions, as its delivery receipt shows.
+type SendPushNotificationUseCase struct {
+	Repo     repository.ChatRepository
+	Receipts repository.ReceiptRepository
+	Devices  repository.DeviceRepository
+	Provider pushport.Provider
+}
+
+func NewSendPushNotificationUseCase(repo repository.ChatRepository, receipts repository.ReceiptRepository, devices repository.DeviceRepository, provider pushport.Provider) *SendPushNotificationUseCase {
+	return &SendPushNotificationUseCase{Repo: repo, Receipts: receipts, Devices: devices, Provider: provider}
+}
+
+func (uc *SendPushNotificationUseCase) Execute(ctx context.Context, in SendPushNotificationInput) (*SendPushNotificationOutput, error) {
+	if in.UserID == "" || in.ConversationID == "" {
+		return nil, fmt.Errorf("userId and conversationId are required")
+	}
+	out := &SendPushNotificationOutput{}
+
+	p, err := loadParticipant(ctx, uc.Repo, in.ConversationID, in.UserID)
+	if errors.Is(err, chat.ErrNotParticipant) {
+		return out, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	last, err := uc.latestNotifiable(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if last == nil {
+		return out, nil
+	}
+	delivered, err := uc.delivered(ctx, last.ID, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if delivered {
+		return out, nil
+	}
+
+	unread, err := uc.Repo.CountUnread(ctx, in.ConversationID, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if unread == 0 {
+		return out, nil
+	}
+	devices, err := uc.Devices.ListDeviceTokens(ctx, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if len(devices) == 0 {
+		return out, nil
+	}
+
+	conv, err := uc.Repo.GetConversation(ctx, in.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	counts, err := uc.Repo.ListUnreadCounts(ctx, in.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	badge := 0
+	for _, n := range counts {
+		badge += n
+	}
+
+	n := pushport.Notification{
+		Title:       pushTitle(conv, unread),
+		Body:        pushBody(*last, unread),
+		Badge:       badge,
+		CollapseKey: conv.ID,
+		Data: map[string]string{
+			"type":           "message",
+			"conversationId": conv.ID,
+			"messageId":      last.ID,
+			"unreadCount":    strconv.Itoa(unread),
+		},
+	}
+
+	var (
+		stale   []string
+		lastErr error
+	)
+	for _, d := range devices {
+		n.Token, n.Platform = d.Token, pushPlatform(d.Platform)
+		err := uc.Provider.Send(ctx, n)
+		switch {
+		case errors.Is(err, pushport.ErrInvalidToken):
+			stale = append(stale, d.Token)
+		case err != nil:
+			lastErr = err
+		default:
+			out.Sent++
+		}
+	}
+	if len(stale) > 0 {
+		if err := uc.Devices.RemoveDeviceTokens(ctx, stale); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+		}
+		out.Removed = len(stale)
+	}
+	// A retry would push again to the devices already reached; only give up on this one when none was
+	if lastErr != nil && out.Sent == 0 {
+		return out, fmt.Errorf("%w: %v", ErrPush, lastErr)
+	}
+	return out, nil
+}
+
+// latestNotifiable returns the newest unread message p is to be notified of now, or nil when there is none.
+// Blocks are checked again: they may have changed since the message was sent.
+func (uc *SendPushNotificationUseCase) latestNotifiable(ctx context.Context, p chat.Participant) (*chat.Message, error) {
+	now := time.Now()
+	if p.IsMutedAt(now) || p.NotifyLevel == chat.NotificationLevelNone {
+		return nil, nil
+	}
+	msgs, err := uc.Repo.ListUnreadMessages(ctx, p.ConversationID, p.UserID, pushCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	skip := make(map[string]bool)
+	blocked, err := uc.Repo.ListBlocked(ctx, p.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	for _, b := range blocked {
+		skip[b.BlockedId] = true
+	}
+	blockers, err := uc.Repo.ListBlockedBy(ctx, p.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	for _, b := range blockers {
+		skip[b.BlockerId] = true
+	}
+
+	for i := range msgs {
+		if !skip[msgs[i].SenderID] && p.WantsNotification(msgs[i], now) {
+			return &msgs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// delivered tells whether messageID reached a session of userID, which then needs no push for it.
+func (uc *SendPushNotificationUseCase) delivered(ctx context.Context, messageID string, userID string) (bool, error) {
+	receipts, err := uc.Receipts.ListReceipts(ctx, messageID)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	for _, r := range receipts {
+		if r.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func pushPlatform(p chat.DevicePlatform) pushport.Platform {
+	if p == chat.DevicePlatformAPNs {
+		return pushport.PlatformAPNs
+	}
+	return pushport.PlatformFCM
+}
+
+// pushTitle names the group, if it has a title, or says how many messages are waiting.
+func pushTitle(conv chat.Conversation, unread int) string {
+	if conv.Kind == chat.ConversationKindGroup && conv.Title != nil && *conv.Title != "" {
+		return *conv.Title
+	}
+	if unread == 1 {
+		return "New message"
+	}
+	return fmt.Sprintf("%d new messages", unread)
+}
+
+// pushBody previews last, mentioning the other unread messages waiting with it.
+func pushBody(last chat.Message, unread int) string {
+	preview := "New message"
+	switch {
+	case last.Body != nil && *last.Body != "":
+		preview = truncateRunes(*last.Body, pushPreviewLength)
+	case last.AttachmentID != nil || last.AttachmentURL != nil:
+		preview = "Sent an attachment"
+	}
+	if unread > 1 {
+		return fmt.Sprintf("%s (+%d more)", preview, unread-1)
+	}
+	return preview
+}
+
+// truncateRunes cuts s to at most n characters, marking the cut with an ellipsis.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pushAdapter "go-chatty/internal/infrastructure/push/adapter"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+const pushTestConversation = "5f0c6a52-2f4e-4d3c-9c1e-0c3a1f6b7d10"
+
+// pushTestRepo keeps what SendPushNotificationUseCase reads of one conversation in memory. Methods the use
+// case does not need panic through the nil embedded interface.
+type pushTestRepo struct {
+	repository.ChatRepository
+
+	participant chat.Participant
+	unread      []chat.Message // newest first
+	blocks      []chat.Block
+}
+
+func (r *pushTestRepo) GetParticipant(_ context.Context, conversationID string, userID string) (chat.Participant, error) {
+	if conversationID != r.participant.ConversationID || userID != r.participant.UserID {
+		return chat.Participant{}, repository.ErrNotFound
+	}
+	return r.participant, nil
+}
+
+func (r *pushTestRepo) ListUnreadMessages(_ context.Context, _ string, _ string, limit int) ([]chat.Message, error) {
+	if len(r.unread) > limit {
+		return r.unread[:limit], nil
+	}
+	return r.unread, nil
+}
+
+func (r *pushTestRepo) CountUnread(context.Context, string, string) (int, error) {
+	return len(r.unread), nil
+}
+
+func (r *pushTestRepo) ListUnreadCounts(context.Context, string) (map[string]int, error) {
+	return map[string]int{pushTestConversation: len(r.unread), "other": 4}, nil
+}
+
+func (r *pushTestRepo) ListBlocked(_ context.Context, userID string) ([]chat.Block, error) {
+	var out []chat.Block
+	for _, b := range r.blocks {
+		if b.BlockerId == userID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (r *pushTestRepo) ListBlockedBy(_ context.Context, userID string) ([]chat.Block, error) {
+	var out []chat.Block
+	for _, b := range r.blocks {
+		if b.BlockedId == userID {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (r *pushTestRepo) GetConversation(_ context.Context, id string) (chat.Conversation, error) {
+	return chat.Conversation{ID: id, Kind: chat.ConversationKindDirect}, nil
+}
+
+type pushTestReceipts struct {
+	repository.ReceiptRepository
+	receipts []chat.Receipt
+}
+
+func (r *pushTestReceipts) ListReceipts(_ context.Context, messageID string) ([]chat.Receipt, error) {
+	var out []chat.Receipt
+	for _, rc := range r.receipts {
+		if rc.MessageID == messageID {
+			out = append(out, rc)
+		}
+	}
+	return out, nil
+}
+
+type pushTestDevices struct {
+	repository.DeviceRepository
+}
+
+func (pushTestDevices) ListDeviceTokens(_ context.Context, userID string) ([]chat.DeviceToken, error) {
+	return []chat.DeviceToken{
+		{Token: userID + "-phone", UserID: userID, Platform: chat.DevicePlatformFCM},
+		{Token: userID + "-tablet", UserID: userID, Platform: chat.DevicePlatformAPNs},
+	}, nil
+}
+
+// pushTestMessages returns messages from senders with the given bodies, oldest first as sent, in the newest
+// first order ListUnreadMessages returns.
+func pushTestMessages(senderBodies ...[2]string) []chat.Message {
+	base := time.Now().Add(-time.Minute)
+	msgs := make([]chat.Message, 0, len(senderBodies))
+	for i, sb := range senderBodies {
+		body := sb[1]
+		msgs = append([]chat.Message{{
+			ID:             "m" + string(rune('1'+i)),
+			ConversationID: pushTestConversation,
+			SenderID:       sb[0],
+			CreatedAt:      base.Add(time.Duration(i) * time.Second),
+			Body:           &body,
+			MsgType:        chat.MessageTypeText,
+		}}, msgs...)
+	}
+	return msgs
+}
+
+func newPushTest(repo *pushTestRepo, receipts ...chat.Receipt) (*SendPushNotificationUseCase, *pushAdapter.MemoryProvider) {
+	if repo.participant.UserID == "" {
+		repo.participant = chat.Participant{ConversationID: pushTestConversation, UserID: "alice"}
+	}
+	provider := pushAdapter.NewMemoryProvider(nil)
+	uc := NewSendPushNotificationUseCase(repo, &pushTestReceipts{receipts: receipts}, pushTestDevices{}, provider)
+	return uc, provider
+}
+
+func runPush(t *testing.T, uc *SendPushNotificationUseCase) *SendPushNotificationOutput {
+	t.Helper()
+	out, err := uc.Execute(context.Background(), SendPushNotificationInput{UserID: "alice", ConversationID: pushTestConversation})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return out
+}
+
+func TestSendPushNotificationCollapsesBurst(t *testing.T) {
+	repo := &pushTestRepo{unread: pushTestMessages([2]string{"bob", "one"}, [2]string{"bob", "two"}, [2]string{"bob", "three"})}
+	uc, provider := newPushTest(repo)
+
+	if out := runPush(t, uc); out.Sent != 2 {
+		t.Fatalf("Sent = %d, want one push per device", out.Sent)
+	}
+	sent := provider.Sent()
+	if len(sent) != 2 {
+		t.Fatalf("got %d pushes, want 2", len(sent))
+	}
+	for _, s := range sent {
+		n := s.Notification
+		if n.Title != "3 new messages" || n.Body != "three (+2 more)" {
+			t.Fatalf("got %q / %q", n.Title, n.Body)
+		}
+		if n.Badge != 7 || n.CollapseKey != pushTestConversation || n.Data["messageId"] != "m3" {
+			t.Fatalf("got badge %d, collapse key %q, data %v", n.Badge, n.CollapseKey, n.Data)
+		}
+	}
+}
+
+func TestSendPushNotificationSkipsMutedParticipant(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	for name, p := range map[string]chat.Participant{
+		"muted":      {ConversationID: pushTestConversation, UserID: "alice", MutedUntil: &future},
+		"level none": {ConversationID: pushTestConversation, UserID: "alice", NotifyLevel: chat.NotificationLevelNone},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := &pushTestRepo{participant: p, unread: pushTestMessages([2]string{"bob", "hi @alice"})}
+			uc, provider := newPushTest(repo)
+			if out := runPush(t, uc); out.Sent != 0 || len(provider.Sent()) != 0 {
+				t.Fatalf("pushed %d notifications to a %s participant", len(provider.Sent()), name)
+			}
+		})
+	}
+}
+
+func TestSendPushNotificationPushesAgainOnceUnmuted(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	repo := &pushTestRepo{
+		participant: chat.Participant{ConversationID: pushTestConversation, UserID: "alice", MutedUntil: &past},
+		unread:      pushTestMessages([2]string{"bob", "hi"}),
+	}
+	uc, provider := newPushTest(repo)
+	if runPush(t, uc); len(provider.Sent()) != 2 {
+		t.Fatalf("got %d pushes after the mute expired, want 2", len(provider.Sent()))
+	}
+}
+
+func TestSendPushNotificationPreviewsMentionForMentionsLevel(t *testing.T) {
+	repo := &pushTestRepo{
+		participant: chat.Participant{ConversationID: pushTestConversation, UserID: "alice", NotifyLevel: chat.NotificationLevelMentions},
+		unread:      pushTestMessages([2]string{"bob", "ping @alice"}, [2]string{"carol", "unrelated chatter"}),
+	}
+	uc, provider := newPushTest(repo)
+	runPush(t, uc)
+	sent := provider.Sent()
+	if len(sent) == 0 {
+		t.Fatalf("no push for a mention")
+	}
+	if got := sent[0].Notification.Body; got != "ping @alice (+1 more)" {
+		t.Fatalf("body %q, want the mention", got)
+	}
+
+	repo.unread = pushTestMessages([2]string{"carol", "unrelated chatter"})
+	provider.Reset()
+	runPush(t, uc)
+	if len(provider.Sent()) != 0 {
+		t.Fatalf("pushed without a mention to a mentions-level participant")
+	}
+}
+
+func TestSendPushNotificationSkipsBlockedSenders(t *testing.T) {
+	repo := &pushTestRepo{
+		unread: pushTestMessages([2]string{"bob", "from bob"}, [2]string{"carol", "from carol"}, [2]string{"dave", "from dave"}),
+		blocks: []chat.Block{
+			{BlockerId: "alice", BlockedId: "dave"},
+			{BlockerId: "carol", BlockedId: "alice"},
+		},
+	}
+	uc, provider := newPushTest(repo)
+	runPush(t, uc)
+	sent := provider.Sent()
+	if len(sent) == 0 {
+		t.Fatalf("no push")
+	}
+	if got := sent[0].Notification.Body; !strings.HasPrefix(got, "from bob") {
+		t.Fatalf("body %q, want bob's message", got)
+	}
+
+	repo.unread = repo.unread[:2]
+	provider.Reset()
+	runPush(t, uc)
+	if len(provider.Sent()) != 0 {
+		t.Fatalf("pushed a message of a blocked or blocking sender")
+	}
+}
+
+func TestSendPushNotificationSkipsDeliveredMessage(t *testing.T) {
+	repo := &pushTestRepo{unread: pushTestMessages([2]string{"bob", "one"}, [2]string{"bob", "two"})}
+	uc, provider := newPushTest(repo, chat.Receipt{MessageID: "m2", UserID: "alice", Status: chat.ReceiptStatusDelivered})
+	if runPush(t, uc); len(provider.Sent()) != 0 {
+		t.Fatalf("pushed a message that reached an open session")
+	}
+
+	// A receipt of someone else does not count
+	uc, provider = newPushTest(repo, chat.Receipt{MessageID: "m2", UserID: "bob", Status: chat.ReceiptStatusDelivered})
+	if runPush(t, uc); len(provider.Sent()) != 2 {
+		t.Fatalf("got %d pushes, want 2", len(provider.Sent()))
+	}
+}
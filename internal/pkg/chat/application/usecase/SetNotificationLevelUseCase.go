@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// SetNotificationLevelInput carries a participant's choice of which messages of a conversation notify them.
+type SetNotificationLevelInput struct {
+	ConversationID string
+	UserID         string
+	Level          chat.NotificationLevel
+}
+
+// SetNotificationLevelUseCase changes a participant's notification level in a conversation.
+type SetNotificationLevelUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewSetNotificationLevelUseCase(repo repository.ChatRepository) *SetNotificationLevelUseCase {
+	return &SetNotificationLevelUseCase{Repo: repo}
+}
+
+// Execute stores the level and returns the participant's updated settings.
+func (uc *SetNotificationLevelUseCase) Execute(ctx context.Context, in SetNotificationLevelInput) (*chat.Participant, error) {
+	if in.ConversationID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId and userId are required")
+	}
+	if !in.Level.Valid() {
+		return nil, chat.ErrInvalidNotificationLevel
+	}
+
+	p, err := loadParticipant(ctx, uc.Repo, in.ConversationID, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.Repo.SetNotificationLevel(ctx, in.ConversationID, in.UserID, in.Level)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, chat.ErrNotParticipant
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	p.NotifyLevel = in.Level
+	return &p, nil
+}
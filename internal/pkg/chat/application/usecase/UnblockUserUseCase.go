@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// UnblockUserInput carries a request from BlockerID to lift a block on BlockedID.
+type UnblockUserInput struct {
+	BlockerID string
+	BlockedID string
+}
+
+// UnblockUserUseCase removes a block; removing a block that does not exist is a no-op.
+type UnblockUserUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewUnblockUserUseCase(repo repository.ChatRepository) *UnblockUserUseCase {
+	return &UnblockUserUseCase{Repo: repo}
+}
+
+func (uc *UnblockUserUseCase) Execute(ctx context.Context, in UnblockUserInput) error {
+	if in.BlockerID == "" || in.BlockedID == "" {
+		return fmt.Errorf("blockerId and blockedId are required")
+	}
+
+	err := uc.Repo.UnblockUser(ctx, in.BlockerID, in.BlockedID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return nil
+}
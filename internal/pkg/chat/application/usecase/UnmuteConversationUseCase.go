@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// UnmuteConversationInput carries a participant's request to lift their mute of a conversation.
+type UnmuteConversationInput struct {
+	ConversationID string
+	UserID         string
+}
+
+// UnmuteConversationUseCase restores the notifications of a conversation for one participant.
+// Unmuting a conversation that is not muted is harmless.
+type UnmuteConversationUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewUnmuteConversationUseCase(repo repository.ChatRepository) *UnmuteConversationUseCase {
+	return &UnmuteConversationUseCase{Repo: repo}
+}
+
+// Execute clears the mute and returns the participant's updated settings.
+func (uc *UnmuteConversationUseCase) Execute(ctx context.Context, in UnmuteConversationInput) (*chat.Participant, error) {
+	if in.ConversationID == "" || in.UserID == "" {
+		return nil, fmt.Errorf("conversationId and userId are required")
+	}
+
+	p, err := loadParticipant(ctx, uc.Repo, in.ConversationID, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	err = uc.Repo.SetMuteUntil(ctx, in.ConversationID, in.UserID, nil)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, chat.ErrNotParticipant
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	p.MutedUntil = nil
+	return &p, nil
+}
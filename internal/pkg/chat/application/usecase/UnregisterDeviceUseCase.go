@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// UnregisterDeviceInput names a push token to forget, e.g. when the user signs out of an app install.
+type UnregisterDeviceInput struct {
+	UserID string
+	Token  string
+}
+
+// UnregisterDeviceUseCase stops push notifications to one of the user's devices.
+type UnregisterDeviceUseCase struct {
+	Devices repository.DeviceRepository
+}
+
+func NewUnregisterDeviceUseCase(devices repository.DeviceRepository) *UnregisterDeviceUseCase {
+	return &UnregisterDeviceUseCase{Devices: devices}
+}
+
+func (uc *UnregisterDeviceUseCase) Execute(ctx context.Context, in UnregisterDeviceInput) error {
+	if in.UserID == "" || in.Token == "" {
+		return fmt.Errorf("userId and token are required")
+	}
+	err := uc.Devices.DeleteDeviceToken(ctx, in.UserID, in.Token)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.ErrDeviceNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return nil
+}
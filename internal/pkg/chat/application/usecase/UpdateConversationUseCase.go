@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// UpdateConversationInput carries a request to rename a group and/or change its avatar.
+// Nil fields are left untouched.
+type UpdateConversationInput struct {
+	ConversationID string
+	ActorID        string
+	Title          *string
+	AvatarURL      *string
+}
+
+// UpdateConversationUseCase edits the profile of a group conversation.
+type UpdateConversationUseCase struct {
+	Repo repository.ChatRepository
+}
+
+func NewUpdateConversationUseCase(repo repository.ChatRepository) *UpdateConversationUseCase {
+	return &UpdateConversationUseCase{Repo: repo}
+}
+
+// Execute applies the profile change and returns the system message recording it.
+func (uc *UpdateConversationUseCase) Execute(ctx context.Context, in UpdateConversationInput) (*chat.Message, error) {
+	if in.ConversationID == "" || in.ActorID == "" {
+		return nil, fmt.Errorf("conversationId and actorId are required")
+	}
+	if in.Title != nil {
+		trimmed := strings.TrimSpace(*in.Title)
+		if trimmed == "" {
+			return nil, fmt.Errorf("title must not be empty")
+		}
+		in.Title = &trimmed
+	}
+	if in.Title == nil && in.AvatarURL == nil {
+		return nil, fmt.Errorf("title or avatarUrl is required")
+	}
+
+	c, err := loadChat(ctx, uc.Repo, in.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := c.UpdateProfile(in.ActorID, in.Title, in.AvatarURL, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	stored, err := uc.Repo.UpdateConversationProfile(ctx, in.ConversationID, in.Title, in.AvatarURL, msg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return &stored, nil
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/google/uuid"
+)
+
+// loadChat hydrates the Chat aggregate with its conversation and participants.
+// Malformed ids and missing conversations are reported as chat.ErrNotParticipant so callers cannot probe for ids.
+func loadChat(ctx context.Context, repo repository.ChatRepository, conversationID string) (*chat.Chat, error) {
+	if _, err := uuid.Parse(conversationID); err != nil {
+		return nil, chat.ErrNotParticipant
+	}
+	conv, err := repo.GetConversation(ctx, conversationID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, chat.ErrNotParticipant
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	participants, err := repo.ListParticipants(ctx, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+
+	c := &chat.Chat{
+		Conversation: conv,
+		Participants: make(map[string]chat.Participant, len(participants)),
+	}
+	for _, p := range participants {
+		c.Participants[p.UserID] = p
+	}
+	return c, nil
+}
+
+// loadParticipant fetches the membership of userID in conversationID; non-members get chat.ErrNotParticipant.
+func loadParticipant(ctx context.Context, repo repository.ChatRepository, conversationID string, userID string) (chat.Participant, error) {
+	if _, err := uuid.Parse(conversationID); err != nil {
+		return chat.Participant{}, chat.ErrNotParticipant
+	}
+	p, err := repo.GetParticipant(ctx, conversationID, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.Participant{}, chat.ErrNotParticipant
+	}
+	if err != nil {
+		return chat.Participant{}, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return p, nil
+}
+
+// loadMessage fetches messageID and checks that it belongs to conversationID.
+// Malformed ids, missing messages and messages of other conversations are all chat.ErrMessageNotFound.
+func loadMessage(ctx context.Context, repo repository.ChatRepository, conversationID string, messageID string) (chat.Message, error) {
+	if _, err := uuid.Parse(messageID); err != nil {
+		return chat.Message{}, chat.ErrMessageNotFound
+	}
+	msg, err := repo.GetMessage(ctx, messageID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.Message{}, chat.ErrMessageNotFound
+	}
+	if err != nil {
+		return chat.Message{}, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if msg.ConversationID != conversationID {
+		return chat.Message{}, chat.ErrMessageNotFound
+	}
+	return msg, nil
+}
+
+// loadThreadRoot fetches the root of a thread on behalf of userID, who must be a participant able to see it.
+// Thread replies cannot root threads of their own and are reported as chat.ErrInvalidThread.
+func loadThreadRoot(ctx context.Context, repo repository.ChatRepository, conversationID string, rootID string, userID string) (chat.Message, error) {
+	c, err := loadChat(ctx, repo, conversationID)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if !c.HasParticipant(userID) {
+		return chat.Message{}, chat.ErrNotParticipant
+	}
+	root, err := loadMessage(ctx, repo, conversationID, rootID)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if !c.CanView(userID, root) {
+		return chat.Message{}, chat.ErrMessageNotFound
+	}
+	if root.IsThreadReply() {
+		return chat.Message{}, chat.ErrInvalidThread
+	}
+	return root, nil
+}
+
+// loadAttachment fetches attachmentID; malformed ids and missing rows are both chat.ErrAttachmentNotFound.
+func loadAttachment(ctx context.Context, repo repository.ChatRepository, attachmentID string) (chat.Attachment, error) {
+	if _, err := uuid.Parse(attachmentID); err != nil {
+		return chat.Attachment{}, chat.ErrAttachmentNotFound
+	}
+	a, err := repo.GetAttachment(ctx, attachmentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.Attachment{}, chat.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return chat.Attachment{}, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	return a, nil
+}
+
+// loadWebhook fetches a webhook subscription of tenantID. Malformed ids and subscriptions of other tenants
+// are reported as chat.ErrWebhookNotFound.
+func loadWebhook(ctx context.Context, repo repository.WebhookRepository, tenantID string, webhookID string) (chat.WebhookSubscription, error) {
+	if _, err := uuid.Parse(webhookID); err != nil {
+		return chat.WebhookSubscription{}, chat.ErrWebhookNotFound
+	}
+	s, err := repo.GetWebhookSubscription(ctx, webhookID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.WebhookSubscription{}, chat.ErrWebhookNotFound
+	}
+	if err != nil {
+		return chat.WebhookSubscription{}, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if s.TenantID != tenantID {
+		return chat.WebhookSubscription{}, chat.ErrWebhookNotFound
+	}
+	return s, nil
+}
+
+// loadDelivery fetches a delivery of the subscription webhookID; others are reported as chat.ErrDeliveryNotFound.
+func loadDelivery(ctx context.Context, repo repository.WebhookRepository, webhookID string, deliveryID string) (chat.WebhookDelivery, error) {
+	if _, err := uuid.Parse(deliveryID); err != nil {
+		return chat.WebhookDelivery{}, chat.ErrDeliveryNotFound
+	}
+	d, err := repo.GetWebhookDelivery(ctx, deliveryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return chat.WebhookDelivery{}, chat.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return chat.WebhookDelivery{}, fmt.Errorf("%w: %v", ErrPersistence, err)
+	}
+	if d.SubscriptionID != webhookID {
+		return chat.WebhookDelivery{}, chat.ErrDeliveryNotFound
+	}
+	return d, nil
+}
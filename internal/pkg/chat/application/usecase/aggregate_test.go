@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+// nilRepo panics on any repository call through its nil embedded interface.
+type nilRepo struct {
+	repository.ChatRepository
+}
+
+func TestLoadChatRejectsMalformedIDs(t *testing.T) {
+	for _, id := range []string{"", "not-a-uuid", "5f0c6a52-2f4e-4d3c-9c1e"} {
+		if _, err := loadChat(context.Background(), nilRepo{}, id); !errors.Is(err, chat.ErrNotParticipant) {
+			t.Fatalf("loadChat(%q): err = %v, want ErrNotParticipant", id, err)
+		}
+	}
+}
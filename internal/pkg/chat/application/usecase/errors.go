@@ -4,3 +4,9 @@ import "fmt"
 
 // ErrPersistence indicates an infrastructure/repository failure inside a use case
 var ErrPersistence = fmt.Errorf("chat use case persistence error")
+
+// ErrStorage indicates an object storage failure inside a use case
+var ErrStorage = fmt.Errorf("chat use case storage error")
+
+// ErrPush indicates a push notification provider failure inside a use case
+var ErrPush = fmt.Errorf("chat use case push error")
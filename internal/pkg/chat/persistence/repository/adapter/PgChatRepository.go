@@ -3,140 +3,1457 @@ package adapter
 import (
 	"context"
 	"errors"
+	"fmt"
 	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// uniqueViolation is the SQLSTATE Postgres reports when a unique index rejects a row.
+const uniqueViolation = "23505"
+
 type PgChatRepository struct {
 	pool *pgxpool.Pool
 }
 
-func NewPgChatRepository(pool *pgxpool.Pool) *PgChatRepository {
-	return &PgChatRepository{pool: pool}
+func NewPgChatRepository(pool *pgxpool.Pool) *PgChatRepository {
+	return &PgChatRepository{pool: pool}
+}
+
+func (r *PgChatRepository) CreateConversation(ctx context.Context, c chat.Conversation, participants []chat.Participant) (string, bool, error) {
+	if r == nil || r.pool == nil {
+		return "", false, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO chat.conversation (created_at, tenant_id, kind, history_hidden, title, avatar_url, pair_key, last_activity_at)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5, $6, NULLIF($7, ''), $1)
+		ON CONFLICT (COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), pair_key)
+		WHERE pair_key IS NOT NULL
+		DO NOTHING
+		RETURNING id::text
+	`, c.CreatedAt, c.TenantID, c.Kind, c.HistoryHidden, c.Title, c.AvatarURL, c.PairKey).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The pair already has a direct conversation; the conflicting row is committed and visible here
+		err = tx.QueryRow(ctx, `
+			SELECT id::text
+			FROM chat.conversation
+			WHERE tenant_id IS NOT DISTINCT FROM NULLIF($1, '')::uuid AND pair_key = $2
+		`, c.TenantID, c.PairKey).Scan(&id)
+		if err != nil {
+			return "", false, err
+		}
+		return id, false, tx.Commit(ctx)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, p := range participants {
+		var joinedAt *time.Time
+		if !p.JoinedAt.IsZero() {
+			joinedAt = &p.JoinedAt
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO chat.participant (conversation_id, user_id, role, joined_at)
+			VALUES ($1::uuid, $2::uuid, $3, COALESCE($4, now() AT TIME ZONE 'utc'))
+		`, id, p.UserID, p.Role, joinedAt)
+		if err != nil {
+			return "", false, err
+		}
+	}
+	if err := writeOutbox(ctx, tx, chat.OutboxConversationCreated, id, nil); err != nil {
+		return "", false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", false, err
+	}
+	return id, true, nil
+}
+
+func (r *PgChatRepository) GetConversation(ctx context.Context, conversationID string) (chat.Conversation, error) {
+	if r == nil || r.pool == nil {
+		return chat.Conversation{}, errors.New("PgChatRepository: nil pool")
+	}
+	var (
+		c      chat.Conversation
+		tenant *string
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT id::text, created_at, tenant_id::text, kind, history_hidden, title, avatar_url, COALESCE(pair_key, ''),
+		       last_message_id::text, last_activity_at
+		FROM chat.conversation
+		WHERE id = $1::uuid
+	`, conversationID).Scan(&c.ID, &c.CreatedAt, &tenant, &c.Kind, &c.HistoryHidden, &c.Title, &c.AvatarURL, &c.PairKey,
+		&c.LastMessageID, &c.LastActivityAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return chat.Conversation{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return chat.Conversation{}, err
+	}
+	if tenant != nil {
+		c.TenantID = *tenant
+	}
+	return c, nil
+}
+
+func (r *PgChatRepository) UpdateConversationProfile(ctx context.Context, conversationID string, title *string, avatarURL *string, record chat.Message) (chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	ct, err := tx.Exec(ctx, `
+		UPDATE chat.conversation
+		SET title = COALESCE($2, title),
+		    avatar_url = COALESCE($3, avatar_url)
+		WHERE id = $1::uuid
+	`, conversationID, title, avatarURL)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if ct.RowsAffected() == 0 {
+		return chat.Message{}, repository.ErrNotFound
+	}
+	return saveSystemMessage(ctx, tx, record)
+}
+
+func (r *PgChatRepository) AddParticipant(ctx context.Context, p chat.Participant, record chat.Message) (chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockConversation(ctx, tx, p.ConversationID); err != nil {
+		return chat.Message{}, err
+	}
+	var joinedAt *time.Time
+	if !p.JoinedAt.IsZero() {
+		joinedAt = &p.JoinedAt
+	}
+	// joined_at is only set on first insert so re-adding a member keeps their original cut-off
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO chat.participant (conversation_id, user_id, role, last_read_msg, muted_until, joined_at, notify_level)
+		VALUES ($1::uuid, $2::uuid, $3, $4::uuid, $5, COALESCE($6, now() AT TIME ZONE 'utc'), $7)
+		ON CONFLICT (conversation_id, user_id)
+		DO UPDATE SET role = EXCLUDED.role,
+		              last_read_msg = EXCLUDED.last_read_msg,
+		              muted_until = EXCLUDED.muted_until,
+		              notify_level = EXCLUDED.notify_level
+	`, p.ConversationID, p.UserID, p.Role, p.LastReadMsg, p.MutedUntil, joinedAt, p.NotifyLevel); err != nil {
+		return chat.Message{}, err
+	}
+	return saveSystemMessage(ctx, tx, record)
+}
+
+func (r *PgChatRepository) GetParticipant(ctx context.Context, conversationID string, userID string) (chat.Participant, error) {
+	if r == nil || r.pool == nil {
+		return chat.Participant{}, errors.New("PgChatRepository: nil pool")
+	}
+	var p chat.Participant
+	err := r.pool.QueryRow(ctx, `
+		SELECT conversation_id::text, user_id::text, role, last_read_msg::text, muted_until, joined_at, notify_level
+		FROM chat.participant
+		WHERE conversation_id = $1::uuid AND user_id = $2::uuid
+	`, conversationID, userID).Scan(&p.ConversationID, &p.UserID, &p.Role, &p.LastReadMsg, &p.MutedUntil, &p.JoinedAt, &p.NotifyLevel)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return chat.Participant{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return chat.Participant{}, err
+	}
+	return p, nil
+}
+
+func (r *PgChatRepository) ListParticipants(ctx context.Context, conversationID string) ([]chat.Participant, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT conversation_id::text, user_id::text, role, last_read_msg::text, muted_until, joined_at, notify_level
+		FROM chat.participant
+		WHERE conversation_id = $1::uuid
+		ORDER BY joined_at, user_id
+	`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []chat.Participant
+	for rows.Next() {
+		var p chat.Participant
+		if err := rows.Scan(&p.ConversationID, &p.UserID, &p.Role, &p.LastReadMsg, &p.MutedUntil, &p.JoinedAt, &p.NotifyLevel); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return participants, nil
+}
+
+func (r *PgChatRepository) UpdateParticipantRole(ctx context.Context, conversationID string, userID string, role chat.ParticipantRole, record chat.Message) (chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockConversation(ctx, tx, conversationID); err != nil {
+		return chat.Message{}, err
+	}
+	if role != chat.ParticipantRoleOwner {
+		if err := checkOwnerRemains(ctx, tx, conversationID, userID); err != nil {
+			return chat.Message{}, err
+		}
+	}
+	ct, err := tx.Exec(ctx, `
+		UPDATE chat.participant
+		SET role = $3
+		WHERE conversation_id = $1::uuid AND user_id = $2::uuid
+	`, conversationID, userID, role)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if ct.RowsAffected() == 0 {
+		return chat.Message{}, repository.ErrNotFound
+	}
+	return saveSystemMessage(ctx, tx, record)
+}
+
+func (r *PgChatRepository) RemoveParticipant(ctx context.Context, conversationID string, userID string, record chat.Message) (chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockConversation(ctx, tx, conversationID); err != nil {
+		return chat.Message{}, err
+	}
+	if err := checkOwnerRemains(ctx, tx, conversationID, userID); err != nil {
+		return chat.Message{}, err
+	}
+	ct, err := tx.Exec(ctx, `
+		DELETE FROM chat.participant
+		WHERE conversation_id = $1::uuid AND user_id = $2::uuid
+	`, conversationID, userID)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if ct.RowsAffected() == 0 {
+		return chat.Message{}, repository.ErrNotFound
+	}
+	return saveSystemMessage(ctx, tx, record)
+}
+
+// lockConversation takes the conversation's row lock, which nextSeq would only take when the system message
+// is stored, before membership is read or changed within tx. Concurrent membership changes of the
+// conversation then apply one after the other, each seeing the membership the previous one left.
+func lockConversation(ctx context.Context, tx pgx.Tx, conversationID string) error {
+	var locked int
+	err := tx.QueryRow(ctx, `
+		SELECT 1 FROM chat.conversation WHERE id = $1::uuid FOR UPDATE
+	`, conversationID).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// checkOwnerRemains returns ErrLastOwner when userID is the only owner of the conversation and others
+// participate in it, so removing userID or their ownership would leave it without an owner. The caller
+// holds the conversation's row lock.
+func checkOwnerRemains(ctx context.Context, tx pgx.Tx, conversationID string, userID string) error {
+	var lastOwner bool
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(bool_or(user_id = $2::uuid AND role = $3)
+		                AND NOT bool_or(user_id <> $2::uuid AND role = $3)
+		                AND bool_or(user_id <> $2::uuid), false)
+		FROM chat.participant
+		WHERE conversation_id = $1::uuid
+	`, conversationID, userID, chat.ParticipantRoleOwner).Scan(&lastOwner)
+	if err != nil {
+		return err
+	}
+	if lastOwner {
+		return repository.ErrLastOwner
+	}
+	return nil
+}
+
+func (r *PgChatRepository) SaveMessage(ctx context.Context, m chat.Message) (chat.Message, bool, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, false, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return chat.Message{}, false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	stored, created, err := saveMessage(ctx, tx, m)
+	if err != nil || !created {
+		// A retry of an already stored message rolls back, which returns the sequence number
+		return stored, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return chat.Message{}, false, err
+	}
+	return stored, true, nil
+}
+
+// saveMessage inserts m within tx as SaveMessage describes. When created is false tx holds nothing worth committing.
+func saveMessage(ctx context.Context, tx pgx.Tx, m chat.Message) (chat.Message, bool, error) {
+	seq, err := nextSeq(ctx, tx, m.ConversationID)
+	if err != nil {
+		return chat.Message{}, false, err
+	}
+	rows, err := tx.Query(ctx, `
+		INSERT INTO chat.message (
+			conversation_id, sender_id, created_at, body, msg_type, attachment_url, attachment_meta, dedupe_key,
+			reply_to_id, thread_root_id, seq, change_seq, attachment_id
+		) VALUES ($1::uuid, $2::uuid, $3, $4, $5, $6, COALESCE($7::json, NULL), $8, $9::uuid, $10::uuid, $11, $11, $12::uuid)
+		ON CONFLICT (conversation_id, sender_id, dedupe_key) WHERE dedupe_key IS NOT NULL
+		DO NOTHING
+		RETURNING `+messageColumns+`
+	`, m.ConversationID, m.SenderID, m.CreatedAt, m.Body, m.MsgType, m.AttachmentURL, m.AttachmentMeta, m.DedupeKey,
+		m.ReplyToID, m.ThreadRootID, seq, m.AttachmentID)
+	if err != nil {
+		return chat.Message{}, false, attachmentConflict(err)
+	}
+	inserted, err := scanMessages(rows)
+	if err != nil {
+		return chat.Message{}, false, attachmentConflict(err)
+	}
+	if len(inserted) == 0 {
+		// Retry of an already stored message: hand back the original
+		rows, err := tx.Query(ctx, dedupedMessageSQL, m.ConversationID, m.SenderID, m.DedupeKey)
+		if err != nil {
+			return chat.Message{}, false, err
+		}
+		existing, err := scanMessages(rows)
+		if err != nil {
+			return chat.Message{}, false, err
+		}
+		if len(existing) == 0 {
+			return chat.Message{}, false, repository.ErrNotFound
+		}
+		return existing[0], false, nil
+	}
+
+	if m.ThreadRootID != nil {
+		if _, err := tx.Exec(ctx, `
+			UPDATE chat.message
+			SET thread_reply_count = thread_reply_count + 1,
+			    thread_last_reply_at = GREATEST(COALESCE(thread_last_reply_at, $2), $2),
+			    change_seq = $3
+			WHERE id = $1::uuid
+		`, *m.ThreadRootID, m.CreatedAt, seq); err != nil {
+			return chat.Message{}, false, err
+		}
+	} else {
+		// The conversation row is already locked by nextSeq; a message stamped earlier than the current
+		// last one, by a node with a lagging clock, leaves the inbox position alone
+		if _, err := tx.Exec(ctx, `
+			UPDATE chat.conversation
+			SET last_message_id = $2::uuid, last_activity_at = $3
+			WHERE id = $1::uuid AND last_activity_at <= $3
+		`, m.ConversationID, inserted[0].ID, inserted[0].CreatedAt); err != nil {
+			return chat.Message{}, false, err
+		}
+	}
+	// Written after nextSeq took the conversation's row lock, so entries of a conversation follow commit order
+	if err := writeOutbox(ctx, tx, chat.OutboxMessageCreated, m.ConversationID, &inserted[0].ID); err != nil {
+		return chat.Message{}, false, err
+	}
+	return inserted[0], true, nil
+}
+
+// saveSystemMessage stores the system message recording a change made within tx and commits both together.
+func saveSystemMessage(ctx context.Context, tx pgx.Tx, record chat.Message) (chat.Message, error) {
+	stored, _, err := saveMessage(ctx, tx, record)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return chat.Message{}, err
+	}
+	return stored, nil
+}
+
+// attachmentConflict reports an attachment already backing another message as ErrConflict.
+func attachmentConflict(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == "uq_message_attachment" {
+		return repository.ErrConflict
+	}
+	return err
+}
+
+// nextSeq takes the next number of the conversation's sequence. The row lock it leaves on the conversation
+// serialises writers of that conversation until tx ends, so numbers are committed in order.
+func nextSeq(ctx context.Context, tx pgx.Tx, conversationID string) (int64, error) {
+	var seq int64
+	err := tx.QueryRow(ctx, `
+		UPDATE chat.conversation
+		SET last_seq = last_seq + 1
+		WHERE id = $1::uuid
+		RETURNING last_seq
+	`, conversationID).Scan(&seq)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, repository.ErrNotFound
+	}
+	return seq, err
+}
+
+func (r *PgChatRepository) GetMessage(ctx context.Context, messageID string) (chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM chat.message
+		WHERE id = $1::uuid
+	`, messageID)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	msgs, err := scanMessages(rows)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if len(msgs) == 0 {
+		return chat.Message{}, repository.ErrNotFound
+	}
+	return msgs[0], nil
+}
+
+// dedupedMessageSQL selects the message a sender stored in a conversation with a dedupe key.
+const dedupedMessageSQL = `
+	SELECT ` + messageColumns + `
+	FROM chat.message
+	WHERE conversation_id = $1::uuid AND sender_id = $2::uuid AND dedupe_key = $3
+`
+
+func (r *PgChatRepository) GetMessageByDedupeKey(ctx context.Context, conversationID string, senderID string, dedupeKey string) (chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, dedupedMessageSQL, conversationID, senderID, dedupeKey)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	msgs, err := scanMessages(rows)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if len(msgs) == 0 {
+		return chat.Message{}, repository.ErrNotFound
+	}
+	return msgs[0], nil
+}
+
+func (r *PgChatRepository) EditMessage(ctx context.Context, m chat.Message, e chat.MessageEdit) (int64, error) {
+	if r == nil || r.pool == nil {
+		return 0, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	seq, err := nextSeq(ctx, tx, m.ConversationID)
+	if err != nil {
+		return 0, err
+	}
+	// The previous body is read under the row lock, so concurrent edits each record the body they replaced;
+	// a concurrent delete wins: tombstones are never edited
+	var previous *string
+	err = tx.QueryRow(ctx, `
+		WITH prev AS (
+		  SELECT id, body
+		  FROM chat.message
+		  WHERE id = $1::uuid AND deleted_at IS NULL
+		  FOR UPDATE
+		)
+		UPDATE chat.message m
+		SET body = $2, edited_at = $3, change_seq = $4
+		FROM prev
+		WHERE m.id = prev.id
+		RETURNING prev.body
+	`, m.ID, m.Body, m.EditedAt, seq).Scan(&previous)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO chat.message_edit (message_id, editor_id, previous_body, edited_at)
+		VALUES ($1::uuid, $2::uuid, $3, $4)
+	`, e.MessageID, e.EditorID, previous, e.EditedAt); err != nil {
+		return 0, err
+	}
+	return seq, tx.Commit(ctx)
+}
+
+func (r *PgChatRepository) DeleteMessage(ctx context.Context, m chat.Message) (int64, *chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return 0, nil, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	seq, err := nextSeq(ctx, tx, m.ConversationID)
+	if err != nil {
+		return 0, nil, err
+	}
+	var rootID *string
+	err = tx.QueryRow(ctx, `
+		UPDATE chat.message
+		SET body = NULL, attachment_url = NULL, attachment_meta = NULL, attachment_id = NULL,
+		    deleted_at = $2, deleted_by = $3::uuid, change_seq = $4
+		WHERE id = $1::uuid AND deleted_at IS NULL
+		RETURNING thread_root_id::text
+	`, m.ID, m.DeletedAt, m.DeletedBy, seq).Scan(&rootID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Deleted content must not survive in the edit history either
+	if _, err := tx.Exec(ctx, `DELETE FROM chat.message_edit WHERE message_id = $1::uuid`, m.ID); err != nil {
+		return 0, nil, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM chat.reaction WHERE message_id = $1::uuid`, m.ID); err != nil {
+		return 0, nil, err
+	}
+
+	var root *chat.Message
+	if rootID != nil {
+		// The root counts live replies only; its last-reply time falls back to the newest reply left
+		rows, err := tx.Query(ctx, `
+			UPDATE chat.message root
+			SET thread_reply_count = GREATEST(root.thread_reply_count - 1, 0),
+			    thread_last_reply_at = (
+			      SELECT max(reply.created_at)
+			      FROM chat.message reply
+			      WHERE reply.thread_root_id = root.id AND reply.deleted_at IS NULL
+			    ),
+			    change_seq = $2
+			WHERE root.id = $1::uuid
+			RETURNING `+messageColumns, *rootID, seq)
+		if err != nil {
+			return 0, nil, err
+		}
+		roots, err := scanMessages(rows)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(roots) > 0 {
+			root = &roots[0]
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, nil, err
+	}
+	return seq, root, nil
+}
+
+func (r *PgChatRepository) ListMessageEdits(ctx context.Context, messageID string) ([]chat.MessageEdit, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT id::text, message_id::text, editor_id::text, previous_body, edited_at
+		FROM chat.message_edit
+		WHERE message_id = $1::uuid
+		ORDER BY edited_at, id
+	`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edits []chat.MessageEdit
+	for rows.Next() {
+		var e chat.MessageEdit
+		if err := rows.Scan(&e.ID, &e.MessageID, &e.EditorID, &e.PreviousBody, &e.EditedAt); err != nil {
+			return nil, err
+		}
+		edits = append(edits, e)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return edits, nil
+}
+
+// headlineOptions shapes search snippets: up to two fragments around the matches, which are wrapped in the highlight markers.
+var headlineOptions = fmt.Sprintf(`StartSel="%s", StopSel="%s", MinWords=8, MaxWords=24, MaxFragments=2, FragmentDelimiter=" … "`,
+	chat.HighlightStart, chat.HighlightStop)
+
+func (r *PgChatRepository) SearchMessages(ctx context.Context, q repository.MessageSearchQuery) ([]chat.MessageSearchHit, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	var beforeAt *time.Time
+	var beforeID *string
+	if q.Before != nil {
+		beforeAt, beforeID = &q.Before.CreatedAt, &q.Before.ID
+	}
+	var msgType *int16
+	if q.MsgType != nil {
+		t := int16(*q.MsgType)
+		msgType = &t
+	}
+
+	// The query is parsed once, with the session's configuration (SEARCH_LANGUAGE) and with "simple", so
+	// idx_message_search serves the match and messages indexed under another SEARCH_LANGUAGE still match
+	// on their unstemmed words. The highlight markers are stripped from bodies first, so only ts_headline
+	// places them. Messages the searcher cannot see, because they joined a group hiding its history
+	// afterwards, are left out
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+messageColumns+`,
+		       ts_headline(m.search_config, translate(coalesce(m.body, ''), chr(2) || chr(3), ''), query.q, $11)
+		FROM chat.message m,
+		     (SELECT websearch_to_tsquery(get_current_ts_config(), $2)
+		             || websearch_to_tsquery('simple'::regconfig, $2) AS q) query
+		WHERE m.search_vector @@ query.q
+		  AND m.deleted_at IS NULL
+		  AND EXISTS (
+		    SELECT 1
+		    FROM chat.participant p
+		    JOIN chat.conversation c ON c.id = p.conversation_id
+		    WHERE p.conversation_id = m.conversation_id AND p.user_id = $1::uuid
+		      AND (c.kind <> 1 OR NOT c.history_hidden OR m.created_at >= p.joined_at)
+		  )
+		  AND ($3::uuid IS NULL OR m.conversation_id = $3::uuid)
+		  AND ($4::uuid IS NULL OR m.sender_id = $4::uuid)
+		  AND ($5::timestamp IS NULL OR m.created_at >= $5)
+		  AND ($6::timestamp IS NULL OR m.created_at < $6)
+		  AND ($7::smallint IS NULL OR m.msg_type = $7)
+		  AND ($8::timestamp IS NULL OR (m.created_at, m.id) < ($8, $9::uuid))
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $10
+	`, q.UserID, q.Text, q.ConversationID, q.SenderID, q.From, q.To, msgType, beforeAt, beforeID, limit, headlineOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []chat.MessageSearchHit
+	for rows.Next() {
+		var hit chat.MessageSearchHit
+		if hit.Message, err = scanMessage(rows, &hit.Snippet); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return hits, nil
+}
+
+func (r *PgChatRepository) SaveAttachment(ctx context.Context, a chat.Attachment) (string, error) {
+	if r == nil || r.pool == nil {
+		return "", errors.New("PgChatRepository: nil pool")
+	}
+	var id string
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO chat.attachment (owner_id, storage_key, file_name, content_type, size_bytes, created_at)
+		VALUES ($1::uuid, $2, $3, $4, $5, $6)
+		RETURNING id::text
+	`, a.OwnerID, a.StorageKey, a.FileName, a.ContentType, a.Size, a.CreatedAt).Scan(&id)
+	return id, err
+}
+
+func (r *PgChatRepository) GetAttachment(ctx context.Context, attachmentID string) (chat.Attachment, error) {
+	if r == nil || r.pool == nil {
+		return chat.Attachment{}, errors.New("PgChatRepository: nil pool")
+	}
+	var a chat.Attachment
+	err := r.pool.QueryRow(ctx, `
+		SELECT id::text, owner_id::text, storage_key, file_name, content_type, size_bytes, created_at,
+		       sniffed_type, checksum, width, height, thumbnail_key, processed_at, rejected
+		FROM chat.attachment
+		WHERE id = $1::uuid
+	`, attachmentID).Scan(&a.ID, &a.OwnerID, &a.StorageKey, &a.FileName, &a.ContentType, &a.Size, &a.CreatedAt,
+		&a.SniffedType, &a.Checksum, &a.Width, &a.Height, &a.ThumbnailKey, &a.ProcessedAt, &a.Rejected)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return chat.Attachment{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return chat.Attachment{}, err
+	}
+	return a, nil
+}
+
+func (r *PgChatRepository) SaveAttachmentProcessing(ctx context.Context, a chat.Attachment) (chat.Message, bool, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, false, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return chat.Message{}, false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE chat.attachment
+		SET sniffed_type = $2, checksum = $3, width = $4, height = $5, thumbnail_key = $6, processed_at = $7, rejected = $8
+		WHERE id = $1::uuid
+	`, a.ID, a.SniffedType, a.Checksum, a.Width, a.Height, a.ThumbnailKey, a.ProcessedAt, a.Rejected); err != nil {
+		return chat.Message{}, false, err
+	}
+
+	var conversationID string
+	err = tx.QueryRow(ctx, `
+		SELECT conversation_id::text
+		FROM chat.message
+		WHERE attachment_id = $1::uuid AND deleted_at IS NULL
+	`, a.ID).Scan(&conversationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return chat.Message{}, false, tx.Commit(ctx)
+	}
+	if err != nil {
+		return chat.Message{}, false, err
+	}
+
+	seq, err := nextSeq(ctx, tx, conversationID)
+	if err != nil {
+		return chat.Message{}, false, err
+	}
+	// The message may have been deleted meanwhile; the sequence number is then skipped
+	rows, err := tx.Query(ctx, `
+		UPDATE chat.message
+		SET attachment_meta = $2::json, msg_type = $3, change_seq = $4
+		WHERE attachment_id = $1::uuid AND deleted_at IS NULL
+		RETURNING `+messageColumns, a.ID, a.Metadata(), a.MessageType(), seq)
+	if err != nil {
+		return chat.Message{}, false, err
+	}
+	msgs, err := scanMessages(rows)
+	if err != nil {
+		return chat.Message{}, false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return chat.Message{}, false, err
+	}
+	if len(msgs) == 0 {
+		return chat.Message{}, false, nil
+	}
+	return msgs[0], true, nil
+}
+
+func (r *PgChatRepository) GetMessageByAttachment(ctx context.Context, attachmentID string) (chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return chat.Message{}, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM chat.message
+		WHERE attachment_id = $1::uuid
+	`, attachmentID)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	msgs, err := scanMessages(rows)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	if len(msgs) == 0 {
+		return chat.Message{}, repository.ErrNotFound
+	}
+	return msgs[0], nil
+}
+
+func (r *PgChatRepository) AddReaction(ctx context.Context, conversationID string, rc chat.Reaction, maxPerUser int, maxEmojis int) (int64, bool, error) {
+	if r == nil || r.pool == nil {
+		return 0, false, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Rolled back, along with the number, unless the reaction is stored
+	seq, err := nextSeq(ctx, tx, conversationID)
+	if err != nil {
+		return 0, false, err
+	}
+	// The message row lock serialises reactions to the message, so the limits hold under concurrent adds;
+	// a deleted message takes no reactions
+	var locked bool
+	err = tx.QueryRow(ctx, `
+		SELECT true
+		FROM chat.message
+		WHERE id = $1::uuid AND conversation_id = $2::uuid AND deleted_at IS NULL
+		FOR UPDATE
+	`, rc.MessageID, conversationID).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	ct, err := tx.Exec(ctx, `
+		INSERT INTO chat.reaction (message_id, user_id, emoji, created_at)
+		SELECT $1::uuid, $2::uuid, $3, $4
+		WHERE (SELECT count(*) FROM chat.reaction WHERE message_id = $1::uuid AND user_id = $2::uuid) < $5
+		  AND (
+		    EXISTS (SELECT 1 FROM chat.reaction WHERE message_id = $1::uuid AND emoji = $3)
+		    OR (SELECT count(DISTINCT emoji) FROM chat.reaction WHERE message_id = $1::uuid) < $6
+		  )
+		ON CONFLICT (message_id, user_id, emoji) DO NOTHING
+	`, rc.MessageID, rc.UserID, rc.Emoji, rc.CreatedAt, maxPerUser, maxEmojis)
+	if err != nil {
+		return 0, false, err
+	}
+	if ct.RowsAffected() == 0 {
+		// Either the user already reacted with the emoji, which is not an error, or a limit was reached
+		var exists bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM chat.reaction WHERE message_id = $1::uuid AND user_id = $2::uuid AND emoji = $3
+			)
+		`, rc.MessageID, rc.UserID, rc.Emoji).Scan(&exists); err != nil {
+			return 0, false, err
+		}
+		if !exists {
+			return 0, false, repository.ErrLimitReached
+		}
+		return 0, false, nil
+	}
+
+	if err := touchMessage(ctx, tx, rc.MessageID, seq); err != nil {
+		return 0, false, err
+	}
+	return seq, true, tx.Commit(ctx)
+}
+
+func (r *PgChatRepository) RemoveReaction(ctx context.Context, conversationID string, messageID string, userID string, emoji string) (int64, error) {
+	if r == nil || r.pool == nil {
+		return 0, errors.New("PgChatRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	seq, err := nextSeq(ctx, tx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	ct, err := tx.Exec(ctx, `
+		DELETE FROM chat.reaction
+		WHERE message_id = $1::uuid AND user_id = $2::uuid AND emoji = $3
+	`, messageID, userID, emoji)
+	if err != nil {
+		return 0, err
+	}
+	if ct.RowsAffected() == 0 {
+		return 0, repository.ErrNotFound
+	}
+
+	if err := touchMessage(ctx, tx, messageID, seq); err != nil {
+		return 0, err
+	}
+	return seq, tx.Commit(ctx)
+}
+
+// touchMessage records a change to the message's reactions as its latest change, so replay carries the
+// message, and its current reactions, again.
+func touchMessage(ctx context.Context, tx pgx.Tx, messageID string, seq int64) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE chat.message
+		SET change_seq = $2
+		WHERE id = $1::uuid
+	`, messageID, seq)
+	return err
+}
+
+func (r *PgChatRepository) ListReactions(ctx context.Context, messageID string) ([]chat.Reaction, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT message_id::text, user_id::text, emoji, created_at
+		FROM chat.reaction
+		WHERE message_id = $1::uuid
+		ORDER BY created_at, user_id
+	`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactions []chat.Reaction
+	for rows.Next() {
+		var rc chat.Reaction
+		if err := rows.Scan(&rc.MessageID, &rc.UserID, &rc.Emoji, &rc.CreatedAt); err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, rc)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return reactions, nil
+}
+
+func (r *PgChatRepository) CountReactions(ctx context.Context, messageIDs []string, viewerID string) (map[string][]chat.ReactionCount, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	counts := make(map[string][]chat.ReactionCount)
+	if len(messageIDs) == 0 {
+		return counts, nil
+	}
+	// Emojis are listed in the order they first appeared on each message
+	rows, err := r.pool.Query(ctx, `
+		SELECT message_id::text, emoji, count(*), bool_or(user_id = $2::uuid)
+		FROM chat.reaction
+		WHERE message_id = ANY($1::uuid[])
+		GROUP BY message_id, emoji
+		ORDER BY message_id, min(created_at), emoji
+	`, messageIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			messageID string
+			rc        chat.ReactionCount
+		)
+		if err := rows.Scan(&messageID, &rc.Emoji, &rc.Count, &rc.Reacted); err != nil {
+			return nil, err
+		}
+		counts[messageID] = append(counts[messageID], rc)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return counts, nil
+}
+
+func (r *PgChatRepository) GetMessagesByConversation(ctx context.Context, q repository.MessageQuery) ([]chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	switch {
+	case q.Around != nil:
+		// Anchor and newer half ascending, then the older half; stitched newest first
+		newerLimit := limit - limit/2
+		newer, err := r.queryMessagesFrom(ctx, q, *q.Around, true, newerLimit)
+		if err != nil {
+			return nil, err
+		}
+		older, err := r.queryMessagesBefore(ctx, q, q.Around, limit-len(newer))
+		if err != nil {
+			return nil, err
+		}
+		return append(reverseMessages(newer), older...), nil
+	case q.After != nil:
+		newer, err := r.queryMessagesFrom(ctx, q, *q.After, false, limit)
+		if err != nil {
+			return nil, err
+		}
+		return reverseMessages(newer), nil
+	case q.Before != nil:
+		return r.queryMessagesBefore(ctx, q, q.Before, limit)
+	}
+
+	offset := q.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM chat.message
+		WHERE conversation_id = $1::uuid
+		  AND `+threadFilter(q, 5)+`
+		  AND ($4::timestamp IS NULL OR created_at >= $4)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, q.ConversationID, limit, offset, q.Since, q.ThreadRootID)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+func (r *PgChatRepository) ListMessageChanges(ctx context.Context, conversationID string, afterSeq int64, limit int) ([]chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM chat.message
+		WHERE conversation_id = $1::uuid
+		  AND thread_root_id IS NULL
+		  AND change_seq > $2
+		ORDER BY change_seq
+		LIMIT $3
+	`, conversationID, afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+// queryMessagesBefore returns up to limit messages of q's timeline strictly older than cursor, newest first.
+func (r *PgChatRepository) queryMessagesBefore(ctx context.Context, q repository.MessageQuery, cursor *chat.MessageCursor, limit int) ([]chat.Message, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM chat.message
+		WHERE conversation_id = $1::uuid
+		  AND `+threadFilter(q, 6)+`
+		  AND ($2::timestamp IS NULL OR created_at >= $2)
+		  AND (created_at, id) < ($3, $4::uuid)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5
+	`, q.ConversationID, q.Since, cursor.CreatedAt, cursor.ID, limit, q.ThreadRootID)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+// queryMessagesFrom returns up to limit messages of q's timeline newer than cursor (inclusive when requested), oldest first.
+func (r *PgChatRepository) queryMessagesFrom(ctx context.Context, q repository.MessageQuery, cursor chat.MessageCursor, inclusive bool, limit int) ([]chat.Message, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM chat.message
+		WHERE conversation_id = $1::uuid
+		  AND `+threadFilter(q, 7)+`
+		  AND ($2::timestamp IS NULL OR created_at >= $2)
+		  AND ((created_at, id) > ($3, $4::uuid) OR ($5 AND id = $4::uuid))
+		ORDER BY created_at ASC, id ASC
+		LIMIT $6
+	`, q.ConversationID, q.Since, cursor.CreatedAt, cursor.ID, inclusive, limit, q.ThreadRootID)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+// threadFilter restricts a message query to q's timeline: the replies of q.ThreadRootID, bound to $param, or the
+// top-level messages when it is nil. The parameter is referenced either way so the argument list stays the same,
+// and each shape gets its own statement so the thread index can serve thread pages.
+func threadFilter(q repository.MessageQuery, param int) string {
+	if q.ThreadRootID != nil {
+		return fmt.Sprintf("thread_root_id = $%d::uuid", param)
+	}
+	return fmt.Sprintf("(thread_root_id IS NULL AND $%d::uuid IS NULL)", param)
+}
+
+// messageColumns is the select list read by scanMessages.
+const messageColumns = `id::text, conversation_id::text, sender_id::text, created_at, body, msg_type, attachment_url,
+		attachment_meta, dedupe_key, edited_at, deleted_at, deleted_by::text, reply_to_id::text, thread_root_id::text,
+		thread_reply_count, thread_last_reply_at, seq, change_seq, attachment_id::text`
+
+func scanMessages(rows pgx.Rows) ([]chat.Message, error) {
+	defer rows.Close()
+
+	var msgs []chat.Message
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return msgs, nil
+}
+
+// scanMessage reads the messageColumns of the current row, followed by any extra columns into extra.
+func scanMessage(rows pgx.Rows, extra ...any) (chat.Message, error) {
+	var (
+		msg     chat.Message
+		body    *string
+		attURL  *string
+		attMeta *string
+		dedupe  *string
+	)
+	dest := []any{&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.CreatedAt, &body, &msg.MsgType, &attURL, &attMeta, &dedupe,
+		&msg.EditedAt, &msg.DeletedAt, &msg.DeletedBy, &msg.ReplyToID, &msg.ThreadRootID, &msg.ReplyCount, &msg.LastReplyAt,
+		&msg.Seq, &msg.ChangeSeq, &msg.AttachmentID}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return chat.Message{}, err
+	}
+	msg.Body = body
+	msg.AttachmentURL = attURL
+	msg.AttachmentMeta = attMeta
+	msg.DedupeKey = dedupe
+	return msg, nil
+}
+
+func reverseMessages(msgs []chat.Message) []chat.Message {
+	for i, j := 0, len(msgs)-1; i < j; i, j = i+1, j-1 {
+		msgs[i], msgs[j] = msgs[j], msgs[i]
+	}
+	return msgs
 }
 
-func (r *PgChatRepository) CreateConversation(ctx context.Context, c chat.Conversation) (string, error) {
+func (r *PgChatRepository) AdvanceReadState(ctx context.Context, conversationID string, userID string, messageID string, at time.Time) (int64, bool, error) {
 	if r == nil || r.pool == nil {
-		return "", errors.New("PgChatRepository: nil pool")
+		return 0, false, errors.New("PgChatRepository: nil pool")
 	}
-	var id string
-	err := r.pool.QueryRow(ctx,
-		"INSERT INTO chat.conversation (created_at, tenant_id) VALUES ($1, NULLIF($2, '')::uuid) RETURNING id::text",
-		c.CreatedAt, c.TenantID,
-	).Scan(&id)
-	return id, err
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Rolled back, along with the number, unless the watermark moves
+	seq, err := nextSeq(ctx, tx, conversationID)
+	if err != nil {
+		return 0, false, err
+	}
+	// The row lock taken by UPDATE serialises concurrent reads of the same participant,
+	// and the watermark comparison is re-evaluated against the latest row version.
+	ct, err := tx.Exec(ctx, `
+		UPDATE chat.participant p
+		SET last_read_msg = m.id, read_seq = $4, read_at = $5
+		FROM chat.message m
+		WHERE p.conversation_id = $1::uuid AND p.user_id = $2::uuid
+		  AND m.id = $3::uuid AND m.conversation_id = p.conversation_id
+		  AND NOT EXISTS (
+			SELECT 1 FROM chat.message cur
+			WHERE cur.id = p.last_read_msg AND (cur.created_at, cur.id) >= (m.created_at, m.id)
+		  )
+	`, conversationID, userID, messageID, seq, at)
+	if err != nil {
+		return 0, false, err
+	}
+	if ct.RowsAffected() == 0 {
+		return 0, false, nil
+	}
+	return seq, true, tx.Commit(ctx)
 }
 
-func (r *PgChatRepository) AddParticipant(ctx context.Context, p chat.Participant) error {
+func (r *PgChatRepository) ListReadChanges(ctx context.Context, conversationID string, afterSeq int64, limit int) ([]chat.ReadReceipt, error) {
 	if r == nil || r.pool == nil {
-		return errors.New("PgChatRepository: nil pool")
+		return nil, errors.New("PgChatRepository: nil pool")
 	}
-	_, err := r.pool.Exec(ctx, `
-		INSERT INTO chat.participant (conversation_id, user_id, role, last_read_msg, muted_until)
-		VALUES ($1::uuid, $2::uuid, $3, $4::uuid, $5)
-		ON CONFLICT (conversation_id, user_id)
-		DO UPDATE SET role = EXCLUDED.role,
-		              last_read_msg = EXCLUDED.last_read_msg,
-		              muted_until = EXCLUDED.muted_until
-	`, p.ConversationID, p.UserID, p.Role, p.LastReadMsg, p.MutedUntil)
-	return err
+	rows, err := r.pool.Query(ctx, `
+		SELECT conversation_id::text, user_id::text, last_read_msg::text, read_at, read_seq
+		FROM chat.participant
+		WHERE conversation_id = $1::uuid
+		  AND read_seq > $2
+		  AND last_read_msg IS NOT NULL
+		ORDER BY read_seq
+		LIMIT $3
+	`, conversationID, afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reads []chat.ReadReceipt
+	for rows.Next() {
+		var rr chat.ReadReceipt
+		if err := rows.Scan(&rr.ConversationID, &rr.UserID, &rr.MessageID, &rr.At, &rr.Seq); err != nil {
+			return nil, err
+		}
+		reads = append(reads, rr)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return reads, nil
 }
 
-func (r *PgChatRepository) SaveMessage(ctx context.Context, m chat.Message) (string, error) {
+// unreadCountSQL counts, per participant row p, the messages of other senders after the read watermark.
+// Messages sent before the participant joined never count as unread, and neither do deleted messages,
+// thread replies, which stay off the timeline the watermark moves along, or system records.
+const unreadCountSQL = `
+	SELECT count(*)
+	FROM chat.message m
+	LEFT JOIN chat.message r ON r.id = p.last_read_msg
+	WHERE m.conversation_id = p.conversation_id
+	  AND m.sender_id <> p.user_id
+	  AND m.created_at >= p.joined_at
+	  AND m.deleted_at IS NULL
+	  AND m.thread_root_id IS NULL
+	  AND m.msg_type <> 3
+	  AND (r.id IS NULL OR (m.created_at, m.id) > (r.created_at, r.id))
+`
+
+func (r *PgChatRepository) CountUnread(ctx context.Context, conversationID string, userID string) (int, error) {
 	if r == nil || r.pool == nil {
-		return "", errors.New("PgChatRepository: nil pool")
+		return 0, errors.New("PgChatRepository: nil pool")
 	}
-	var id string
+	var n int
 	err := r.pool.QueryRow(ctx, `
-		INSERT INTO chat.message (
-			conversation_id, sender_id, created_at, body, msg_type, attachment_url, attachment_meta, dedupe_key
-		) VALUES ($1::uuid, $2::uuid, $3, $4, $5, $6, COALESCE($7::json, NULL), $8)
-		RETURNING id::text
-	`, m.ConversationID, m.SenderID, m.CreatedAt, m.Body, m.MsgType, m.AttachmentURL, m.AttachmentMeta, m.DedupeKey).Scan(&id)
-	return id, err
+		SELECT (`+unreadCountSQL+`)
+		FROM chat.participant p
+		WHERE p.conversation_id = $1::uuid AND p.user_id = $2::uuid
+	`, conversationID, userID).Scan(&n)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *PgChatRepository) ListUnreadMessages(ctx context.Context, conversationID string, userID string, limit int) ([]chat.Message, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		WITH w AS (
+			SELECT p.joined_at, r.created_at AS read_created_at, r.id AS read_id
+			FROM chat.participant p
+			LEFT JOIN chat.message r ON r.id = p.last_read_msg
+			WHERE p.conversation_id = $1::uuid AND p.user_id = $2::uuid
+		)
+		SELECT `+messageColumns+`
+		FROM chat.message, w
+		WHERE conversation_id = $1::uuid
+		  AND sender_id <> $2::uuid
+		  AND created_at >= w.joined_at
+		  AND deleted_at IS NULL
+		  AND thread_root_id IS NULL
+		  AND msg_type <> 3
+		  AND (w.read_id IS NULL OR (created_at, id) > (w.read_created_at, w.read_id))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`, conversationID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanMessages(rows)
+}
+
+func (r *PgChatRepository) ListUnreadCounts(ctx context.Context, userID string) (map[string]int, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT p.conversation_id::text, (`+unreadCountSQL+`)
+		FROM chat.participant p
+		WHERE p.user_id = $1::uuid
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			convID string
+			n      int
+		)
+		if err := rows.Scan(&convID, &n); err != nil {
+			return nil, err
+		}
+		counts[convID] = n
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return counts, nil
 }
 
-func (r *PgChatRepository) GetMessagesByConversation(ctx context.Context, conversationID string, limit int, offset int) ([]chat.Message, error) {
+func (r *PgChatRepository) ListInbox(ctx context.Context, q repository.InboxQuery) ([]chat.InboxEntry, error) {
 	if r == nil || r.pool == nil {
 		return nil, errors.New("PgChatRepository: nil pool")
 	}
+	limit := q.Limit
 	if limit <= 0 {
-		limit = 50
+		limit = 20
 	}
-	if offset < 0 {
-		offset = 0
+	var beforeAt *time.Time
+	var beforeID *string
+	if q.Before != nil {
+		beforeAt, beforeID = &q.Before.LastActivityAt, &q.Before.ID
 	}
+
+	// The page is cut first so unread counts and members are only computed for the conversations returned
 	rows, err := r.pool.Query(ctx, `
-		SELECT id::text, conversation_id::text, sender_id::text, created_at, body, msg_type, attachment_url, attachment_meta, dedupe_key
-		FROM chat.message
-		WHERE conversation_id = $1::uuid
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`, conversationID, limit, offset)
+		SELECT p.conversation_id::text, p.created_at, p.tenant_id::text, p.kind, p.history_hidden, p.title, p.avatar_url,
+		       COALESCE(p.pair_key, ''), p.last_message_id::text, p.last_activity_at,
+		       p.role, p.last_read_msg::text, p.muted_until, p.joined_at, p.notify_level,
+		       (`+unreadCountSQL+`),
+		       ARRAY(
+		         SELECT o.user_id::text
+		         FROM chat.participant o
+		         WHERE o.conversation_id = p.conversation_id AND o.user_id <> p.user_id
+		         ORDER BY o.joined_at, o.user_id
+		         LIMIT $5
+		       ),
+		       (SELECT count(*) FROM chat.participant o WHERE o.conversation_id = p.conversation_id)
+		FROM (
+		  SELECT pp.conversation_id, pp.user_id, pp.role, pp.last_read_msg, pp.muted_until, pp.joined_at, pp.notify_level,
+		         c.created_at, c.tenant_id, c.kind, c.history_hidden, c.title, c.avatar_url, c.pair_key,
+		         c.last_message_id, c.last_activity_at
+		  FROM chat.participant pp
+		  JOIN chat.conversation c ON c.id = pp.conversation_id
+		  WHERE pp.user_id = $1::uuid
+		    AND ($2::timestamp IS NULL OR (c.last_activity_at, c.id) < ($2, $3::uuid))
+		  ORDER BY c.last_activity_at DESC, c.id DESC
+		  LIMIT $4
+		) p
+		ORDER BY p.last_activity_at DESC, p.conversation_id DESC
+	`, q.UserID, beforeAt, beforeID, limit, max(q.OthersLimit, 0))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var msgs []chat.Message
+	var (
+		entries []chat.InboxEntry
+		lastIDs []string
+	)
 	for rows.Next() {
 		var (
-			msg     chat.Message
-			body    *string
-			attURL  *string
-			attMeta *string
-			dedupe  *string
+			e      chat.InboxEntry
+			tenant *string
 		)
-		if err := rows.Scan(&msg.ID, &msg.ConversationID, &msg.SenderID, &msg.CreatedAt, &body, &msg.MsgType, &attURL, &attMeta, &dedupe); err != nil {
+		if err := rows.Scan(&e.Conversation.ID, &e.Conversation.CreatedAt, &tenant, &e.Conversation.Kind,
+			&e.Conversation.HistoryHidden, &e.Conversation.Title, &e.Conversation.AvatarURL, &e.Conversation.PairKey,
+			&e.Conversation.LastMessageID, &e.Conversation.LastActivityAt,
+			&e.Membership.Role, &e.Membership.LastReadMsg, &e.Membership.MutedUntil, &e.Membership.JoinedAt, &e.Membership.NotifyLevel,
+			&e.UnreadCount, &e.OtherIDs, &e.MemberCount); err != nil {
 			return nil, err
 		}
-		msg.Body = body
-		msg.AttachmentURL = attURL
-		msg.AttachmentMeta = attMeta
-		msg.DedupeKey = dedupe
-		msgs = append(msgs, msg)
+		if tenant != nil {
+			e.Conversation.TenantID = *tenant
+		}
+		e.Membership.ConversationID, e.Membership.UserID = e.Conversation.ID, q.UserID
+		if id := e.Conversation.LastMessageID; id != nil {
+			lastIDs = append(lastIDs, *id)
+		}
+		entries = append(entries, e)
 	}
 	if rows.Err() != nil {
 		return nil, rows.Err()
 	}
-	return msgs, nil
+	if len(lastIDs) == 0 {
+		return entries, nil
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM chat.message
+		WHERE id = ANY($1::uuid[])
+	`, lastIDs)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := scanMessages(rows)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*chat.Message, len(msgs))
+	for i := range msgs {
+		byID[msgs[i].ID] = &msgs[i]
+	}
+	for i := range entries {
+		if id := entries[i].Conversation.LastMessageID; id != nil {
+			entries[i].LastMessage = byID[*id]
+		}
+	}
+	return entries, nil
 }
 
-func (r *PgChatRepository) UpdateParticipantReadState(ctx context.Context, conversationID string, userID string, lastReadMsg *string) error {
+func (r *PgChatRepository) SetMuteUntil(ctx context.Context, conversationID string, userID string, mutedUntil *time.Time) error {
 	if r == nil || r.pool == nil {
 		return errors.New("PgChatRepository: nil pool")
 	}
 	ct, err := r.pool.Exec(ctx, `
 		UPDATE chat.participant
-		SET last_read_msg = $3::uuid
+		SET muted_until = $3
 		WHERE conversation_id = $1::uuid AND user_id = $2::uuid
-	`, conversationID, userID, lastReadMsg)
+	`, conversationID, userID, mutedUntil)
 	if err != nil {
 		return err
 	}
 	if ct.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+		return repository.ErrNotFound
 	}
 	return nil
 }
 
-func (r *PgChatRepository) SetMuteUntil(ctx context.Context, conversationID string, userID string, mutedUntil *time.Time) error {
+func (r *PgChatRepository) SetNotificationLevel(ctx context.Context, conversationID string, userID string, level chat.NotificationLevel) error {
 	if r == nil || r.pool == nil {
 		return errors.New("PgChatRepository: nil pool")
 	}
 	ct, err := r.pool.Exec(ctx, `
 		UPDATE chat.participant
-		SET muted_until = $3
+		SET notify_level = $3
 		WHERE conversation_id = $1::uuid AND user_id = $2::uuid
-	`, conversationID, userID, mutedUntil)
+	`, conversationID, userID, level)
 	if err != nil {
 		return err
 	}
 	if ct.RowsAffected() == 0 {
-		return pgx.ErrNoRows
+		return repository.ErrNotFound
 	}
 	return nil
 }
@@ -181,3 +1498,143 @@ func (r *PgChatRepository) ListParticipantIDs(ctx context.Context, conversationI
 	}
 	return ids, nil
 }
+
+func (r *PgChatRepository) ListContactIDs(ctx context.Context, userID string) ([]string, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT DISTINCT other.user_id::text
+		FROM chat.participant self
+		JOIN chat.participant other ON other.conversation_id = self.conversation_id AND other.user_id <> self.user_id
+		WHERE self.user_id = $1::uuid
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return ids, nil
+}
+
+func (r *PgChatRepository) BlockUser(ctx context.Context, b chat.Block) error {
+	if r == nil || r.pool == nil {
+		return errors.New("PgChatRepository: nil pool")
+	}
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO chat.block (blocker_id, blocked_id, at)
+		VALUES ($1::uuid, $2::uuid, $3)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+	`, b.BlockerId, b.BlockedId, b.CreatedAt)
+	return err
+}
+
+func (r *PgChatRepository) UnblockUser(ctx context.Context, blockerID string, blockedID string) error {
+	if r == nil || r.pool == nil {
+		return errors.New("PgChatRepository: nil pool")
+	}
+	ct, err := r.pool.Exec(ctx, `
+		DELETE FROM chat.block
+		WHERE blocker_id = $1::uuid AND blocked_id = $2::uuid
+	`, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PgChatRepository) ListBlocked(ctx context.Context, blockerID string) ([]chat.Block, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT blocker_id::text, blocked_id::text, at
+		FROM chat.block
+		WHERE blocker_id = $1::uuid
+		ORDER BY at DESC
+	`, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []chat.Block
+	for rows.Next() {
+		var b chat.Block
+		if err := rows.Scan(&b.BlockerId, &b.BlockedId, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return blocks, nil
+}
+
+func (r *PgChatRepository) ListBlockedBy(ctx context.Context, blockedID string) ([]chat.Block, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgChatRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT blocker_id::text, blocked_id::text, at
+		FROM chat.block
+		WHERE blocked_id = $1::uuid
+		ORDER BY at DESC
+	`, blockedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []chat.Block
+	for rows.Next() {
+		var b chat.Block
+		if err := rows.Scan(&b.BlockerId, &b.BlockedId, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return blocks, nil
+}
+
+func (r *PgChatRepository) FindBlock(ctx context.Context, userID string, otherIDs []string) (chat.Block, error) {
+	if r == nil || r.pool == nil {
+		return chat.Block{}, errors.New("PgChatRepository: nil pool")
+	}
+	if len(otherIDs) == 0 {
+		return chat.Block{}, repository.ErrNotFound
+	}
+	var b chat.Block
+	err := r.pool.QueryRow(ctx, `
+		SELECT blocker_id::text, blocked_id::text, at
+		FROM chat.block
+		WHERE (blocker_id = $1::uuid AND blocked_id = ANY($2::uuid[]))
+		   OR (blocked_id = $1::uuid AND blocker_id = ANY($2::uuid[]))
+		LIMIT 1
+	`, userID, otherIDs).Scan(&b.BlockerId, &b.BlockedId, &b.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return chat.Block{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return chat.Block{}, err
+	}
+	return b, nil
+}
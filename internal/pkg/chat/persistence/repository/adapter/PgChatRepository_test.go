@@ -0,0 +1,175 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+)
+
+func TestSearchMessagesUsesSearchIndex(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewPgChatRepository(pool)
+	ctx := context.Background()
+	alice, bob := uuid.NewString(), uuid.NewString()
+	conv := newTestConversation(t, repo, alice, bob)
+
+	saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: bob}, "The runners were running late")
+	if _, err := pool.Exec(ctx, `UPDATE chat.message SET search_config = 'simple' WHERE conversation_id = $1::uuid`, conv); err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: bob}, "She runs every morning")
+
+	// "run" matches the stemmed english message; the one indexed as simple matches its exact word
+	for text, want := range map[string]int{"run": 1, "running": 2, "walking": 0} {
+		hits, err := repo.SearchMessages(ctx, repository.MessageSearchQuery{UserID: alice, Text: text})
+		if err != nil {
+			t.Fatalf("SearchMessages(%q): %v", text, err)
+		}
+		if len(hits) != want {
+			t.Fatalf("SearchMessages(%q) = %d hits, want %d", text, len(hits), want)
+		}
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `SET LOCAL enable_seqscan = off`); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	rows, err := tx.Query(ctx, `
+		EXPLAIN SELECT m.id
+		FROM chat.message m,
+		     (SELECT websearch_to_tsquery(get_current_ts_config(), $1)
+		             || websearch_to_tsquery('simple'::regconfig, $1) AS q) query
+		WHERE m.search_vector @@ query.q
+	`, "running")
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		plan = append(plan, line)
+	}
+	if !strings.Contains(strings.Join(plan, "\n"), "idx_message_search") {
+		t.Fatalf("search does not use idx_message_search:\n%s", strings.Join(plan, "\n"))
+	}
+}
+
+func TestMembershipChangesKeepAnOwner(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewPgChatRepository(pool)
+	ctx := context.Background()
+	alice, bob, carol := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	conv := newTestConversation(t, repo, alice, bob, carol)
+	record := func(actor string) chat.Message {
+		return chat.NewSystemMessage(conv, chat.SystemEvent{Event: chat.SystemEventParticipantLeft, ActorID: actor, UserID: actor}, time.Now())
+	}
+	if _, err := repo.UpdateParticipantRole(ctx, conv, bob, chat.ParticipantRoleOwner, record(alice)); err != nil {
+		t.Fatalf("promote bob: %v", err)
+	}
+
+	// Both owners leave at once, each having seen the other as an owner: one of them must stay
+	errs := make(chan error, 2)
+	for _, owner := range []string{alice, bob} {
+		go func() {
+			_, err := repo.RemoveParticipant(ctx, conv, owner, record(owner))
+			errs <- err
+		}()
+	}
+	var lastOwner int
+	for range 2 {
+		switch err := <-errs; {
+		case errors.Is(err, repository.ErrLastOwner):
+			lastOwner++
+		case err != nil:
+			t.Fatalf("RemoveParticipant: %v", err)
+		}
+	}
+	if lastOwner != 1 {
+		t.Fatalf("%d of the two owners were kept from leaving, want 1", lastOwner)
+	}
+
+	participants, err := repo.ListParticipants(ctx, conv)
+	if err != nil {
+		t.Fatalf("ListParticipants: %v", err)
+	}
+	var owner string
+	for _, p := range participants {
+		if p.Role == chat.ParticipantRoleOwner {
+			owner = p.UserID
+		}
+	}
+	if owner == "" {
+		t.Fatalf("no owner left among %+v", participants)
+	}
+	if _, err := repo.UpdateParticipantRole(ctx, conv, owner, chat.ParticipantRoleAdmin, record(owner)); !errors.Is(err, repository.ErrLastOwner) {
+		t.Fatalf("demoting the last owner: err = %v, want ErrLastOwner", err)
+	}
+	if _, err := repo.RemoveParticipant(ctx, conv, carol, record(carol)); err != nil {
+		t.Fatalf("RemoveParticipant(carol): %v", err)
+	}
+	// The last participant may leave
+	if _, err := repo.RemoveParticipant(ctx, conv, owner, record(owner)); err != nil {
+		t.Fatalf("RemoveParticipant(last): %v", err)
+	}
+}
+
+func TestUnreadCountsOnlyLiveTimelineMessages(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewPgChatRepository(pool)
+	ctx := context.Background()
+	alice, bob := uuid.NewString(), uuid.NewString()
+	conv := newTestConversation(t, repo, alice, bob)
+
+	read := saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: bob}, "read already")
+	if _, _, err := repo.AdvanceReadState(ctx, conv, alice, read.ID, time.Now()); err != nil {
+		t.Fatalf("AdvanceReadState: %v", err)
+	}
+	root := saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: bob}, "unread root")
+	saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: bob, ThreadRootID: &root.ID}, "thread reply")
+	saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: alice}, "own message")
+	deleted := saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: bob}, "deleted")
+	now := time.Now().UTC()
+	deleted.DeletedAt, deleted.DeletedBy = &now, &bob
+	if _, _, err := repo.DeleteMessage(ctx, deleted); err != nil {
+		t.Fatalf("DeleteMessage: %v", err)
+	}
+	if _, err := repo.UpdateConversationProfile(ctx, conv, nil, nil, chat.NewSystemMessage(conv, chat.SystemEvent{
+		Event: chat.SystemEventConversationUpdated, ActorID: bob,
+	}, time.Now())); err != nil {
+		t.Fatalf("UpdateConversationProfile: %v", err)
+	}
+	last := saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: bob}, "unread last")
+
+	n, err := repo.CountUnread(ctx, conv, alice)
+	if err != nil {
+		t.Fatalf("CountUnread: %v", err)
+	}
+	counts, err := repo.ListUnreadCounts(ctx, alice)
+	if err != nil {
+		t.Fatalf("ListUnreadCounts: %v", err)
+	}
+	msgs, err := repo.ListUnreadMessages(ctx, conv, alice, 10)
+	if err != nil {
+		t.Fatalf("ListUnreadMessages: %v", err)
+	}
+	if n != 2 || counts[conv] != 2 || len(msgs) != 2 {
+		t.Fatalf("CountUnread = %d, ListUnreadCounts = %d, ListUnreadMessages = %d messages, want 2", n, counts[conv], len(msgs))
+	}
+	if msgs[0].ID != last.ID || msgs[1].ID != root.ID {
+		t.Fatalf("ListUnreadMessages = %s, %s, want %s, %s", msgs[0].ID, msgs[1].ID, last.ID, root.ID)
+	}
+}
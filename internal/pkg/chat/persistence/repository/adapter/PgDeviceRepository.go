@@ -0,0 +1,112 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PgDeviceRepository implements repository.DeviceRepository using PostgreSQL (pgxpool)
+type PgDeviceRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgDeviceRepository(pool *pgxpool.Pool) *PgDeviceRepository {
+	return &PgDeviceRepository{pool: pool}
+}
+
+// Ensure interface compliance at compile time
+var _ repository.DeviceRepository = (*PgDeviceRepository)(nil)
+
+func (r *PgDeviceRepository) SaveDeviceToken(ctx context.Context, d chat.DeviceToken) error {
+	if r == nil || r.pool == nil {
+		return errors.New("PgDeviceRepository: nil pool")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if d.DeviceID != nil {
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM chat.device_token
+			WHERE user_id = $1::uuid AND device_id = $2 AND token <> $3
+		`, d.UserID, *d.DeviceID, d.Token); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO chat.device_token (token, user_id, platform, device_id, created_at, updated_at)
+		VALUES ($1, $2::uuid, $3, $4, $5, $5)
+		ON CONFLICT (token)
+		DO UPDATE SET user_id = EXCLUDED.user_id,
+		              platform = EXCLUDED.platform,
+		              device_id = EXCLUDED.device_id,
+		              updated_at = EXCLUDED.updated_at
+	`, d.Token, d.UserID, d.Platform, d.DeviceID, d.UpdatedAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PgDeviceRepository) DeleteDeviceToken(ctx context.Context, userID string, token string) error {
+	if r == nil || r.pool == nil {
+		return errors.New("PgDeviceRepository: nil pool")
+	}
+	ct, err := r.pool.Exec(ctx, `
+		DELETE FROM chat.device_token
+		WHERE token = $2 AND user_id = $1::uuid
+	`, userID, token)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PgDeviceRepository) ListDeviceTokens(ctx context.Context, userID string) ([]chat.DeviceToken, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgDeviceRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT token, user_id::text, platform, device_id, created_at, updated_at
+		FROM chat.device_token
+		WHERE user_id = $1::uuid
+		ORDER BY updated_at DESC, token
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var devices []chat.DeviceToken
+	for rows.Next() {
+		var d chat.DeviceToken
+		if err := rows.Scan(&d.Token, &d.UserID, &d.Platform, &d.DeviceID, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return devices, nil
+}
+
+func (r *PgDeviceRepository) RemoveDeviceTokens(ctx context.Context, tokens []string) error {
+	if r == nil || r.pool == nil {
+		return errors.New("PgDeviceRepository: nil pool")
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	_, err := r.pool.Exec(ctx, `DELETE FROM chat.device_token WHERE token = ANY($1)`, tokens)
+	return err
+}
@@ -0,0 +1,162 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// outboxChannel is the notification channel announcing new outbox entries.
+const outboxChannel = "chat_outbox"
+
+// PgOutboxRepository implements repository.OutboxRepository using PostgreSQL (pgxpool)
+type PgOutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgOutboxRepository(pool *pgxpool.Pool) *PgOutboxRepository {
+	return &PgOutboxRepository{pool: pool}
+}
+
+// Ensure interface compliance at compile time
+var _ repository.OutboxRepository = (*PgOutboxRepository)(nil)
+
+// writeOutbox queues a change for publishing within tx; listeners hear about it once tx commits.
+func writeOutbox(ctx context.Context, tx pgx.Tx, kind chat.OutboxKind, conversationID string, messageID *string) error {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO chat.outbox (kind, conversation_id, message_id)
+		VALUES ($1, $2::uuid, $3::uuid)
+	`, kind, conversationID, messageID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `SELECT pg_notify($1, '')`, outboxChannel)
+	return err
+}
+
+func (r *PgOutboxRepository) ListOutboxConversations(ctx context.Context, limit int) ([]string, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgOutboxRepository: nil pool")
+	}
+	// Only the oldest live entry of a conversation can be due: the others wait for it
+	rows, err := r.pool.Query(ctx, `
+		SELECT o.conversation_id::text
+		FROM chat.outbox o
+		WHERE o.dead_at IS NULL
+		  AND o.next_attempt_at <= now() AT TIME ZONE 'utc'
+		  AND NOT EXISTS (
+		    SELECT 1
+		    FROM chat.outbox w
+		    WHERE w.conversation_id = o.conversation_id AND w.id < o.id AND w.dead_at IS NULL
+		  )
+		ORDER BY o.id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *PgOutboxRepository) RelayConversationOutbox(ctx context.Context, conversationID string, limit int, publish repository.OutboxPublisher) (int, error) {
+	if r == nil || r.pool == nil {
+		return 0, errors.New("PgOutboxRepository: nil pool")
+	}
+	n := 0
+	for n < limit {
+		relayed, err := r.relayNext(ctx, conversationID, publish)
+		if err != nil || !relayed {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// relayNext claims the oldest live entry of conversationID if it is due, publishes it and records the outcome.
+// The claim moves the entry's next attempt chat.OutboxClaimLease away, which holds back the conversation for
+// other relays while this one publishes, without keeping a transaction open. It reports false when no entry
+// was due, e.g. because another relay claimed it. An entry whose outcome is not recorded is published again
+// once the claim runs out: delivery is at least once.
+func (r *PgOutboxRepository) relayNext(ctx context.Context, conversationID string, publish repository.OutboxPublisher) (bool, error) {
+	var e chat.OutboxEntry
+	err := r.pool.QueryRow(ctx, `
+		UPDATE chat.outbox
+		SET next_attempt_at = now() AT TIME ZONE 'utc' + $2::float8 * interval '1 second'
+		WHERE id = (
+		    SELECT h.id
+		    FROM chat.outbox h
+		    WHERE h.conversation_id = $1::uuid AND h.dead_at IS NULL
+		    ORDER BY h.id
+		    LIMIT 1
+		  )
+		  AND next_attempt_at <= now() AT TIME ZONE 'utc'
+		RETURNING id, event_id::text, kind, conversation_id::text, message_id::text, created_at, attempts
+	`, conversationID, chat.OutboxClaimLease.Seconds()).Scan(&e.ID, &e.EventID, &e.Kind, &e.ConversationID, &e.MessageID, &e.CreatedAt, &e.Attempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if perr := publish(ctx, e); perr != nil {
+		delay := chat.OutboxRetryDelay(e.Attempts + 1)
+		_, err = r.pool.Exec(ctx, `
+			UPDATE chat.outbox
+			SET attempts = attempts + 1,
+			    last_error = $2,
+			    next_attempt_at = now() AT TIME ZONE 'utc' + $3::float8 * interval '1 second',
+			    dead_at = CASE WHEN attempts + 1 >= $4 THEN now() AT TIME ZONE 'utc' END
+			WHERE id = $1
+		`, e.ID, perr.Error(), delay.Seconds(), chat.MaxOutboxAttempts)
+	} else {
+		_, err = r.pool.Exec(ctx, `DELETE FROM chat.outbox WHERE id = $1`, e.ID)
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *PgOutboxRepository) ListenOutbox(ctx context.Context, notify func()) error {
+	if r == nil || r.pool == nil {
+		return errors.New("PgOutboxRepository: nil pool")
+	}
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Leave no listener behind on the pooled connection
+		unlistenCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, _ = conn.Exec(unlistenCtx, `UNLISTEN `+outboxChannel)
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, `LISTEN `+outboxChannel); err != nil {
+		return err
+	}
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+		notify()
+	}
+}
@@ -0,0 +1,237 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+)
+
+// outboxLog records the entries a publisher was handed, failing those fail picks.
+type outboxLog struct {
+	entries []chat.OutboxEntry
+	fail    func(e chat.OutboxEntry) error
+}
+
+func (l *outboxLog) publish(_ context.Context, e chat.OutboxEntry) error {
+	l.entries = append(l.entries, e)
+	if l.fail != nil {
+		return l.fail(e)
+	}
+	return nil
+}
+
+// messageIDs returns the message of each recorded entry, "" for conversation entries.
+func (l *outboxLog) messageIDs() []string {
+	ids := make([]string, len(l.entries))
+	for i, e := range l.entries {
+		if e.MessageID != nil {
+			ids[i] = *e.MessageID
+		}
+	}
+	return ids
+}
+
+// newOutboxConversation creates a conversation with messages and returns it with the message ids, in the
+// order their outbox entries were queued after the conversation's own.
+func newOutboxConversation(t *testing.T, repo *PgChatRepository, messages int) (string, []string) {
+	t.Helper()
+	alice, bob := uuid.NewString(), uuid.NewString()
+	conv := newTestConversation(t, repo, alice, bob)
+	ids := make([]string, messages)
+	for i := range ids {
+		ids[i] = saveTestMessage(t, repo, chat.Message{ConversationID: conv, SenderID: alice}, "hello").ID
+	}
+	return conv, ids
+}
+
+// makeDue lets the retry delays and claims of the conversation's entries run out.
+func makeDue(t *testing.T, pool *pgxpool.Pool, conversationID string) {
+	t.Helper()
+	if _, err := pool.Exec(context.Background(), `
+		UPDATE chat.outbox
+		SET next_attempt_at = now() AT TIME ZONE 'utc' - interval '1 second'
+		WHERE conversation_id = $1::uuid
+	`, conversationID); err != nil {
+		t.Fatalf("make due: %v", err)
+	}
+}
+
+func TestRelayConversationOutboxKeepsOrder(t *testing.T) {
+	pool := newTestPool(t)
+	repo, outbox := NewPgChatRepository(pool), NewPgOutboxRepository(pool)
+	ctx := context.Background()
+	convA, msgsA := newOutboxConversation(t, repo, 3)
+	convB, _ := newOutboxConversation(t, repo, 1)
+
+	due, err := outbox.ListOutboxConversations(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListOutboxConversations: %v", err)
+	}
+	if !slices.Equal(due, []string{convA, convB}) {
+		t.Fatalf("due conversations = %v, want %s then %s", due, convA, convB)
+	}
+
+	// The limit stops a conversation part-way; the next call carries on where it stopped
+	log := &outboxLog{}
+	for _, limit := range []int{2, 10} {
+		if _, err := outbox.RelayConversationOutbox(ctx, convA, limit, log.publish); err != nil {
+			t.Fatalf("RelayConversationOutbox: %v", err)
+		}
+	}
+	if got, want := log.messageIDs(), append([]string{""}, msgsA...); !slices.Equal(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	if log.entries[0].Kind != chat.OutboxConversationCreated || log.entries[1].Kind != chat.OutboxMessageCreated {
+		t.Fatalf("published kinds %d, %d", log.entries[0].Kind, log.entries[1].Kind)
+	}
+
+	due, err = outbox.ListOutboxConversations(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListOutboxConversations: %v", err)
+	}
+	if !slices.Equal(due, []string{convB}) {
+		t.Fatalf("due conversations after relaying %s = %v, want %s", convA, due, convB)
+	}
+}
+
+func TestRelayConversationOutboxFailedEntryHoldsBackLaterOnes(t *testing.T) {
+	pool := newTestPool(t)
+	repo, outbox := NewPgChatRepository(pool), NewPgOutboxRepository(pool)
+	ctx := context.Background()
+	conv, msgs := newOutboxConversation(t, repo, 2)
+
+	failing := &outboxLog{fail: func(e chat.OutboxEntry) error {
+		if e.MessageID != nil && *e.MessageID == msgs[0] {
+			return errors.New("queue unavailable")
+		}
+		return nil
+	}}
+	n, err := outbox.RelayConversationOutbox(ctx, conv, 10, failing.publish)
+	if err != nil {
+		t.Fatalf("RelayConversationOutbox: %v", err)
+	}
+	if n != 2 || !slices.Equal(failing.messageIDs(), []string{"", msgs[0]}) {
+		t.Fatalf("relayed %d: %v, want the conversation then the failing %s only", n, failing.messageIDs(), msgs[0])
+	}
+
+	// The failed entry waits for its retry and the entry after it waits for the failed one
+	due, err := outbox.ListOutboxConversations(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListOutboxConversations: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("due conversations = %v, want none before the retry", due)
+	}
+	log := &outboxLog{}
+	if n, err := outbox.RelayConversationOutbox(ctx, conv, 10, log.publish); err != nil || n != 0 {
+		t.Fatalf("RelayConversationOutbox before the retry = %d, %v", n, err)
+	}
+
+	makeDue(t, pool, conv)
+	if _, err := outbox.RelayConversationOutbox(ctx, conv, 10, log.publish); err != nil {
+		t.Fatalf("RelayConversationOutbox: %v", err)
+	}
+	if !slices.Equal(log.messageIDs(), msgs) {
+		t.Fatalf("published %v on retry, want %v", log.messageIDs(), msgs)
+	}
+	if log.entries[0].Attempts != 1 || log.entries[1].Attempts != 0 {
+		t.Fatalf("attempts = %d, %d, want 1, 0", log.entries[0].Attempts, log.entries[1].Attempts)
+	}
+}
+
+func TestRelayConversationOutboxClaimLease(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewPgChatRepository(pool)
+	replicaA, replicaB := NewPgOutboxRepository(pool), NewPgOutboxRepository(pool)
+	conv, msgs := newOutboxConversation(t, repo, 1)
+
+	// Replica A claims the head and, while it publishes, replica B finds the conversation held
+	ctx, crash := context.WithCancel(context.Background())
+	var duringClaim int
+	_, err := replicaA.RelayConversationOutbox(ctx, conv, 10, func(ctx context.Context, e chat.OutboxEntry) error {
+		n, err := replicaB.RelayConversationOutbox(context.Background(), conv, 10, (&outboxLog{}).publish)
+		if err != nil {
+			t.Errorf("replica B: %v", err)
+		}
+		duringClaim = n
+		// A dies before it can record the outcome
+		crash()
+		return ctx.Err()
+	})
+	if err == nil {
+		t.Fatalf("RelayConversationOutbox recorded an outcome after its context ended")
+	}
+	if duringClaim != 0 {
+		t.Fatalf("replica B relayed %d entries of a claimed conversation", duringClaim)
+	}
+
+	// The claim outlives A until its lease runs out
+	log := &outboxLog{}
+	if n, err := replicaB.RelayConversationOutbox(context.Background(), conv, 10, log.publish); err != nil || n != 0 {
+		t.Fatalf("replica B within the lease = %d, %v", n, err)
+	}
+	makeDue(t, pool, conv)
+	if _, err := replicaB.RelayConversationOutbox(context.Background(), conv, 10, log.publish); err != nil {
+		t.Fatalf("replica B: %v", err)
+	}
+	// Published again from the claimed entry on, without counting as a failed attempt
+	if !slices.Equal(log.messageIDs(), []string{"", msgs[0]}) || log.entries[0].Attempts != 0 {
+		t.Fatalf("replica B published %v with %d attempts", log.messageIDs(), log.entries[0].Attempts)
+	}
+}
+
+func TestRelayConversationOutboxDeadLetters(t *testing.T) {
+	pool := newTestPool(t)
+	repo, outbox := NewPgChatRepository(pool), NewPgOutboxRepository(pool)
+	ctx := context.Background()
+	conv, msgs := newOutboxConversation(t, repo, 1)
+
+	failing := &outboxLog{fail: func(e chat.OutboxEntry) error {
+		if e.Kind == chat.OutboxConversationCreated {
+			return errors.New("webhook queue unavailable")
+		}
+		return nil
+	}}
+	for attempt := 1; attempt <= chat.MaxOutboxAttempts; attempt++ {
+		if n, err := outbox.RelayConversationOutbox(ctx, conv, 1, failing.publish); err != nil || n != 1 {
+			t.Fatalf("attempt %d: RelayConversationOutbox = %d, %v", attempt, n, err)
+		}
+		makeDue(t, pool, conv)
+	}
+	if len(failing.entries) != chat.MaxOutboxAttempts || failing.messageIDs()[len(failing.entries)-1] != "" {
+		t.Fatalf("published %v, want the conversation entry %d times", failing.messageIDs(), chat.MaxOutboxAttempts)
+	}
+
+	var dead int
+	var lastError string
+	if err := pool.QueryRow(ctx, `
+		SELECT count(*), max(last_error) FROM chat.outbox WHERE conversation_id = $1::uuid AND dead_at IS NOT NULL
+	`, conv).Scan(&dead, &lastError); err != nil {
+		t.Fatalf("count dead entries: %v", err)
+	}
+	if dead != 1 || lastError != "webhook queue unavailable" {
+		t.Fatalf("%d dead entries, last error %q", dead, lastError)
+	}
+
+	// The dead entry no longer holds back the conversation
+	log := &outboxLog{}
+	if _, err := outbox.RelayConversationOutbox(ctx, conv, 10, log.publish); err != nil {
+		t.Fatalf("RelayConversationOutbox: %v", err)
+	}
+	if !slices.Equal(log.messageIDs(), msgs) {
+		t.Fatalf("published %v after dead-lettering, want %v", log.messageIDs(), msgs)
+	}
+	due, err := outbox.ListOutboxConversations(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListOutboxConversations: %v", err)
+	}
+	if len(due) != 0 {
+		t.Fatalf("due conversations = %v, want the dead entry left out", due)
+	}
+}
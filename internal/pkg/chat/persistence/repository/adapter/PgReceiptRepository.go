@@ -0,0 +1,114 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PgReceiptRepository implements repository.ReceiptRepository using PostgreSQL (pgxpool)
+type PgReceiptRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgReceiptRepository(pool *pgxpool.Pool) *PgReceiptRepository {
+	return &PgReceiptRepository{pool: pool}
+}
+
+// Ensure interface compliance at compile time
+var _ repository.ReceiptRepository = (*PgReceiptRepository)(nil)
+
+func (r *PgReceiptRepository) SaveReceipts(ctx context.Context, receipts []chat.Receipt) ([]chat.Receipt, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgReceiptRepository: nil pool")
+	}
+	if len(receipts) == 0 {
+		return nil, nil
+	}
+
+	messageIDs := make([]string, len(receipts))
+	userIDs := make([]string, len(receipts))
+	statuses := make([]int16, len(receipts))
+	ats := make([]time.Time, len(receipts))
+	for i, rc := range receipts {
+		messageIDs[i] = rc.MessageID
+		userIDs[i] = rc.UserID
+		statuses[i] = int16(rc.Status)
+		ats[i] = rc.At
+	}
+
+	// One round trip per batch; rows already stored are left untouched and not returned
+	rows, err := r.pool.Query(ctx, `
+		INSERT INTO chat.receipt (message_id, user_id, status, at)
+		SELECT DISTINCT ON (message_id, user_id, status) message_id, user_id, status, at
+		FROM unnest($1::uuid[], $2::uuid[], $3::smallint[], $4::timestamp[]) AS t(message_id, user_id, status, at)
+		ORDER BY message_id, user_id, status, at
+		ON CONFLICT (message_id, user_id, status) DO NOTHING
+		RETURNING message_id::text, user_id::text, status
+	`, messageIDs, userIDs, statuses, ats)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type key struct {
+		messageID string
+		userID    string
+		status    chat.ReceiptStatus
+	}
+	inserted := make(map[key]struct{})
+	for rows.Next() {
+		var k key
+		if err := rows.Scan(&k.messageID, &k.userID, &k.status); err != nil {
+			return nil, err
+		}
+		inserted[k] = struct{}{}
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	saved := make([]chat.Receipt, 0, len(inserted))
+	for _, rc := range receipts {
+		k := key{rc.MessageID, rc.UserID, rc.Status}
+		if _, ok := inserted[k]; ok {
+			saved = append(saved, rc)
+			delete(inserted, k)
+		}
+	}
+	return saved, nil
+}
+
+func (r *PgReceiptRepository) ListReceipts(ctx context.Context, messageID string) ([]chat.Receipt, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgReceiptRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT message_id::text, user_id::text, status, at
+		FROM chat.receipt
+		WHERE message_id = $1::uuid
+		ORDER BY at, user_id
+	`, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var receipts []chat.Receipt
+	for rows.Next() {
+		var rc chat.Receipt
+		if err := rows.Scan(&rc.MessageID, &rc.UserID, &rc.Status, &rc.At); err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, rc)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return receipts, nil
+}
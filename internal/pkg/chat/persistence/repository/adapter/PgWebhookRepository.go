@@ -0,0 +1,277 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	repository "go-chatty/internal/pkg/chat/persistence/repository/port"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PgWebhookRepository implements repository.WebhookRepository using PostgreSQL (pgxpool)
+type PgWebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgWebhookRepository(pool *pgxpool.Pool) *PgWebhookRepository {
+	return &PgWebhookRepository{pool: pool}
+}
+
+// Ensure interface compliance at compile time
+var _ repository.WebhookRepository = (*PgWebhookRepository)(nil)
+
+// sameTenantSQL matches the subscription's tenant against the tenant id in parameter $1 ("" for none),
+// in the form idx_webhook_subscription_tenant indexes.
+const sameTenantSQL = `COALESCE(s.tenant_id, '00000000-0000-0000-0000-000000000000'::uuid) =
+	COALESCE(NULLIF($1, '')::uuid, '00000000-0000-0000-0000-000000000000'::uuid)`
+
+const subscriptionColumns = `s.id::text, COALESCE(s.tenant_id::text, ''), s.url, s.secret, s.events, s.description, s.created_at, s.updated_at`
+
+const deliveryColumns = `d.id::text, d.subscription_id::text, d.event_id::text, d.event_type, d.payload::text, d.status, d.attempts,
+	d.last_status_code, d.last_error, d.next_attempt_at, d.created_at, d.updated_at, d.delivered_at`
+
+func (r *PgWebhookRepository) CreateWebhookSubscription(ctx context.Context, s chat.WebhookSubscription) (string, error) {
+	if r == nil || r.pool == nil {
+		return "", errors.New("PgWebhookRepository: nil pool")
+	}
+	var id string
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO chat.webhook_subscription (tenant_id, url, secret, events, description, created_at, updated_at)
+		VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5, $6, $7)
+		RETURNING id::text
+	`, s.TenantID, s.URL, s.Secret, eventTypeStrings(s.Events), s.Description, s.CreatedAt, s.UpdatedAt).Scan(&id)
+	return id, err
+}
+
+func (r *PgWebhookRepository) GetWebhookSubscription(ctx context.Context, id string) (chat.WebhookSubscription, error) {
+	if r == nil || r.pool == nil {
+		return chat.WebhookSubscription{}, errors.New("PgWebhookRepository: nil pool")
+	}
+	row := r.pool.QueryRow(ctx, `
+		SELECT `+subscriptionColumns+`
+		FROM chat.webhook_subscription s
+		WHERE s.id = $1::uuid
+	`, id)
+	s, err := scanSubscription(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return chat.WebhookSubscription{}, repository.ErrNotFound
+	}
+	return s, err
+}
+
+func (r *PgWebhookRepository) ListWebhookSubscriptions(ctx context.Context, tenantID string) ([]chat.WebhookSubscription, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgWebhookRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+subscriptionColumns+`
+		FROM chat.webhook_subscription s
+		WHERE `+sameTenantSQL+`
+		ORDER BY s.created_at, s.id
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []chat.WebhookSubscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
+
+func (r *PgWebhookRepository) DeleteWebhookSubscription(ctx context.Context, tenantID string, id string) error {
+	if r == nil || r.pool == nil {
+		return errors.New("PgWebhookRepository: nil pool")
+	}
+	ct, err := r.pool.Exec(ctx, `
+		DELETE FROM chat.webhook_subscription s
+		WHERE s.id = $2::uuid AND `+sameTenantSQL+`
+	`, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PgWebhookRepository) CreateWebhookDeliveries(ctx context.Context, e chat.Event, payload []byte, now time.Time) ([]chat.WebhookDelivery, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgWebhookRepository: nil pool")
+	}
+	// Deliveries already recorded for the event are updated in place so that they are returned too
+	rows, err := r.pool.Query(ctx, `
+		INSERT INTO chat.webhook_delivery AS d (subscription_id, event_id, event_type, payload, next_attempt_at, created_at, updated_at)
+		SELECT s.id, $2::uuid, $3::text, $4::jsonb, $5::timestamp, $5::timestamp, $5::timestamp
+		FROM chat.webhook_subscription s
+		WHERE `+sameTenantSQL+`
+		  AND (cardinality(s.events) = 0 OR $3::text = ANY(s.events))
+		ON CONFLICT (subscription_id, event_id) DO UPDATE SET event_id = d.event_id
+		RETURNING `+deliveryColumns+`
+	`, e.TenantID, e.ID, string(e.Type), string(payload), now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []chat.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+func (r *PgWebhookRepository) GetWebhookDelivery(ctx context.Context, id string) (chat.WebhookDelivery, error) {
+	if r == nil || r.pool == nil {
+		return chat.WebhookDelivery{}, errors.New("PgWebhookRepository: nil pool")
+	}
+	row := r.pool.QueryRow(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM chat.webhook_delivery d
+		WHERE d.id = $1::uuid
+	`, id)
+	d, err := scanDelivery(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return chat.WebhookDelivery{}, repository.ErrNotFound
+	}
+	return d, err
+}
+
+func (r *PgWebhookRepository) UpdateWebhookDelivery(ctx context.Context, d chat.WebhookDelivery, prevStatus chat.DeliveryStatus, prevAttempts int) error {
+	if r == nil || r.pool == nil {
+		return errors.New("PgWebhookRepository: nil pool")
+	}
+	ct, err := r.pool.Exec(ctx, `
+		UPDATE chat.webhook_delivery
+		SET status = $2, attempts = $3, last_status_code = $4, last_error = $5, next_attempt_at = $6,
+		    updated_at = $7, delivered_at = $8
+		WHERE id = $1::uuid AND status = $9 AND attempts = $10
+	`, d.ID, d.Status, d.Attempts, d.LastStatusCode, d.LastError, d.NextAttemptAt, d.UpdatedAt, d.DeliveredAt,
+		prevStatus, prevAttempts)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() == 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+func (r *PgWebhookRepository) ListWebhookDeliveries(ctx context.Context, q repository.WebhookDeliveryQuery) ([]chat.WebhookDelivery, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgWebhookRepository: nil pool")
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	var beforeAt *time.Time
+	var beforeID *string
+	if q.Before != nil {
+		beforeAt, beforeID = &q.Before.CreatedAt, &q.Before.ID
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM chat.webhook_delivery d
+		WHERE d.subscription_id = $1::uuid
+		  AND ($2::smallint IS NULL OR d.status = $2)
+		  AND ($3::timestamp IS NULL OR (d.created_at, d.id) < ($3, $4::uuid))
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $5
+	`, q.SubscriptionID, q.Status, beforeAt, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []chat.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+func (r *PgWebhookRepository) ListOverdueWebhookDeliveries(ctx context.Context, dueBefore time.Time, limit int) ([]chat.WebhookDelivery, error) {
+	if r == nil || r.pool == nil {
+		return nil, errors.New("PgWebhookRepository: nil pool")
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM chat.webhook_delivery d
+		WHERE d.status = $1 AND d.next_attempt_at < $2
+		ORDER BY d.next_attempt_at
+		LIMIT $3
+	`, chat.DeliveryStatusPending, dueBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []chat.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+func scanSubscription(row pgx.Row) (chat.WebhookSubscription, error) {
+	var (
+		s      chat.WebhookSubscription
+		events []string
+	)
+	if err := row.Scan(&s.ID, &s.TenantID, &s.URL, &s.Secret, &events, &s.Description, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return chat.WebhookSubscription{}, err
+	}
+	for _, e := range events {
+		s.Events = append(s.Events, chat.EventType(e))
+	}
+	return s, nil
+}
+
+func scanDelivery(row pgx.Row) (chat.WebhookDelivery, error) {
+	var (
+		d         chat.WebhookDelivery
+		eventType string
+		payload   string
+	)
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &eventType, &payload, &d.Status, &d.Attempts,
+		&d.LastStatusCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt, &d.DeliveredAt); err != nil {
+		return chat.WebhookDelivery{}, err
+	}
+	d.EventType = chat.EventType(eventType)
+	d.Payload = []byte(payload)
+	return d, nil
+}
+
+// eventTypeStrings converts event types for a TEXT[] parameter; an empty filter is stored as an empty array.
+func eventTypeStrings(types []chat.EventType) []string {
+	res := make([]string, 0, len(types))
+	for _, t := range types {
+		res = append(res, string(t))
+	}
+	return res
+}
@@ -0,0 +1,111 @@
+package adapter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+)
+
+// migrationDir holds the schema migrations, applied in file name order.
+const migrationDir = "../../../../../infrastructure/database/migration"
+
+// newTestPool connects to a fresh database migrated to the latest schema. The tests run against the
+// server TEST_DB_URL points at, which needs the right to create databases, and are skipped without it.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	dsn := strings.TrimSpace(os.Getenv("TEST_DB_URL"))
+	if dsn == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	admin, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer admin.Close(ctx)
+	name := "chatty_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := admin.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		t.Fatalf("create database: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if conn, err := pgx.Connect(ctx, dsn); err == nil {
+			_, _ = conn.Exec(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)")
+			_ = conn.Close(ctx)
+		}
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("parse dsn: %v", err)
+	}
+	cfg.ConnConfig.Database = name
+	cfg.ConnConfig.RuntimeParams["default_text_search_config"] = "english"
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connect %s: %v", name, err)
+	}
+	t.Cleanup(pool.Close)
+
+	files, err := filepath.Glob(filepath.Join(migrationDir, "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no migrations in %s: %v", migrationDir, err)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		sql, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatalf("read %s: %v", f, err)
+		}
+		if _, err := pool.Exec(ctx, string(sql)); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(f), err)
+		}
+	}
+	return pool
+}
+
+// newTestConversation creates a group conversation owned by the first of users, the others joining as members.
+func newTestConversation(t *testing.T, repo *PgChatRepository, users ...string) string {
+	t.Helper()
+	now := time.Now().UTC()
+	participants := make([]chat.Participant, len(users))
+	for i, u := range users {
+		participants[i] = chat.Participant{UserID: u, Role: chat.ParticipantRoleMember, JoinedAt: now}
+	}
+	participants[0].Role = chat.ParticipantRoleOwner
+	title := "test"
+	id, _, err := repo.CreateConversation(context.Background(), chat.Conversation{
+		CreatedAt: now, Kind: chat.ConversationKindGroup, Title: &title,
+	}, participants)
+	if err != nil {
+		t.Fatalf("CreateConversation: %v", err)
+	}
+	return id
+}
+
+// saveTestMessage stores a message of m's type (text unless set) with body from sender.
+func saveTestMessage(t *testing.T, repo *PgChatRepository, m chat.Message, body string) chat.Message {
+	t.Helper()
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	m.Body = &body
+	stored, _, err := repo.SaveMessage(context.Background(), m)
+	if err != nil {
+		t.Fatalf("SaveMessage: %v", err)
+	}
+	return stored
+}
@@ -2,19 +2,164 @@ package repository
 
 import (
 	"context"
+	"errors"
 	chat "go-chatty/internal/pkg/chat/application/domain"
 	"time"
 )
 
+// ErrNotFound is returned by adapters when the requested row does not exist.
+var ErrNotFound = errors.New("chat repository: not found")
+
+// ErrLimitReached is returned by adapters when a write would exceed a limit enforced in storage.
+var ErrLimitReached = errors.New("chat repository: limit reached")
+
+// ErrConflict is returned by adapters when a write would break a uniqueness rule not handled otherwise.
+var ErrConflict = errors.New("chat repository: conflict")
+
+// ErrLastOwner is returned by adapters when a membership change would leave a group whose other
+// participants remain without an owner.
+var ErrLastOwner = errors.New("chat repository: last owner")
+
+// MessageQuery selects a page of messages within a conversation, newest first.
+// At most one of Before, After and Around should be set; when one is, Offset is ignored.
+// Without ThreadRootID the page comes from the main timeline, which leaves thread replies out.
+type MessageQuery struct {
+	ConversationID string
+	Limit          int
+	Offset         int                 // legacy offset paging, kept for backward compatibility
+	Since          *time.Time          // when set, only messages created at or after Since are returned
+	Before         *chat.MessageCursor // messages strictly older than the cursor
+	After          *chat.MessageCursor // messages strictly newer than the cursor
+	Around         *chat.MessageCursor // the cursor's message plus up to Limit/2 neighbours on each side
+	ThreadRootID   *string             // when set, pages through the replies of that thread instead
+}
+
+// MessageSearchQuery selects the messages of a full-text search, newest first.
+type MessageSearchQuery struct {
+	UserID         string              // searcher: only messages they can see in conversations they participate in
+	Text           string              // web search syntax: words, "quoted phrases", OR, -excluded
+	ConversationID *string             // restrict to one conversation
+	SenderID       *string             // restrict to one sender
+	From           *time.Time          // created at or after
+	To             *time.Time          // created before
+	MsgType        *chat.MessageType   // restrict to one message type
+	Before         *chat.MessageCursor // matches strictly older than the cursor
+	Limit          int
+}
+
+// InboxQuery selects a page of a user's conversations, most recently active first.
+type InboxQuery struct {
+	UserID      string
+	Before      *chat.ConversationCursor // conversations strictly less recently active than the cursor
+	Limit       int
+	OthersLimit int // other participants listed per conversation
+}
+
 // ChatRepository defines persistence operations for the chat domain
-// Note: Receipt and Block operations were removed from Chat; handle them in a separate context/service if needed.
+// Note: Receipt operations were removed from Chat; handle them in a separate context/service if needed.
 type ChatRepository interface {
-	CreateConversation(ctx context.Context, c chat.Conversation) (string, error)
-	AddParticipant(ctx context.Context, p chat.Participant) error
-	SaveMessage(ctx context.Context, m chat.Message) (string, error)
-	GetMessagesByConversation(ctx context.Context, conversationID string, limit int, offset int) ([]chat.Message, error)
-	UpdateParticipantReadState(ctx context.Context, conversationID string, userID string, lastReadMsg *string) error
+	// CreateConversation inserts the conversation and its participants in one transaction.
+	// For direct conversations with a PairKey already taken in the tenant, nothing is inserted
+	// and the existing conversation id is returned with created=false. A created conversation is
+	// queued for publishing in the outbox within the same transaction.
+	CreateConversation(ctx context.Context, c chat.Conversation, participants []chat.Participant) (id string, created bool, err error)
+	GetConversation(ctx context.Context, conversationID string) (chat.Conversation, error)
+	// UpdateConversationProfile, AddParticipant, UpdateParticipantRole and RemoveParticipant apply a change
+	// together with the system message record announcing it, stored as SaveMessage would, and return that
+	// message as stored. Either both are written or neither is. They return ErrNotFound when the
+	// conversation or participant to change is missing. Membership changes are serialised per conversation:
+	// UpdateParticipantRole and RemoveParticipant return ErrLastOwner, writing nothing, when they would
+	// take away the last owner of a group other participants remain in.
+	UpdateConversationProfile(ctx context.Context, conversationID string, title *string, avatarURL *string, record chat.Message) (chat.Message, error)
+	AddParticipant(ctx context.Context, p chat.Participant, record chat.Message) (chat.Message, error)
+	GetParticipant(ctx context.Context, conversationID string, userID string) (chat.Participant, error)
+	ListParticipants(ctx context.Context, conversationID string) ([]chat.Participant, error)
+	UpdateParticipantRole(ctx context.Context, conversationID string, userID string, role chat.ParticipantRole, record chat.Message) (chat.Message, error)
+	RemoveParticipant(ctx context.Context, conversationID string, userID string, record chat.Message) (chat.Message, error)
+	// SaveMessage inserts m with the next number of its conversation's sequence and returns it as stored.
+	// When m carries a DedupeKey already used by the same sender in the conversation, nothing is inserted
+	// and the stored message is returned with created=false. A new thread reply bumps the reply count,
+	// last-reply time and change sequence of its root in the same transaction, while a new top-level message
+	// becomes its conversation's last message and activity time. It returns ErrConflict
+	// when m's attachment already backs another message. A created message is queued for publishing in
+	// the outbox within the same transaction.
+	SaveMessage(ctx context.Context, m chat.Message) (stored chat.Message, created bool, err error)
+	GetMessage(ctx context.Context, messageID string) (chat.Message, error)
+	// GetMessageByDedupeKey returns the message the sender stored in the conversation with dedupeKey, or
+	// ErrNotFound when there is none.
+	GetMessageByDedupeKey(ctx context.Context, conversationID string, senderID string, dedupeKey string) (chat.Message, error)
+	// EditMessage stores m's new body and EditedAt together with the edit-history entry e, and returns
+	// the sequence number of the change. The entry records the body the edit replaced as read under the
+	// message's row lock, not e.PreviousBody. It returns ErrNotFound when the message is missing or already deleted.
+	EditMessage(ctx context.Context, m chat.Message, e chat.MessageEdit) (changeSeq int64, err error)
+	// DeleteMessage turns the message into a tombstone, purges its edit history and reactions, and returns
+	// the sequence number of the change. Deleting a thread reply takes it off its root's reply count and
+	// last-reply time in the same transaction; root is then the root as updated, nil otherwise. It returns
+	// ErrNotFound when the message is missing or already deleted.
+	DeleteMessage(ctx context.Context, m chat.Message) (changeSeq int64, root *chat.Message, err error)
+	// ListMessageChanges returns the top-level messages of the conversation created or changed after
+	// sequence number afterSeq, in sequence order, at most limit of them.
+	ListMessageChanges(ctx context.Context, conversationID string, afterSeq int64, limit int) ([]chat.Message, error)
+	// ListReadChanges returns the read watermarks of the conversation moved after sequence number afterSeq,
+	// each at its latest position, in sequence order, at most limit of them.
+	ListReadChanges(ctx context.Context, conversationID string, afterSeq int64, limit int) ([]chat.ReadReceipt, error)
+	ListMessageEdits(ctx context.Context, messageID string) ([]chat.MessageEdit, error)
+	// SearchMessages returns the live messages matching q, thread replies included, each with a snippet
+	// whose matches are wrapped in chat.HighlightStart and chat.HighlightStop.
+	SearchMessages(ctx context.Context, q MessageSearchQuery) ([]chat.MessageSearchHit, error)
+
+	SaveAttachment(ctx context.Context, a chat.Attachment) (id string, err error)
+	GetAttachment(ctx context.Context, attachmentID string) (chat.Attachment, error)
+	// SaveAttachmentProcessing stores the processing results of a and refreshes the attachment_meta and
+	// msg_type of the live message carrying it. That message is returned with the sequence number of the
+	// change; updated is false when no live message carries a.
+	SaveAttachmentProcessing(ctx context.Context, a chat.Attachment) (msg chat.Message, updated bool, err error)
+	// GetMessageByAttachment returns the message backed by the attachment, or ErrNotFound while it is unused.
+	GetMessageByAttachment(ctx context.Context, attachmentID string) (chat.Message, error)
+
+	// AddReaction stores r on a message of the conversation; created is false when the user already reacted
+	// with that emoji. The limits are enforced atomically: it returns ErrLimitReached when the user already
+	// has maxPerUser emojis on the message, or when r's emoji would be the message's maxEmojis+1th distinct
+	// emoji. It returns ErrNotFound when the message is missing or deleted. A stored reaction is a change to
+	// the message: changeSeq is its sequence number, 0 when nothing was stored.
+	AddReaction(ctx context.Context, conversationID string, r chat.Reaction, maxPerUser int, maxEmojis int) (changeSeq int64, created bool, err error)
+	// RemoveReaction deletes the reaction as a change to its message and returns the sequence number of
+	// the change, or ErrNotFound.
+	RemoveReaction(ctx context.Context, conversationID string, messageID string, userID string, emoji string) (changeSeq int64, err error)
+	ListReactions(ctx context.Context, messageID string) ([]chat.Reaction, error)
+	// CountReactions aggregates reactions per emoji for each of messageIDs, keyed by message id,
+	// flagging the emojis viewerID reacted with. Messages without reactions are absent.
+	CountReactions(ctx context.Context, messageIDs []string, viewerID string) (map[string][]chat.ReactionCount, error)
+	GetMessagesByConversation(ctx context.Context, q MessageQuery) ([]chat.Message, error)
+	// AdvanceReadState moves the participant's read watermark to messageID at time at, only when that
+	// message is newer, by (created_at, id), than the current watermark; advanced reports whether it moved.
+	// A move takes the next number of the conversation's sequence, returned as seq.
+	AdvanceReadState(ctx context.Context, conversationID string, userID string, messageID string, at time.Time) (seq int64, advanced bool, err error)
+	// CountUnread counts the live top-level messages from other senders after the participant's watermark
+	// (or since joining), system records left out.
+	CountUnread(ctx context.Context, conversationID string, userID string) (int, error)
+	// ListUnreadMessages returns up to limit of the messages CountUnread counts, newest first.
+	ListUnreadMessages(ctx context.Context, conversationID string, userID string, limit int) ([]chat.Message, error)
+	// ListUnreadCounts returns CountUnread for every conversation of userID, keyed by conversation id.
+	ListUnreadCounts(ctx context.Context, userID string) (map[string]int, error)
+	// ListInbox returns the conversations of q.UserID with the user's membership, unread count, last message
+	// (whether or not the user may see it) and up to q.OthersLimit other participants.
+	ListInbox(ctx context.Context, q InboxQuery) ([]chat.InboxEntry, error)
+	// SetMuteUntil mutes the conversation for the participant until mutedUntil, or unmutes it when nil.
+	// It returns ErrNotFound when userID is not a participant.
 	SetMuteUntil(ctx context.Context, conversationID string, userID string, mutedUntil *time.Time) error
+	// SetNotificationLevel stores the participant's notification level, or returns ErrNotFound.
+	SetNotificationLevel(ctx context.Context, conversationID string, userID string, level chat.NotificationLevel) error
 	IsParticipant(ctx context.Context, conversationID string, userID string) (bool, error)
 	ListParticipantIDs(ctx context.Context, conversationID string) ([]string, error)
+	// ListContactIDs returns every other user sharing at least one conversation with userID.
+	ListContactIDs(ctx context.Context, userID string) ([]string, error)
+
+	BlockUser(ctx context.Context, b chat.Block) error
+	UnblockUser(ctx context.Context, blockerID string, blockedID string) error
+	ListBlocked(ctx context.Context, blockerID string) ([]chat.Block, error)
+	// ListBlockedBy returns the blocks other users created against blockedID.
+	ListBlockedBy(ctx context.Context, blockedID string) ([]chat.Block, error)
+	// FindBlock returns a block in either direction between userID and any of otherIDs, or ErrNotFound.
+	FindBlock(ctx context.Context, userID string, otherIDs []string) (chat.Block, error)
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+)
+
+// DeviceRepository persists the push tokens of users' devices, kept apart from ChatRepository
+// because they belong to the notification pipeline rather than to conversations.
+type DeviceRepository interface {
+	// SaveDeviceToken registers d, taking the token over from whoever held it and replacing the previous
+	// token of the same user and DeviceID. Registering a known token again refreshes it.
+	SaveDeviceToken(ctx context.Context, d chat.DeviceToken) error
+	// DeleteDeviceToken removes a token of userID, or returns ErrNotFound.
+	DeleteDeviceToken(ctx context.Context, userID string, token string) error
+	ListDeviceTokens(ctx context.Context, userID string) ([]chat.DeviceToken, error)
+	// RemoveDeviceTokens forgets tokens the push service rejected, whoever they belong to.
+	RemoveDeviceTokens(ctx context.Context, tokens []string) error
+}
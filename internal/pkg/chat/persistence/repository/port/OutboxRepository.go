@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+)
+
+// OutboxPublisher publishes an outbox entry; an error leaves the entry for a later attempt.
+type OutboxPublisher func(ctx context.Context, e chat.OutboxEntry) error
+
+// OutboxRepository hands the changes ChatRepository queues in the outbox over to be published.
+type OutboxRepository interface {
+	// ListOutboxConversations returns up to limit conversations whose oldest live entry is due, oldest first.
+	ListOutboxConversations(ctx context.Context, limit int) ([]string, error)
+	// RelayConversationOutbox passes the due entries of conversationID to publish one at a time, oldest first,
+	// and commits the outcome of each on its own: a published entry is deleted, a failed one is retried after
+	// chat.OutboxRetryDelay, the later entries of the conversation waiting for it, or dead-lettered once it
+	// failed chat.MaxOutboxAttempts times. One caller relays a conversation at a time, across processes: the
+	// others return 0 at once. publish must return within chat.OutboxClaimLease. It stops after limit entries
+	// and returns how many it published or failed.
+	RelayConversationOutbox(ctx context.Context, conversationID string, limit int, publish OutboxPublisher) (int, error)
+	// ListenOutbox calls notify whenever entries were queued, until ctx ends or the connection fails.
+	ListenOutbox(ctx context.Context, notify func()) error
+}
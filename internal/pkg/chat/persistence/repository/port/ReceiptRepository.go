@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+)
+
+// ReceiptRepository persists per-recipient message receipts, kept apart from ChatRepository
+// because receipts are written in bulk from the realtime path.
+type ReceiptRepository interface {
+	// SaveReceipts inserts receipts, skipping ones already stored, and returns those actually inserted.
+	SaveReceipts(ctx context.Context, receipts []chat.Receipt) ([]chat.Receipt, error)
+	ListReceipts(ctx context.Context, messageID string) ([]chat.Receipt, error)
+}
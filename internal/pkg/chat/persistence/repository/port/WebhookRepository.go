@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	chat "go-chatty/internal/pkg/chat/application/domain"
+)
+
+// WebhookDeliveryQuery selects a page of a subscription's deliveries, newest first.
+type WebhookDeliveryQuery struct {
+	SubscriptionID string
+	Status         *chat.DeliveryStatus // all statuses when nil
+	Before         *chat.DeliveryCursor // deliveries strictly older than the cursor
+	Limit          int
+}
+
+// WebhookRepository persists tenants' webhook subscriptions and the deliveries of events to them.
+type WebhookRepository interface {
+	// CreateWebhookSubscription inserts s and returns its generated id.
+	CreateWebhookSubscription(ctx context.Context, s chat.WebhookSubscription) (string, error)
+	// GetWebhookSubscription returns ErrNotFound for unknown ids.
+	GetWebhookSubscription(ctx context.Context, id string) (chat.WebhookSubscription, error)
+	// ListWebhookSubscriptions returns the subscriptions of tenantID ("" for none), oldest first.
+	ListWebhookSubscriptions(ctx context.Context, tenantID string) ([]chat.WebhookSubscription, error)
+	// DeleteWebhookSubscription removes a subscription of tenantID with its deliveries, or returns ErrNotFound.
+	DeleteWebhookSubscription(ctx context.Context, tenantID string, id string) error
+
+	// CreateWebhookDeliveries records a pending delivery of payload to every subscription of e's tenant that
+	// wants e, and returns the deliveries of e, including those recorded by an earlier call.
+	CreateWebhookDeliveries(ctx context.Context, e chat.Event, payload []byte, now time.Time) ([]chat.WebhookDelivery, error)
+	// GetWebhookDelivery returns ErrNotFound for unknown ids.
+	GetWebhookDelivery(ctx context.Context, id string) (chat.WebhookDelivery, error)
+	// UpdateWebhookDelivery stores the outcome of an attempt or a replay of d, provided the row still has
+	// prevStatus and prevAttempts; ErrConflict tells that someone else moved it meanwhile.
+	UpdateWebhookDelivery(ctx context.Context, d chat.WebhookDelivery, prevStatus chat.DeliveryStatus, prevAttempts int) error
+	ListWebhookDeliveries(ctx context.Context, q WebhookDeliveryQuery) ([]chat.WebhookDelivery, error)
+	// ListOverdueWebhookDeliveries returns up to limit pending deliveries whose next attempt was due before
+	// dueBefore, the longest overdue first.
+	ListOverdueWebhookDeliveries(ctx context.Context, dueBefore time.Time, limit int) ([]chat.WebhookDelivery, error)
+}
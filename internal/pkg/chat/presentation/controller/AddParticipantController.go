@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddParticipantController handles adding a member to a group (one controller per endpoint)
+type AddParticipantController struct {
+	UC *usecase.AddParticipantUseCase
+}
+
+func NewAddParticipantController(pool *pgxpool.Pool) *AddParticipantController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &AddParticipantController{UC: usecase.NewAddParticipantUseCase(repo)}
+}
+
+type addParticipantRequest struct {
+	UserID string `json:"userId" binding:"required"`
+	Role   string `json:"role"` // "member" (default), "admin" or "owner"
+}
+
+func (h *AddParticipantController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req addParticipantRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		role := chat.ParticipantRoleMember
+		if req.Role != "" {
+			parsed, err := chat.ParseParticipantRole(req.Role)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			role = parsed
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		msg, err := h.UC.Execute(ctx, usecase.AddParticipantInput{
+			ConversationID: c.Param("chatId"),
+			ActorID:        principal.UserID,
+			UserID:         req.UserID,
+			Role:           role,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"message": toPayload(*msg)})
+	}
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AddReactionController handles reacting to a message with an emoji (one controller per endpoint)
+type AddReactionController struct {
+	UC            *usecase.AddReactionUseCase
+	listBlockedUC *usecase.ListBlockedUseCase
+	router        *realtime.Router
+}
+
+func NewAddReactionController(pool *pgxpool.Pool, router *realtime.Router) *AddReactionController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &AddReactionController{
+		UC:            usecase.NewAddReactionUseCase(repo),
+		listBlockedUC: usecase.NewListBlockedUseCase(repo),
+		router:        router,
+	}
+}
+
+type addReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+func (h *AddReactionController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req addReactionRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.Emoji == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "emoji is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		result, err := h.UC.Execute(ctx, usecase.AddReactionInput{
+			ConversationID: c.Param("chatId"),
+			MessageID:      c.Param("messageId"),
+			UserID:         principal.UserID,
+			Emoji:          req.Emoji,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		status := http.StatusOK
+		if result.Added {
+			status = http.StatusCreated
+			// The reaction is stored; a failed broadcast only delays peers until their next fetch
+			_ = broadcastReaction(ctx, h.router, h.listBlockedUC, "reaction_added", result.Message, result.Reaction, result.Count, result.Seq)
+		}
+		c.JSON(status, gin.H{
+			"messageId": result.Reaction.MessageID,
+			"emoji":     result.Reaction.Emoji,
+			"count":     result.Count,
+			"createdAt": result.Reaction.CreatedAt,
+		})
+	}
+}
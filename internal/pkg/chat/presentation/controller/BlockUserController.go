@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BlockUserController handles blocking another user (one controller per endpoint)
+type BlockUserController struct {
+	UC     *usecase.BlockUserUseCase
+	router *realtime.Router
+}
+
+func NewBlockUserController(pool *pgxpool.Pool, router *realtime.Router) *BlockUserController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &BlockUserController{UC: usecase.NewBlockUserUseCase(repo), router: router}
+}
+
+type blockUserRequest struct {
+	UserID string `json:"userId"`
+}
+
+func (h *BlockUserController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req blockUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		b, err := h.UC.Execute(ctx, usecase.BlockUserInput{
+			BlockerID: principal.UserID,
+			BlockedID: req.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		// Open sessions filter ephemeral frames with in-memory block lists; keep them current
+		h.router.UpdateBlocked(b.BlockerId, b.BlockedId, true)
+		c.JSON(http.StatusCreated, gin.H{"userId": b.BlockedId, "createdAt": b.CreatedAt})
+	}
+}
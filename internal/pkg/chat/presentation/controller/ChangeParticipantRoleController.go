@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ChangeParticipantRoleController handles promoting/demoting a group member (one controller per endpoint)
+type ChangeParticipantRoleController struct {
+	UC *usecase.ChangeParticipantRoleUseCase
+}
+
+func NewChangeParticipantRoleController(pool *pgxpool.Pool) *ChangeParticipantRoleController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &ChangeParticipantRoleController{UC: usecase.NewChangeParticipantRoleUseCase(repo)}
+}
+
+type changeParticipantRoleRequest struct {
+	Role string `json:"role" binding:"required"` // "member", "admin" or "owner"
+}
+
+func (h *ChangeParticipantRoleController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req changeParticipantRoleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		role, err := chat.ParseParticipantRole(req.Role)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		userID := c.Param("userId")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		msg, err := h.UC.Execute(ctx, usecase.ChangeParticipantRoleInput{
+			ConversationID: c.Param("chatId"),
+			ActorID:        principal.UserID,
+			UserID:         userID,
+			Role:           role,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": toPayload(*msg)})
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
+	"go-chatty/internal/infrastructure/auth"
 	"go-chatty/internal/infrastructure/realtime"
+	storageport "go-chatty/internal/infrastructure/storage/port"
 	chat "go-chatty/internal/pkg/chat/application/domain"
 	"go-chatty/internal/pkg/chat/application/usecase"
 	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
@@ -20,40 +24,66 @@ import (
 // ChatSocketController handles the websocket endpoint for realtime chat traffic.
 type ChatSocketController struct {
 	router          *realtime.Router
+	upgrader        websocket.Upgrader
 	sendMessageUC   *usecase.SendMessageUseCase
 	joinRoomUC      *usecase.JoinConversationUseCase
-	listMembersUC   *usecase.ListParticipantsUseCase
+	joinThreadUC    *usecase.JoinThreadUseCase
+	listBlockedUC   *usecase.ListBlockedUseCase
+	markReadUC      *usecase.MarkReadUseCase
+	ackDeliveryUC   *usecase.AckDeliveryUseCase
+	editMessageUC   *usecase.EditMessageUseCase
+	deleteMessageUC *usecase.DeleteMessageUseCase
+	addReactionUC   *usecase.AddReactionUseCase
+	removeReactUC   *usecase.RemoveReactionUseCase
+	replayUC        *usecase.ReplayConversationUseCase
+	muteUC          *usecase.MuteConversationUseCase
+	unmuteUC        *usecase.UnmuteConversationUseCase
+	notifyLevelUC   *usecase.SetNotificationLevelUseCase
 	inflightTimeout time.Duration
 }
 
-func NewChatSocketController(pool *pgxpool.Pool, router *realtime.Router) *ChatSocketController {
+func NewChatSocketController(pool *pgxpool.Pool, router *realtime.Router, checkOrigin auth.OriginChecker, storage storageport.Storage, policy storageport.Policy) *ChatSocketController {
 	repo := repoAdapter.NewPgChatRepository(pool)
 	return &ChatSocketController{
-		router:          router,
-		sendMessageUC:   usecase.NewSendMessageUseCase(repo),
+		router: router,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     checkOrigin,
+		},
+		sendMessageUC:   usecase.NewSendMessageUseCase(repo, storage, policy),
 		joinRoomUC:      usecase.NewJoinConversationUseCase(repo),
-		listMembersUC:   usecase.NewListParticipantsUseCase(repo),
+		joinThreadUC:    usecase.NewJoinThreadUseCase(repo),
+		listBlockedUC:   usecase.NewListBlockedUseCase(repo),
+		markReadUC:      usecase.NewMarkReadUseCase(repo),
+		ackDeliveryUC:   usecase.NewAckDeliveryUseCase(repo),
+		editMessageUC:   usecase.NewEditMessageUseCase(repo),
+		deleteMessageUC: usecase.NewDeleteMessageUseCase(repo),
+		addReactionUC:   usecase.NewAddReactionUseCase(repo),
+		removeReactUC:   usecase.NewRemoveReactionUseCase(repo),
+		replayUC:        usecase.NewReplayConversationUseCase(repo),
+		muteUC:          usecase.NewMuteConversationUseCase(repo),
+		unmuteUC:        usecase.NewUnmuteConversationUseCase(repo),
+		notifyLevelUC:   usecase.NewSetNotificationLevelUseCase(repo),
 		inflightTimeout: 5 * time.Second,
 	}
 }
 
-var wsUpgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		// Allow all origins for now; plug a proper checker when auth is added.
-		return true
-	},
-}
-
 type inboundFrame struct {
-	Type           string  `json:"type"`
-	ConversationID string  `json:"conversationId,omitempty"`
-	Body           *string `json:"body,omitempty"`
-	MsgType        *int16  `json:"msgType,omitempty"`
-	AttachmentURL  *string `json:"attachmentUrl,omitempty"`
-	AttachmentMeta *string `json:"attachmentMeta,omitempty"`
-	DedupeKey      *string `json:"dedupeKey,omitempty"`
+	Type           string           `json:"type"`
+	ConversationID string           `json:"conversationId,omitempty"`
+	Body           *string          `json:"body,omitempty"`
+	MsgType        *int16           `json:"msgType,omitempty"`
+	AttachmentID   *string          `json:"attachmentId,omitempty"`
+	DedupeKey      *string          `json:"dedupeKey,omitempty"`
+	MessageID      string           `json:"messageId,omitempty"`
+	Emoji          string           `json:"emoji,omitempty"`
+	ReplyToID      *string          `json:"replyToId,omitempty"`
+	ThreadRootID   *string          `json:"threadRootId,omitempty"`
+	Conversations  map[string]int64 `json:"conversations,omitempty"`   // resume: conversationId -> last seq seen
+	Until          *time.Time       `json:"until,omitempty"`           // mute: end of the mute
+	Duration       *int64           `json:"durationSeconds,omitempty"` // mute: length of the mute
+	Level          string           `json:"level,omitempty"`           // notifications: "all", "mentions" or "none"
 }
 
 type errorFrame struct {
@@ -65,44 +95,137 @@ type errorFrame struct {
 type ackFrame struct {
 	Type           string `json:"type"`
 	ConversationID string `json:"conversationId,omitempty"`
+	MessageID      string `json:"messageId,omitempty"`
+}
+
+// sentFrame acknowledges a message to the session that sent it; the message itself reaches every session of
+// the room, this one included, from the outbox relay. Seq is the message's number in the conversation's sequence.
+type sentFrame struct {
+	Type           string    `json:"type"`
+	ConversationID string    `json:"conversationId"`
+	MessageID      string    `json:"messageId"`
+	Seq            int64     `json:"seq"`
+	DedupeKey      *string   `json:"dedupeKey,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type connectedFrame struct {
+	Type      string `json:"type"`
+	SessionID string `json:"sessionId"`
+	DeviceID  string `json:"deviceId,omitempty"`
 }
 
+// outboundMessage carries a message-log change; Seq is its number in the conversation's sequence.
 type outboundMessage struct {
 	Type           string         `json:"type"`
 	ConversationID string         `json:"conversationId"`
+	Seq            int64          `json:"seq,omitempty"`
 	Message        messagePayload `json:"message"`
 }
 
-type messagePayload struct {
-	ID             string    `json:"id"`
+// replayedMessage is a message-log change replayed on resume. It carries the message's current reactions,
+// which reconnecting clients cannot learn from the reaction frames they missed.
+type replayedMessage struct {
+	outboundMessage
+	Reactions []reactionPayload `json:"reactions"`
+}
+
+// resumedFrame closes the replay of a conversation; Seq is where the client has caught up to.
+// Truncated tells the client to page through the history over HTTP for what the replay left out.
+type resumedFrame struct {
+	Type           string `json:"type"`
+	ConversationID string `json:"conversationId"`
+	Seq            int64  `json:"seq"`
+	Truncated      bool   `json:"truncated,omitempty"`
+}
+
+// readEventFrame and reactionEventFrame carry changes numbered in the conversation's sequence like message-log changes.
+type readEventFrame struct {
+	Type           string    `json:"type"`
 	ConversationID string    `json:"conversationId"`
-	SenderID       string    `json:"senderId"`
-	CreatedAt      time.Time `json:"createdAt"`
-	Body           *string   `json:"body,omitempty"`
-	MsgType        int16     `json:"msgType"`
-	AttachmentURL  *string   `json:"attachmentUrl,omitempty"`
-	AttachmentMeta *string   `json:"attachmentMeta,omitempty"`
-	DedupeKey      *string   `json:"dedupeKey,omitempty"`
+	Seq            int64     `json:"seq,omitempty"`
+	UserID         string    `json:"userId"`
+	MessageID      string    `json:"messageId"`
+	At             time.Time `json:"at"`
 }
 
-const defaultReadTimeout = 60 * time.Second
+type reactionEventFrame struct {
+	Type           string    `json:"type"`
+	ConversationID string    `json:"conversationId"`
+	Seq            int64     `json:"seq,omitempty"`
+	MessageID      string    `json:"messageId"`
+	UserID         string    `json:"userId"`
+	Emoji          string    `json:"emoji"`
+	Count          int       `json:"count"`
+	At             time.Time `json:"at"`
+}
+
+type reactionPayload struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
+
+type messagePayload struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversationId"`
+	SenderID       string     `json:"senderId"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	Body           *string    `json:"body,omitempty"`
+	MsgType        int16      `json:"msgType"`
+	AttachmentURL  *string    `json:"attachmentUrl,omitempty"`
+	AttachmentMeta *string    `json:"attachmentMeta,omitempty"`
+	DedupeKey      *string    `json:"dedupeKey,omitempty"`
+	EditedAt       *time.Time `json:"editedAt,omitempty"`
+	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy      *string    `json:"deletedBy,omitempty"`
+	ReplyToID      *string    `json:"replyToId,omitempty"`
+	ThreadRootID   *string    `json:"threadRootId,omitempty"`
+	ReplyCount     int        `json:"replyCount,omitempty"`
+	LastReplyAt    *time.Time `json:"lastReplyAt,omitempty"`
+}
+
+type typingFrame struct {
+	Type           string `json:"type"`
+	ConversationID string `json:"conversationId"`
+	ThreadRootID   string `json:"threadRootId,omitempty"`
+	UserID         string `json:"userId"`
+}
+
+const (
+	defaultReadTimeout = 60 * time.Second
+	maxDeviceIDLength  = 64
+	typingTimeout      = 6 * time.Second  // typing_stop is sent for clients that stop refreshing typing_start
+	maxResumeRooms     = 100              // conversations a single resume frame may list
+	resumeTimeout      = 30 * time.Second // overall deadline of a resume, joins and replays included
+)
 
 // Handle upgrades HTTP connections to websocket and processes frames until the client disconnects.
 func (ctl *ChatSocketController) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userID := c.Query("userId")
-		if userID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+		userID := principal.UserID
+
+		deviceID := c.GetHeader("X-Device-ID")
+		if deviceID == "" {
+			deviceID = c.Query("deviceId")
+		}
+		if len(deviceID) > maxDeviceIDLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "deviceId is too long"})
 			return
 		}
 
-		ws, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+		ws, err := ctl.upgrader.Upgrade(c.Writer, c.Request, nil)
 		if err != nil {
 			// Upgrade already wrote the response; just log and return.
 			return
 		}
 
-		conn := realtime.NewConnection(userID, ws)
+		conn := realtime.NewConnection(userID, deviceID, ws)
 		ctl.router.Attach(conn)
 		defer func() {
 			ctl.router.Detach(conn)
@@ -112,10 +235,22 @@ func (ctl *ChatSocketController) Handle() gin.HandlerFunc {
 		ws.SetReadLimit(1 << 20) // 1MB payload cap
 		_ = ws.SetReadDeadline(time.Now().Add(defaultReadTimeout))
 		ws.SetPongHandler(func(string) error {
+			ctl.router.Heartbeat(conn)
 			return ws.SetReadDeadline(time.Now().Add(defaultReadTimeout))
 		})
 
-		handshakeAck := ackFrame{Type: "connected"}
+		// Ephemeral frames are filtered against the block list the router keeps in memory from now on
+		loadCtx, cancelLoad := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+		err = ctl.router.LoadBlocked(loadCtx, conn, func(ctx context.Context) ([]string, error) {
+			return blockedRecipients(ctx, ctl.listBlockedUC, userID)
+		})
+		cancelLoad()
+		if err != nil {
+			ctl.replyError(conn, "internal_error", "unexpected persistence error")
+			return
+		}
+
+		handshakeAck := connectedFrame{Type: "connected", SessionID: conn.ID, DeviceID: conn.DeviceID}
 		if payload, err := json.Marshal(handshakeAck); err == nil {
 			_ = conn.Send(payload)
 		}
@@ -131,6 +266,9 @@ func (ctl *ChatSocketController) Handle() gin.HandlerFunc {
 				return
 			}
 
+			// Any frame counts as user activity for presence
+			ctl.router.Activity(conn)
+
 			var frame inboundFrame
 			if err := json.Unmarshal(data, &frame); err != nil {
 				ctl.replyError(conn, "bad_request", "invalid payload")
@@ -142,8 +280,36 @@ func (ctl *ChatSocketController) Handle() gin.HandlerFunc {
 				ctl.handleJoin(c, conn, frame)
 			case "leave":
 				ctl.handleLeave(conn, frame)
+			case "resume":
+				ctl.handleResume(c, conn, frame)
+			case "join_thread":
+				ctl.handleJoinThread(c, conn, frame)
+			case "leave_thread":
+				ctl.handleLeaveThread(conn, frame)
 			case "message":
 				ctl.handleMessage(c, conn, userID, frame)
+			case "read":
+				ctl.handleRead(c, conn, userID, frame)
+			case "delivered":
+				ctl.handleDelivered(c, conn, userID, frame)
+			case "edit":
+				ctl.handleEdit(c, conn, userID, frame)
+			case "delete":
+				ctl.handleDelete(c, conn, userID, frame)
+			case "react":
+				ctl.handleReact(c, conn, userID, frame)
+			case "unreact":
+				ctl.handleUnreact(c, conn, userID, frame)
+			case "mute":
+				ctl.handleMute(c, conn, userID, frame)
+			case "unmute":
+				ctl.handleUnmute(c, conn, userID, frame)
+			case "notifications":
+				ctl.handleNotificationLevel(c, conn, userID, frame)
+			case "typing_start":
+				ctl.handleTyping(conn, userID, frame, true)
+			case "typing_stop":
+				ctl.handleTyping(conn, userID, frame, false)
 			default:
 				ctl.replyError(conn, "unsupported_type", "unknown frame type")
 			}
@@ -190,6 +356,133 @@ func (ctl *ChatSocketController) handleLeave(conn *realtime.Connection, frame in
 	}
 }
 
+// handleResume brings a reconnecting session back up to date: it rejoins the listed conversations, replays
+// the message-log changes after the sequence number the client last saw in each, and only then lets live
+// frames through. Conversations the user may not join are reported with an error frame and skipped. The
+// resume runs off the read loop, so the client's other frames are handled meanwhile, and within one overall
+// deadline; conversations it leaves no time for are reported as truncated.
+func (ctl *ChatSocketController) handleResume(c *gin.Context, conn *realtime.Connection, frame inboundFrame) {
+	if len(frame.Conversations) == 0 {
+		ctl.replyError(conn, "bad_request", "conversations is required")
+		return
+	}
+	if len(frame.Conversations) > maxResumeRooms {
+		ctl.replyError(conn, "bad_request", fmt.Sprintf("at most %d conversations can be resumed at once", maxResumeRooms))
+		return
+	}
+
+	// The gin context must not outlive the handler; the request context ends with the socket
+	ctx, cancel := context.WithTimeout(c.Request.Context(), resumeTimeout)
+	go func() {
+		defer cancel()
+		ctl.resume(ctx, conn, frame.Conversations)
+	}()
+}
+
+func (ctl *ChatSocketController) resume(ctx context.Context, conn *realtime.Connection, conversations map[string]int64) {
+	rooms := make([]string, 0, len(conversations))
+	for convID := range conversations {
+		err := ctl.joinRoomUC.Execute(ctx, usecase.JoinConversationInput{ConversationID: convID, UserID: conn.UserID})
+		if err != nil {
+			ctl.handleUseCaseError(conn, err)
+			continue
+		}
+		rooms = append(rooms, convID)
+	}
+	sort.Strings(rooms)
+
+	err := ctl.router.Resume(conn, rooms, func(send func([]byte) error) {
+		for _, convID := range rooms {
+			afterSeq := conversations[convID]
+			done := resumedFrame{Type: "resumed", ConversationID: convID, Seq: afterSeq}
+			var result *usecase.ReplayConversationOutput
+			var err error
+			if ctx.Err() == nil {
+				result, err = ctl.replayUC.Execute(ctx, usecase.ReplayConversationInput{
+					ConversationID: convID,
+					UserID:         conn.UserID,
+					AfterSeq:       afterSeq,
+				})
+			}
+			switch {
+			case ctx.Err() != nil:
+				// Out of time: the room stays joined and the client pages through the rest
+				done.Truncated = true
+			case errors.Is(err, usecase.ErrPersistence):
+				// Delivered after the replay; the room stays joined so live frames keep flowing, and the
+				// client learns it has to page through what the replay could not cover
+				ctl.handleUseCaseError(conn, err)
+				done.Truncated = true
+			case err != nil:
+				ctl.handleUseCaseError(conn, err)
+				continue
+			default:
+				for _, change := range result.Changes {
+					payload, err := encodeReplayChange(change, afterSeq, result.Reactions)
+					if err != nil {
+						continue
+					}
+					if err := send(payload); err != nil {
+						// The connection is gone
+						return
+					}
+				}
+				done.Seq = result.Seq
+				done.Truncated = result.Truncated
+			}
+			if payload, err := json.Marshal(done); err == nil {
+				if err := send(payload); err != nil {
+					return
+				}
+			}
+		}
+	})
+	if errors.Is(err, realtime.ErrResumeInProgress) {
+		ctl.replyError(conn, "conflict", "a resume is already in progress")
+	}
+}
+
+// handleJoinThread subscribes the session to the replies of one thread, whether or not it joined the conversation.
+func (ctl *ChatSocketController) handleJoinThread(c *gin.Context, conn *realtime.Connection, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.MessageID == "" {
+		ctl.replyError(conn, "bad_request", "conversationId and messageId are required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	err := ctl.joinThreadUC.Execute(ctx, usecase.JoinThreadInput{
+		ConversationID: frame.ConversationID,
+		RootID:         frame.MessageID,
+		UserID:         conn.UserID,
+	})
+	if err != nil {
+		ctl.handleUseCaseError(conn, err)
+		return
+	}
+
+	ctl.router.Join(realtime.ThreadRoom(frame.ConversationID, frame.MessageID), conn)
+
+	ack := ackFrame{Type: "thread_joined", ConversationID: frame.ConversationID, MessageID: frame.MessageID}
+	if payload, err := json.Marshal(ack); err == nil {
+		_ = conn.Send(payload)
+	}
+}
+
+func (ctl *ChatSocketController) handleLeaveThread(conn *realtime.Connection, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.MessageID == "" {
+		ctl.replyError(conn, "bad_request", "conversationId and messageId are required")
+		return
+	}
+	ctl.router.Leave(realtime.ThreadRoom(frame.ConversationID, frame.MessageID), conn)
+
+	ack := ackFrame{Type: "thread_left", ConversationID: frame.ConversationID, MessageID: frame.MessageID}
+	if payload, err := json.Marshal(ack); err == nil {
+		_ = conn.Send(payload)
+	}
+}
+
 func (ctl *ChatSocketController) handleMessage(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
 	if frame.ConversationID == "" {
 		ctl.replyError(conn, "bad_request", "conversationId is required")
@@ -209,52 +502,329 @@ func (ctl *ChatSocketController) handleMessage(c *gin.Context, conn *realtime.Co
 		SenderID:       userID,
 		Body:           frame.Body,
 		MsgType:        msgType,
-		AttachmentURL:  frame.AttachmentURL,
-		AttachmentMeta: frame.AttachmentMeta,
+		AttachmentID:   frame.AttachmentID,
 		DedupeKey:      frame.DedupeKey,
+		ReplyToID:      frame.ReplyToID,
+		ThreadRootID:   frame.ThreadRootID,
 	})
 	if err != nil {
 		ctl.handleUseCaseError(conn, err)
 		return
 	}
+	// The outbox relay announces the message to the room, the sender's sessions included, to push and
+	// to webhooks; the sending session only learns which message its frame became
+	msg := result.Message
+	ack := sentFrame{
+		Type:           "sent",
+		ConversationID: msg.ConversationID,
+		MessageID:      msg.ID,
+		Seq:            msg.Seq,
+		DedupeKey:      msg.DedupeKey,
+		CreatedAt:      msg.CreatedAt,
+	}
+	if payload, err := json.Marshal(ack); err == nil {
+		_ = conn.Send(payload)
+	}
+}
+
+// handleRead advances the reader's watermark; the room only hears about reads that moved it.
+func (ctl *ChatSocketController) handleRead(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.MessageID == "" {
+		ctl.replyError(conn, "bad_request", "conversationId and messageId are required")
+		return
+	}
 
-	out := outboundMessage{
-		Type:           "message",
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	result, err := ctl.markReadUC.Execute(ctx, usecase.MarkReadInput{
 		ConversationID: frame.ConversationID,
-		Message:        toPayload(*result),
+		UserID:         userID,
+		MessageID:      frame.MessageID,
+	})
+	if err != nil {
+		ctl.handleUseCaseError(conn, err)
+		return
 	}
+	if !result.Advanced {
+		return
+	}
+
+	if err := broadcastReceipt(ctx, ctl.router, ctl.listBlockedUC, result.Receipt); err != nil {
+		ctl.handleUseCaseError(conn, err)
+	}
+}
+
+// handleDelivered records a client acknowledgement for a message the server could not observe
+// being delivered, e.g. one fetched over HTTP after reconnecting.
+func (ctl *ChatSocketController) handleDelivered(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.MessageID == "" {
+		ctl.replyError(conn, "bad_request", "conversationId and messageId are required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
 
-	payload, err := json.Marshal(out)
+	receipt, err := ctl.ackDeliveryUC.Execute(ctx, usecase.AckDeliveryInput{
+		ConversationID: frame.ConversationID,
+		UserID:         userID,
+		MessageID:      frame.MessageID,
+	})
 	if err != nil {
-		ctl.replyError(conn, "internal_error", "failed to encode message")
+		ctl.handleUseCaseError(conn, err)
+		return
+	}
+
+	ctl.router.AckDelivery(realtime.Delivery{
+		ConversationID: receipt.ConversationID,
+		MessageID:      receipt.MessageID,
+		SenderID:       receipt.SenderID,
+		RecipientID:    receipt.UserID,
+		At:             receipt.At,
+	})
+}
+
+func (ctl *ChatSocketController) handleEdit(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.MessageID == "" || frame.Body == nil {
+		ctl.replyError(conn, "bad_request", "conversationId, messageId and body are required")
 		return
 	}
 
-	participants, err := ctl.listParticipants(ctx, frame.ConversationID)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	msg, err := ctl.editMessageUC.Execute(ctx, usecase.EditMessageInput{
+		ConversationID: frame.ConversationID,
+		MessageID:      frame.MessageID,
+		EditorID:       userID,
+		Body:           *frame.Body,
+	})
 	if err != nil {
 		ctl.handleUseCaseError(conn, err)
 		return
 	}
 
-	delivered := ctl.router.Broadcast(frame.ConversationID, payload, userID)
+	if err := broadcastMessageChange(ctx, ctl.router, ctl.listBlockedUC, "message_updated", *msg, userID); err != nil {
+		ctl.handleUseCaseError(conn, err)
+	}
+}
 
-	if !ctl.router.NotifyUser(userID, payload) {
-		_ = conn.Send(payload)
+func (ctl *ChatSocketController) handleDelete(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.MessageID == "" {
+		ctl.replyError(conn, "bad_request", "conversationId and messageId are required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	result, err := ctl.deleteMessageUC.Execute(ctx, usecase.DeleteMessageInput{
+		ConversationID: frame.ConversationID,
+		MessageID:      frame.MessageID,
+		ActorID:        userID,
+	})
+	if err != nil {
+		ctl.handleUseCaseError(conn, err)
+		return
+	}
+	if !result.Deleted {
+		return
 	}
 
-	ctl.forwardToPeerNodes(participants, userID, payload, delivered)
+	if err := broadcastDeletion(ctx, ctl.router, ctl.listBlockedUC, *result, userID); err != nil {
+		ctl.handleUseCaseError(conn, err)
+	}
 }
 
-func (ctl *ChatSocketController) listParticipants(ctx context.Context, conversationID string) ([]string, error) {
-	return ctl.listMembersUC.Execute(ctx, usecase.ListParticipantsInput{ConversationID: conversationID})
+// handleMute mutes the conversation until frame.Until, for frame.Duration seconds, or indefinitely.
+// Every session of the user hears about the new settings.
+func (ctl *ChatSocketController) handleMute(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" {
+		ctl.replyError(conn, "bad_request", "conversationId is required")
+		return
+	}
+	until, err := muteEnd(frame.Until, frame.Duration)
+	if err != nil {
+		ctl.replyError(conn, "bad_request", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	p, err := ctl.muteUC.Execute(ctx, usecase.MuteConversationInput{
+		ConversationID: frame.ConversationID,
+		UserID:         userID,
+		Until:          until,
+	})
+	if err != nil {
+		ctl.handleUseCaseError(conn, err)
+		return
+	}
+	notifySettings(ctl.router, userID, toNotificationSettings(*p))
+}
+
+func (ctl *ChatSocketController) handleUnmute(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" {
+		ctl.replyError(conn, "bad_request", "conversationId is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	p, err := ctl.unmuteUC.Execute(ctx, usecase.UnmuteConversationInput{
+		ConversationID: frame.ConversationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		ctl.handleUseCaseError(conn, err)
+		return
+	}
+	notifySettings(ctl.router, userID, toNotificationSettings(*p))
+}
+
+func (ctl *ChatSocketController) handleNotificationLevel(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.Level == "" {
+		ctl.replyError(conn, "bad_request", "conversationId and level are required")
+		return
+	}
+	level, err := chat.ParseNotificationLevel(frame.Level)
+	if err != nil {
+		ctl.replyError(conn, "bad_request", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	p, err := ctl.notifyLevelUC.Execute(ctx, usecase.SetNotificationLevelInput{
+		ConversationID: frame.ConversationID,
+		UserID:         userID,
+		Level:          level,
+	})
+	if err != nil {
+		ctl.handleUseCaseError(conn, err)
+		return
+	}
+	notifySettings(ctl.router, userID, toNotificationSettings(*p))
+}
+
+func (ctl *ChatSocketController) handleReact(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.MessageID == "" || frame.Emoji == "" {
+		ctl.replyError(conn, "bad_request", "conversationId, messageId and emoji are required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	result, err := ctl.addReactionUC.Execute(ctx, usecase.AddReactionInput{
+		ConversationID: frame.ConversationID,
+		MessageID:      frame.MessageID,
+		UserID:         userID,
+		Emoji:          frame.Emoji,
+	})
+	if err != nil {
+		ctl.handleUseCaseError(conn, err)
+		return
+	}
+	if !result.Added {
+		return
+	}
+
+	if err := broadcastReaction(ctx, ctl.router, ctl.listBlockedUC, "reaction_added", result.Message, result.Reaction, result.Count, result.Seq); err != nil {
+		ctl.handleUseCaseError(conn, err)
+	}
+}
+
+func (ctl *ChatSocketController) handleUnreact(c *gin.Context, conn *realtime.Connection, userID string, frame inboundFrame) {
+	if frame.ConversationID == "" || frame.MessageID == "" || frame.Emoji == "" {
+		ctl.replyError(conn, "bad_request", "conversationId, messageId and emoji are required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ctl.inflightTimeout)
+	defer cancel()
+
+	result, err := ctl.removeReactUC.Execute(ctx, usecase.RemoveReactionInput{
+		ConversationID: frame.ConversationID,
+		MessageID:      frame.MessageID,
+		UserID:         userID,
+		Emoji:          frame.Emoji,
+	})
+	if err != nil {
+		ctl.handleUseCaseError(conn, err)
+		return
+	}
+	if !result.Removed {
+		return
+	}
+
+	if err := broadcastReaction(ctx, ctl.router, ctl.listBlockedUC, "reaction_removed", result.Message, result.Reaction, result.Count, result.Seq); err != nil {
+		ctl.handleUseCaseError(conn, err)
+	}
+}
+
+// handleTyping relays typing indicators to the conversation room, or to a thread's room when messageId names
+// its root. Nothing is persisted: the router stops the indicator itself when the client goes quiet. Throttled
+// frames are dropped with a rate_limited error frame, so clients can slow down. Users the typist blocked
+// are filtered out with the router's in-memory block list, so typing never touches Postgres.
+func (ctl *ChatSocketController) handleTyping(conn *realtime.Connection, userID string, frame inboundFrame, typing bool) {
+	if frame.ConversationID == "" {
+		ctl.replyError(conn, "bad_request", "conversationId is required")
+		return
+	}
+	room := frame.ConversationID
+	if frame.MessageID != "" {
+		room = realtime.ThreadRoom(frame.ConversationID, frame.MessageID)
+	}
+
+	if !typing {
+		ctl.router.StopSignal(conn, room, "typing")
+		return
+	}
+
+	excluded, err := ctl.router.Blocked(userID)
+	if err != nil {
+		ctl.replyError(conn, "internal_error", err.Error())
+		return
+	}
+
+	start, err := json.Marshal(typingFrame{Type: "typing_start", ConversationID: frame.ConversationID, ThreadRootID: frame.MessageID, UserID: userID})
+	if err != nil {
+		return
+	}
+	stop, err := json.Marshal(typingFrame{Type: "typing_stop", ConversationID: frame.ConversationID, ThreadRootID: frame.MessageID, UserID: userID})
+	if err != nil {
+		return
+	}
+
+	err = ctl.router.StartSignal(conn, room, "typing", typingTimeout, start, stop, excluded...)
+	switch {
+	case errors.Is(err, realtime.ErrNotInRoom):
+		ctl.replyError(conn, "forbidden", "join the conversation or thread first")
+	case errors.Is(err, realtime.ErrThrottled):
+		ctl.replyError(conn, "rate_limited", err.Error())
+	}
 }
 
 func (ctl *ChatSocketController) handleUseCaseError(conn *realtime.Connection, err error) {
 	switch {
 	case errors.Is(err, usecase.ErrPersistence):
 		ctl.replyError(conn, "internal_error", "unexpected persistence error")
+	case errors.Is(err, usecase.ErrStorage):
+		ctl.replyError(conn, "internal_error", "unexpected storage error")
 	case errors.Is(err, chat.ErrNotParticipant):
 		ctl.replyError(conn, "forbidden", "user is not a participant in this conversation")
+	case errors.Is(err, chat.ErrForbidden), errors.Is(err, chat.ErrUserBlocked):
+		ctl.replyError(conn, "forbidden", err.Error())
+	case errors.Is(err, chat.ErrMessageNotFound), errors.Is(err, chat.ErrAttachmentNotFound):
+		ctl.replyError(conn, "not_found", err.Error())
+	case errors.Is(err, chat.ErrMessageDeleted), errors.Is(err, chat.ErrReactionLimit), errors.Is(err, chat.ErrInvalidThread),
+		errors.Is(err, chat.ErrAttachmentMissing), errors.Is(err, chat.ErrAttachmentInUse):
+		ctl.replyError(conn, "conflict", err.Error())
 	default:
 		ctl.replyError(conn, "bad_request", err.Error())
 	}
@@ -271,21 +841,6 @@ func (ctl *ChatSocketController) replyError(conn *realtime.Connection, code stri
 	}
 }
 
-func (ctl *ChatSocketController) forwardToPeerNodes(participants []string, senderID string, payload []byte, delivered int) {
-	expected := 0
-	for _, id := range participants {
-		if id == senderID {
-			continue
-		}
-		expected++
-	}
-	if delivered >= expected {
-		return
-	}
-	// TODO: integrate pub/sub (e.g., Redis, NATS) to deliver payload to members connected on other nodes.
-	_ = payload
-}
-
 func toPayload(msg chat.Message) messagePayload {
 	return messagePayload{
 		ID:             msg.ID,
@@ -297,5 +852,12 @@ func toPayload(msg chat.Message) messagePayload {
 		AttachmentURL:  msg.AttachmentURL,
 		AttachmentMeta: msg.AttachmentMeta,
 		DedupeKey:      msg.DedupeKey,
+		EditedAt:       msg.EditedAt,
+		DeletedAt:      msg.DeletedAt,
+		DeletedBy:      msg.DeletedBy,
+		ReplyToID:      msg.ReplyToID,
+		ThreadRootID:   msg.ThreadRootID,
+		ReplyCount:     msg.ReplyCount,
+		LastReplyAt:    msg.LastReplyAt,
 	}
 }
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	qport "go-chatty/internal/infrastructure/queue/port"
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	"go-chatty/internal/pkg/chat/application/task"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CompleteAttachmentController confirms a finished upload and starts its processing (one controller per endpoint)
+type CompleteAttachmentController struct {
+	UC    *usecase.CompleteAttachmentUseCase
+	queue qport.Client
+}
+
+func NewCompleteAttachmentController(pool *pgxpool.Pool, client qport.Client, storage storageport.Storage) *CompleteAttachmentController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &CompleteAttachmentController{UC: usecase.NewCompleteAttachmentUseCase(repo, storage), queue: client}
+}
+
+func (h *CompleteAttachmentController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		a, err := h.UC.Execute(ctx, usecase.CompleteAttachmentInput{
+			AttachmentID: c.Param("attachmentId"),
+			OwnerID:      principal.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+		if a.ProcessedAt == nil {
+			if err := task.EnqueueAttachmentProcessing(ctx, h.queue, a.ID); err != nil {
+				// Sending a message with the attachment queues the processing again
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to queue the processing"})
+				return
+			}
+		}
+		c.JSON(http.StatusAccepted, gin.H{"attachment": attachmentItem(*a)})
+	}
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateAttachmentController registers an attachment and hands out its presigned upload (one controller per endpoint)
+type CreateAttachmentController struct {
+	UC *usecase.CreateAttachmentUseCase
+}
+
+func NewCreateAttachmentController(pool *pgxpool.Pool, storage storageport.Storage, policy storageport.Policy) *CreateAttachmentController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &CreateAttachmentController{UC: usecase.NewCreateAttachmentUseCase(repo, storage, policy)}
+}
+
+type createAttachmentRequest struct {
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+}
+
+func (h *CreateAttachmentController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req createAttachmentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.FileName == "" || req.ContentType == "" || req.Size <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "fileName, contentType and a positive size are required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		result, err := h.UC.Execute(ctx, usecase.CreateAttachmentInput{
+			OwnerID:     principal.UserID,
+			FileName:    req.FileName,
+			ContentType: req.ContentType,
+			Size:        req.Size,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"attachment": attachmentItem(result.Attachment),
+			"upload":     result.Upload,
+		})
+	}
+}
+
+// attachmentItem serializes an attachment for API responses, with processing results once available.
+func attachmentItem(a chat.Attachment) gin.H {
+	item := gin.H{
+		"id":          a.ID,
+		"fileName":    a.FileName,
+		"contentType": a.ContentType,
+		"size":        a.Size,
+		"createdAt":   a.CreatedAt,
+	}
+	if a.ProcessedAt != nil {
+		item["sniffedType"] = a.SniffedType
+		item["sha256"] = a.Checksum
+		item["width"] = a.Width
+		item["height"] = a.Height
+		item["processedAt"] = a.ProcessedAt
+		item["rejected"] = a.Rejected
+	}
+	return item
+}
@@ -2,7 +2,8 @@ package controller
 
 import (
 	"context"
-	"errors"
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
 	"go-chatty/internal/pkg/chat/application/usecase"
 	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
 	"net/http"
@@ -24,10 +25,20 @@ func NewCreateChatController(pool *pgxpool.Pool) *CreateChatController {
 
 type createChatRequest struct {
 	ParticipantIDs []string `json:"participantIds"`
+	Kind           string   `json:"kind"` // "direct" or "group"; inferred from the participant count when empty
+	Title          *string  `json:"title"`
+	AvatarURL      *string  `json:"avatarUrl"`
+	HistoryHidden  bool     `json:"historyHidden"`
 }
 
 func (h *CreateChatController) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
 		var req createChatRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -38,22 +49,44 @@ func (h *CreateChatController) Handle() gin.HandlerFunc {
 			return
 		}
 
-		in := usecase.CreateChatInput{ParticipantIDs: req.ParticipantIDs}
+		in := usecase.CreateChatInput{
+			TenantID:       principal.TenantID,
+			CreatorID:      principal.UserID,
+			ParticipantIDs: req.ParticipantIDs,
+			Title:          req.Title,
+			AvatarURL:      req.AvatarURL,
+			HistoryHidden:  req.HistoryHidden,
+		}
+		if req.Kind != "" {
+			kind, err := chat.ParseConversationKind(req.Kind)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			in.Kind = &kind
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
 		defer cancel()
-		conv, err := h.UC.Execute(ctx, in)
+		out, err := h.UC.Execute(ctx, in)
 		if err != nil {
-			status := http.StatusBadRequest
-			if errors.Is(err, usecase.ErrPersistence) {
-				status = http.StatusInternalServerError
-			}
-			c.JSON(status, gin.H{"error": err.Error()})
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
 			return
 		}
 
-		c.JSON(http.StatusCreated, gin.H{
-			"id":        conv.ID,
-			"createdAt": conv.CreatedAt,
+		// An existing direct conversation is returned as-is with 200 instead of 201
+		status := http.StatusCreated
+		if !out.Created {
+			status = http.StatusOK
+		}
+		conv := out.Conversation
+		c.JSON(status, gin.H{
+			"id":            conv.ID,
+			"createdAt":     conv.CreatedAt,
+			"kind":          conv.Kind.String(),
+			"title":         conv.Title,
+			"avatarUrl":     conv.AvatarURL,
+			"historyHidden": conv.HistoryHidden,
 		})
 	}
 }
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateWebhookController subscribes an endpoint to the events of the caller's tenant (one controller per endpoint)
+type CreateWebhookController struct {
+	UC *usecase.CreateWebhookUseCase
+}
+
+func NewCreateWebhookController(pool *pgxpool.Pool) *CreateWebhookController {
+	webhooks := adapter.NewPgWebhookRepository(pool)
+	return &CreateWebhookController{UC: usecase.NewCreateWebhookUseCase(webhooks)}
+}
+
+type createWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Secret      *string  `json:"secret"` // generated when omitted
+	Events      []string `json:"events"` // every event type when empty
+	Description *string  `json:"description"`
+}
+
+func (h *CreateWebhookController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req createWebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		s, err := h.UC.Execute(ctx, usecase.CreateWebhookInput{
+			TenantID:    principal.TenantID,
+			URL:         req.URL,
+			Secret:      req.Secret,
+			Events:      req.Events,
+			Description: req.Description,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		// The secret is only ever shown here
+		item := webhookItem(*s)
+		item["secret"] = s.Secret
+		c.JSON(http.StatusCreated, item)
+	}
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DeleteMessageController handles deleting a message for everyone (one controller per endpoint)
+type DeleteMessageController struct {
+	UC            *usecase.DeleteMessageUseCase
+	listBlockedUC *usecase.ListBlockedUseCase
+	router        *realtime.Router
+}
+
+func NewDeleteMessageController(pool *pgxpool.Pool, router *realtime.Router) *DeleteMessageController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &DeleteMessageController{
+		UC:            usecase.NewDeleteMessageUseCase(repo),
+		listBlockedUC: usecase.NewListBlockedUseCase(repo),
+		router:        router,
+	}
+}
+
+func (h *DeleteMessageController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		result, err := h.UC.Execute(ctx, usecase.DeleteMessageInput{
+			ConversationID: c.Param("chatId"),
+			MessageID:      c.Param("messageId"),
+			ActorID:        principal.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		if result.Deleted {
+			// The tombstone is stored; a failed broadcast only delays peers until their next fetch
+			_ = broadcastDeletion(ctx, h.router, h.listBlockedUC, *result, principal.UserID)
+		}
+		c.JSON(http.StatusOK, gin.H{"message": toPayload(result.Message)})
+	}
+}
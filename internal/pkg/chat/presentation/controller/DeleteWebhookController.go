@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DeleteWebhookController unsubscribes a webhook of the caller's tenant (one controller per endpoint)
+type DeleteWebhookController struct {
+	UC *usecase.DeleteWebhookUseCase
+}
+
+func NewDeleteWebhookController(pool *pgxpool.Pool) *DeleteWebhookController {
+	webhooks := adapter.NewPgWebhookRepository(pool)
+	return &DeleteWebhookController{UC: usecase.NewDeleteWebhookUseCase(webhooks)}
+}
+
+func (h *DeleteWebhookController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		err := h.UC.Execute(ctx, usecase.DeleteWebhookInput{TenantID: principal.TenantID, WebhookID: c.Param("webhookId")})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
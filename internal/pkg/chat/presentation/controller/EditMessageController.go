@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EditMessageController handles editing a message by its sender (one controller per endpoint)
+type EditMessageController struct {
+	UC            *usecase.EditMessageUseCase
+	listBlockedUC *usecase.ListBlockedUseCase
+	router        *realtime.Router
+}
+
+func NewEditMessageController(pool *pgxpool.Pool, router *realtime.Router) *EditMessageController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &EditMessageController{
+		UC:            usecase.NewEditMessageUseCase(repo),
+		listBlockedUC: usecase.NewListBlockedUseCase(repo),
+		router:        router,
+	}
+}
+
+type editMessageRequest struct {
+	Body *string `json:"body"`
+}
+
+func (h *EditMessageController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req editMessageRequest
+		if err := c.ShouldBindJSON(&req); err != nil || req.Body == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "body is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		msg, err := h.UC.Execute(ctx, usecase.EditMessageInput{
+			ConversationID: c.Param("chatId"),
+			MessageID:      c.Param("messageId"),
+			EditorID:       principal.UserID,
+			Body:           *req.Body,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		// The edit is stored; a failed broadcast only delays peers until their next fetch
+		_ = broadcastMessageChange(ctx, h.router, h.listBlockedUC, "message_updated", *msg, principal.UserID)
+		c.JSON(http.StatusOK, gin.H{"message": toPayload(*msg)})
+	}
+}
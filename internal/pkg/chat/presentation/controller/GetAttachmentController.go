@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GetAttachmentController hands out a fresh presigned download of an attachment (one controller per endpoint)
+type GetAttachmentController struct {
+	UC *usecase.GetAttachmentUseCase
+}
+
+func NewGetAttachmentController(pool *pgxpool.Pool, storage storageport.Storage) *GetAttachmentController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &GetAttachmentController{UC: usecase.NewGetAttachmentUseCase(repo, storage)}
+}
+
+func (h *GetAttachmentController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		result, err := h.UC.Execute(ctx, usecase.GetAttachmentInput{
+			AttachmentID: c.Param("attachmentId"),
+			RequesterID:  principal.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		resp := gin.H{
+			"attachment": attachmentItem(result.Attachment),
+			"download":   result.Download,
+		}
+		if result.Thumbnail != nil {
+			resp["thumbnail"] = result.Thumbnail
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
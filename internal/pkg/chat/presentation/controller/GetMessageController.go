@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"time"
 
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
 	"go-chatty/internal/pkg/chat/application/usecase"
 	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
 
@@ -27,6 +29,12 @@ func NewGetMessageController(pool *pgxpool.Pool) *GetMessageController {
 
 func (h *GetMessageController) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
 		chatID := c.Param("chatId")
 		if chatID == "" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "chatId is required"})
@@ -48,41 +56,73 @@ func (h *GetMessageController) Handle() gin.HandlerFunc {
 			}
 		}
 
-		in := usecase.GetMessageInput{ConversationID: chatID, Limit: limit, Offset: offset}
+		in := usecase.GetMessageInput{
+			ConversationID: chatID,
+			RequesterID:    principal.UserID,
+			Limit:          limit,
+			Offset:         offset,
+			Before:         c.Query("before"),
+			After:          c.Query("after"),
+			Around:         c.Query("around"),
+		}
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
 		defer cancel()
 
-		msgs, err := h.UC.Execute(ctx, in)
+		page, err := h.UC.Execute(ctx, in)
 		if err != nil {
 			status := http.StatusBadRequest
-			if errors.Is(err, usecase.ErrPersistence) {
+			switch {
+			case errors.Is(err, usecase.ErrPersistence):
 				status = http.StatusInternalServerError
+			case errors.Is(err, chat.ErrNotParticipant):
+				status = http.StatusForbidden
 			}
 			c.JSON(status, gin.H{"error": err.Error()})
 			return
 		}
 
-		// Serialize messages as-is; field names kept explicit for clarity
-		out := make([]gin.H, 0, len(msgs))
-		for _, m := range msgs {
-			out = append(out, gin.H{
-				"id":             m.ID,
-				"conversationId": m.ConversationID,
-				"senderId":       m.SenderID,
-				"createdAt":      m.CreatedAt,
-				"body":           m.Body,
-				"msgType":        m.MsgType,
-				"attachmentUrl":  m.AttachmentURL,
-				"attachmentMeta": m.AttachmentMeta,
-				"dedupeKey":      m.DedupeKey,
-			})
-		}
+		out := historyItems(page.Messages, page.Reactions)
 
 		c.JSON(http.StatusOK, gin.H{
-			"messages": out,
-			"limit":    limit,
-			"offset":   offset,
-			"count":    len(out),
+			"messages":   out,
+			"limit":      limit,
+			"offset":     offset,
+			"count":      len(out),
+			"nextCursor": page.NextCursor,
+			"prevCursor": page.PrevCursor,
 		})
 	}
 }
+
+// historyItems serializes messages for history responses; field names kept explicit for clarity.
+func historyItems(msgs []chat.Message, reactions map[string][]chat.ReactionCount) []gin.H {
+	out := make([]gin.H, 0, len(msgs))
+	for _, m := range msgs {
+		out = append(out, historyItem(m, reactions))
+	}
+	return out
+}
+
+func historyItem(m chat.Message, reactions map[string][]chat.ReactionCount) gin.H {
+	return gin.H{
+		"id":             m.ID,
+		"conversationId": m.ConversationID,
+		"senderId":       m.SenderID,
+		"createdAt":      m.CreatedAt,
+		"body":           m.Body,
+		"msgType":        m.MsgType,
+		"attachmentUrl":  m.AttachmentURL,
+		"attachmentMeta": m.AttachmentMeta,
+		"dedupeKey":      m.DedupeKey,
+		"editedAt":       m.EditedAt,
+		"deletedAt":      m.DeletedAt,
+		"deletedBy":      m.DeletedBy,
+		"replyToId":      m.ReplyToID,
+		"threadRootId":   m.ThreadRootID,
+		"replyCount":     m.ReplyCount,
+		"lastReplyAt":    m.LastReplyAt,
+		"seq":            m.Seq,
+		"changeSeq":      m.ChangeSeq,
+		"reactions":      toReactionPayloads(reactions[m.ID]),
+	}
+}
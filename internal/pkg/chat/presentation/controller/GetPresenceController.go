@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GetPresenceController looks up the presence of several users at once (one controller per endpoint)
+type GetPresenceController struct {
+	UC     *usecase.FilterPresenceTargetsUseCase
+	router *realtime.Router
+}
+
+func NewGetPresenceController(pool *pgxpool.Pool, router *realtime.Router) *GetPresenceController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &GetPresenceController{UC: usecase.NewFilterPresenceTargetsUseCase(repo), router: router}
+}
+
+func (h *GetPresenceController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var userIDs []string
+		for _, id := range strings.Split(c.Query("userIds"), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				userIDs = append(userIDs, id)
+			}
+		}
+		if len(userIDs) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "userIds is required"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		targets, err := h.UC.Execute(ctx, usecase.FilterPresenceTargetsInput{
+			RequesterID: principal.UserID,
+			UserIDs:     userIDs,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		presence, err := h.router.LookupPresence(ctx, targets)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "presence lookup failed"})
+			return
+		}
+
+		// Users the caller may not see are left out rather than reported offline
+		out := make([]realtime.Presence, 0, len(targets))
+		for _, id := range targets {
+			out = append(out, presence[id])
+		}
+		c.JSON(http.StatusOK, gin.H{"presence": out})
+	}
+}
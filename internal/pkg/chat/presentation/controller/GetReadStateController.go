@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GetReadStateController returns the caller's unread count and the members' read positions (one controller per endpoint)
+type GetReadStateController struct {
+	UC *usecase.GetReadStateUseCase
+}
+
+func NewGetReadStateController(pool *pgxpool.Pool) *GetReadStateController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &GetReadStateController{UC: usecase.NewGetReadStateUseCase(repo)}
+}
+
+type readPositionResponse struct {
+	UserID            string  `json:"userId"`
+	LastReadMessageID *string `json:"lastReadMessageId"`
+}
+
+func (h *GetReadStateController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		result, err := h.UC.Execute(ctx, usecase.GetReadStateInput{
+			ConversationID: c.Param("chatId"),
+			RequesterID:    principal.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		members := make([]readPositionResponse, 0, len(result.Members))
+		for _, p := range result.Members {
+			members = append(members, readPositionResponse{UserID: p.UserID, LastReadMessageID: p.LastReadMsg})
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"conversationId": c.Param("chatId"),
+			"unreadCount":    result.UnreadCount,
+			"members":        members,
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GetThreadController pages through the replies of a thread (one controller per endpoint)
+type GetThreadController struct {
+	UC *usecase.GetThreadUseCase
+}
+
+func NewGetThreadController(pool *pgxpool.Pool) *GetThreadController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &GetThreadController{UC: usecase.NewGetThreadUseCase(repo)}
+}
+
+func (h *GetThreadController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		limit := 50
+		if v := c.Query("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				limit = n
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		thread, err := h.UC.Execute(ctx, usecase.GetThreadInput{
+			ConversationID: c.Param("chatId"),
+			RootID:         c.Param("messageId"),
+			RequesterID:    principal.UserID,
+			Limit:          limit,
+			Before:         c.Query("before"),
+			After:          c.Query("after"),
+			Around:         c.Query("around"),
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		replies := thread.Replies
+		c.JSON(http.StatusOK, gin.H{
+			"root":       historyItem(thread.Root, replies.Reactions),
+			"messages":   historyItems(replies.Messages, replies.Reactions),
+			"limit":      limit,
+			"count":      len(replies.Messages),
+			"nextCursor": replies.NextCursor,
+			"prevCursor": replies.PrevCursor,
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LeaveConversationController handles a member leaving a group (one controller per endpoint)
+type LeaveConversationController struct {
+	UC     *usecase.LeaveConversationUseCase
+	router *realtime.Router
+}
+
+func NewLeaveConversationController(pool *pgxpool.Pool, router *realtime.Router) *LeaveConversationController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &LeaveConversationController{UC: usecase.NewLeaveConversationUseCase(repo), router: router}
+}
+
+func (h *LeaveConversationController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		msg, err := h.UC.Execute(ctx, usecase.LeaveConversationInput{
+			ConversationID: c.Param("chatId"),
+			UserID:         principal.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		// Out of the rooms right away: the outbox relay only announces the change, once it gets to it
+		h.router.RemoveUser(msg.ConversationID, principal.UserID)
+
+		c.JSON(http.StatusOK, gin.H{"message": toPayload(*msg)})
+	}
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ListBlockedController lists the users blocked by the caller (one controller per endpoint)
+type ListBlockedController struct {
+	UC *usecase.ListBlockedUseCase
+}
+
+func NewListBlockedController(pool *pgxpool.Pool) *ListBlockedController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &ListBlockedController{UC: usecase.NewListBlockedUseCase(repo)}
+}
+
+type blockedUserResponse struct {
+	UserID    string    `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (h *ListBlockedController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		blocks, err := h.UC.Execute(ctx, usecase.ListBlockedInput{BlockerID: principal.UserID})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		out := make([]blockedUserResponse, 0, len(blocks))
+		for _, b := range blocks {
+			out = append(out, blockedUserResponse{UserID: b.BlockedId, CreatedAt: b.CreatedAt})
+		}
+		c.JSON(http.StatusOK, gin.H{"blocked": out})
+	}
+}
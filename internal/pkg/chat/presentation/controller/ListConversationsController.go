@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ListConversationsController returns the caller's inbox, most recently active conversations first (one controller per endpoint)
+type ListConversationsController struct {
+	UC *usecase.ListConversationsUseCase
+}
+
+func NewListConversationsController(pool *pgxpool.Pool) *ListConversationsController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &ListConversationsController{UC: usecase.NewListConversationsUseCase(repo)}
+}
+
+func (h *ListConversationsController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		in := usecase.ListConversationsInput{UserID: principal.UserID, Before: c.Query("before")}
+		if v := c.Query("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				in.Limit = n
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		result, err := h.UC.Execute(ctx, in)
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		now := time.Now().UTC()
+		items := make([]gin.H, 0, len(result.Entries))
+		for _, e := range result.Entries {
+			items = append(items, inboxItem(e, now))
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"conversations": items,
+			"count":         len(items),
+			"nextCursor":    result.NextCursor,
+		})
+	}
+}
+
+// inboxItem serializes an inbox entry; muted reflects the mute state at now, and mutedUntil is null for indefinite mutes.
+func inboxItem(e chat.InboxEntry, now time.Time) gin.H {
+	var last gin.H
+	if e.LastMessage != nil {
+		last = historyItem(*e.LastMessage, nil)
+		delete(last, "reactions")
+	}
+	return gin.H{
+		"id":                e.Conversation.ID,
+		"kind":              e.Conversation.Kind.String(),
+		"title":             e.Conversation.Title,
+		"avatarUrl":         e.Conversation.AvatarURL,
+		"createdAt":         e.Conversation.CreatedAt,
+		"lastActivityAt":    e.Conversation.LastActivityAt,
+		"lastMessage":       last,
+		"unreadCount":       e.UnreadCount,
+		"lastReadMsg":       e.Membership.LastReadMsg,
+		"role":              e.Membership.Role.String(),
+		"muted":             e.Membership.IsMutedAt(now),
+		"mutedUntil":        mutedUntil(e.Membership),
+		"notificationLevel": e.Membership.NotifyLevel.String(),
+		"participants":      e.OtherIDs,
+		"memberCount":       e.MemberCount,
+	}
+}
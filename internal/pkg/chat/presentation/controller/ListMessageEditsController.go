@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ListMessageEditsController returns the edit history of a message (one controller per endpoint)
+type ListMessageEditsController struct {
+	UC *usecase.ListMessageEditsUseCase
+}
+
+func NewListMessageEditsController(pool *pgxpool.Pool) *ListMessageEditsController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &ListMessageEditsController{UC: usecase.NewListMessageEditsUseCase(repo)}
+}
+
+type messageEditResponse struct {
+	EditorID     string    `json:"editorId"`
+	PreviousBody *string   `json:"previousBody"`
+	EditedAt     time.Time `json:"editedAt"`
+}
+
+func (h *ListMessageEditsController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		edits, err := h.UC.Execute(ctx, usecase.ListMessageEditsInput{
+			ConversationID: c.Param("chatId"),
+			MessageID:      c.Param("messageId"),
+			RequesterID:    principal.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		out := make([]messageEditResponse, 0, len(edits))
+		for _, e := range edits {
+			out = append(out, messageEditResponse{EditorID: e.EditorID, PreviousBody: e.PreviousBody, EditedAt: e.EditedAt})
+		}
+		c.JSON(http.StatusOK, gin.H{"messageId": c.Param("messageId"), "edits": out})
+	}
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ListReceiptsController returns the per-recipient receipts of a message (one controller per endpoint)
+type ListReceiptsController struct {
+	UC *usecase.ListReceiptsUseCase
+}
+
+func NewListReceiptsController(pool *pgxpool.Pool) *ListReceiptsController {
+	return &ListReceiptsController{UC: usecase.NewListReceiptsUseCase(
+		adapter.NewPgChatRepository(pool),
+		adapter.NewPgReceiptRepository(pool),
+	)}
+}
+
+type receiptResponse struct {
+	UserID string    `json:"userId"`
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+func (h *ListReceiptsController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		receipts, err := h.UC.Execute(ctx, usecase.ListReceiptsInput{
+			ConversationID: c.Param("chatId"),
+			MessageID:      c.Param("messageId"),
+			RequesterID:    principal.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		out := make([]receiptResponse, 0, len(receipts))
+		for _, rc := range receipts {
+			out = append(out, receiptResponse{UserID: rc.UserID, Status: rc.Status.String(), At: rc.At})
+		}
+		c.JSON(http.StatusOK, gin.H{"messageId": c.Param("messageId"), "receipts": out})
+	}
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSessionsController lists the caller's active websocket sessions on every node (one controller per endpoint)
+type ListSessionsController struct {
+	router *realtime.Router
+}
+
+func NewListSessionsController(router *realtime.Router) *ListSessionsController {
+	return &ListSessionsController{router: router}
+}
+
+func (h *ListSessionsController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		c.JSON(http.StatusOK, gin.H{"sessions": h.router.Sessions(ctx, principal.UserID)})
+	}
+}
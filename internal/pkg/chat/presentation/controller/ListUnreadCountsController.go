@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ListUnreadCountsController returns the caller's unread count per conversation (one controller per endpoint)
+type ListUnreadCountsController struct {
+	UC *usecase.ListUnreadCountsUseCase
+}
+
+func NewListUnreadCountsController(pool *pgxpool.Pool) *ListUnreadCountsController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &ListUnreadCountsController{UC: usecase.NewListUnreadCountsUseCase(repo)}
+}
+
+type unreadCountResponse struct {
+	ConversationID string `json:"conversationId"`
+	UnreadCount    int    `json:"unreadCount"`
+}
+
+func (h *ListUnreadCountsController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		counts, err := h.UC.Execute(ctx, usecase.ListUnreadCountsInput{UserID: principal.UserID})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		out := make([]unreadCountResponse, 0, len(counts))
+		total := 0
+		for id, n := range counts {
+			out = append(out, unreadCountResponse{ConversationID: id, UnreadCount: n})
+			total += n
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].ConversationID < out[j].ConversationID })
+		c.JSON(http.StatusOK, gin.H{"conversations": out, "total": total})
+	}
+}
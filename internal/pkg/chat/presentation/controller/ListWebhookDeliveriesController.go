@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ListWebhookDeliveriesController pages through the deliveries of a webhook, by default its dead letters
+// (one controller per endpoint)
+type ListWebhookDeliveriesController struct {
+	UC *usecase.ListWebhookDeliveriesUseCase
+}
+
+func NewListWebhookDeliveriesController(pool *pgxpool.Pool) *ListWebhookDeliveriesController {
+	webhooks := adapter.NewPgWebhookRepository(pool)
+	return &ListWebhookDeliveriesController{UC: usecase.NewListWebhookDeliveriesUseCase(webhooks)}
+}
+
+func (h *ListWebhookDeliveriesController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		in := usecase.ListWebhookDeliveriesInput{
+			TenantID:  principal.TenantID,
+			WebhookID: c.Param("webhookId"),
+			Before:    c.Query("before"),
+		}
+		// "all" lists every delivery
+		if v := c.DefaultQuery("status", "dead"); v != "all" {
+			status, err := chat.ParseDeliveryStatus(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			in.Status = &status
+		}
+		if v := c.Query("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				in.Limit = n
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		out, err := h.UC.Execute(ctx, in)
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		items := make([]gin.H, 0, len(out.Deliveries))
+		for _, d := range out.Deliveries {
+			items = append(items, deliveryItem(d))
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"deliveries": items,
+			"count":      len(items),
+			"nextCursor": out.NextCursor,
+		})
+	}
+}
+
+// deliveryItem renders a delivery with the event it carries.
+func deliveryItem(d chat.WebhookDelivery) gin.H {
+	return gin.H{
+		"id":             d.ID,
+		"webhookId":      d.SubscriptionID,
+		"eventId":        d.EventID,
+		"eventType":      d.EventType,
+		"event":          json.RawMessage(d.Payload),
+		"status":         d.Status.String(),
+		"attempts":       d.Attempts,
+		"lastStatusCode": d.LastStatusCode,
+		"lastError":      d.LastError,
+		"nextAttemptAt":  d.NextAttemptAt,
+		"createdAt":      d.CreatedAt,
+		"updatedAt":      d.UpdatedAt,
+		"deliveredAt":    d.DeliveredAt,
+	}
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ListWebhooksController lists the webhook subscriptions of the caller's tenant (one controller per endpoint)
+type ListWebhooksController struct {
+	UC *usecase.ListWebhooksUseCase
+}
+
+func NewListWebhooksController(pool *pgxpool.Pool) *ListWebhooksController {
+	webhooks := adapter.NewPgWebhookRepository(pool)
+	return &ListWebhooksController{UC: usecase.NewListWebhooksUseCase(webhooks)}
+}
+
+func (h *ListWebhooksController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		subs, err := h.UC.Execute(ctx, usecase.ListWebhooksInput{TenantID: principal.TenantID})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		items := make([]gin.H, 0, len(subs))
+		for _, s := range subs {
+			items = append(items, webhookItem(s))
+		}
+		c.JSON(http.StatusOK, gin.H{"webhooks": items, "count": len(items)})
+	}
+}
+
+// webhookItem renders a subscription without its secret.
+func webhookItem(s chat.WebhookSubscription) gin.H {
+	events := make([]string, 0, len(s.Events))
+	for _, e := range s.Events {
+		events = append(events, string(e))
+	}
+	return gin.H{
+		"id":          s.ID,
+		"url":         s.URL,
+		"events":      events,
+		"description": s.Description,
+		"createdAt":   s.CreatedAt,
+	}
+}
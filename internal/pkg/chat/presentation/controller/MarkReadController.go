@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MarkReadController handles advancing the caller's read watermark (one controller per endpoint)
+type MarkReadController struct {
+	UC            *usecase.MarkReadUseCase
+	listBlockedUC *usecase.ListBlockedUseCase
+	router        *realtime.Router
+}
+
+func NewMarkReadController(pool *pgxpool.Pool, router *realtime.Router) *MarkReadController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &MarkReadController{
+		UC:            usecase.NewMarkReadUseCase(repo),
+		listBlockedUC: usecase.NewListBlockedUseCase(repo),
+		router:        router,
+	}
+}
+
+type markReadRequest struct {
+	MessageID string `json:"messageId"`
+}
+
+func (h *MarkReadController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req markReadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		result, err := h.UC.Execute(ctx, usecase.MarkReadInput{
+			ConversationID: c.Param("chatId"),
+			UserID:         principal.UserID,
+			MessageID:      req.MessageID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		if result.Advanced {
+			// The watermark is already stored; a failed broadcast only delays peers until their next fetch
+			_ = broadcastReceipt(ctx, h.router, h.listBlockedUC, result.Receipt)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"conversationId": result.Receipt.ConversationID,
+			"messageId":      result.Receipt.MessageID,
+			"advanced":       result.Advanced,
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MuteConversationController handles muting a conversation for the caller (one controller per endpoint)
+type MuteConversationController struct {
+	UC     *usecase.MuteConversationUseCase
+	router *realtime.Router
+}
+
+func NewMuteConversationController(pool *pgxpool.Pool, router *realtime.Router) *MuteConversationController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &MuteConversationController{UC: usecase.NewMuteConversationUseCase(repo), router: router}
+}
+
+// muteRequest sets an end to the mute, as a time or a duration; an empty body mutes indefinitely.
+type muteRequest struct {
+	Until           *time.Time `json:"until"`
+	DurationSeconds *int64     `json:"durationSeconds"`
+}
+
+func (h *MuteConversationController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req muteRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		until, err := muteEnd(req.Until, req.DurationSeconds)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		p, err := h.UC.Execute(ctx, usecase.MuteConversationInput{
+			ConversationID: c.Param("chatId"),
+			UserID:         principal.UserID,
+			Until:          until,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		settings := toNotificationSettings(*p)
+		notifySettings(h.router, p.UserID, settings)
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+// muteEnd resolves the end of a mute given either as a time or as a duration from now; nil means indefinitely.
+func muteEnd(until *time.Time, durationSeconds *int64) (*time.Time, error) {
+	switch {
+	case until != nil && durationSeconds != nil:
+		return nil, fmt.Errorf("until and durationSeconds are mutually exclusive")
+	case durationSeconds != nil:
+		if *durationSeconds <= 0 {
+			return nil, fmt.Errorf("durationSeconds must be positive")
+		}
+		t := time.Now().UTC().Add(time.Duration(min(*durationSeconds, maxMuteSeconds)) * time.Second)
+		return &t, nil
+	default:
+		return until, nil
+	}
+}
+
+// maxMuteSeconds keeps timed mutes clear of time.Duration overflow; longer ones might as well be indefinite.
+const maxMuteSeconds = 100 * 365 * 24 * 60 * 60
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RegisterDeviceController records a push token of the caller's device (one controller per endpoint)
+type RegisterDeviceController struct {
+	UC *usecase.RegisterDeviceUseCase
+}
+
+func NewRegisterDeviceController(pool *pgxpool.Pool) *RegisterDeviceController {
+	devices := adapter.NewPgDeviceRepository(pool)
+	return &RegisterDeviceController{UC: usecase.NewRegisterDeviceUseCase(devices)}
+}
+
+type registerDeviceRequest struct {
+	Token    string  `json:"token" binding:"required"`
+	Platform string  `json:"platform" binding:"required"` // "fcm" or "apns"
+	DeviceID *string `json:"deviceId"`                    // defaults to the X-Device-ID header
+}
+
+func (h *RegisterDeviceController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req registerDeviceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		platform, err := chat.ParseDevicePlatform(req.Platform)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.DeviceID == nil {
+			if v := c.GetHeader("X-Device-ID"); v != "" {
+				req.DeviceID = &v
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		d, err := h.UC.Execute(ctx, usecase.RegisterDeviceInput{
+			UserID:   principal.UserID,
+			Token:    req.Token,
+			Platform: platform,
+			DeviceID: req.DeviceID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"token":     d.Token,
+			"platform":  d.Platform.String(),
+			"deviceId":  d.DeviceID,
+			"updatedAt": d.UpdatedAt,
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RemoveParticipantController handles removing a member from a group (one controller per endpoint)
+type RemoveParticipantController struct {
+	UC     *usecase.RemoveParticipantUseCase
+	router *realtime.Router
+}
+
+func NewRemoveParticipantController(pool *pgxpool.Pool, router *realtime.Router) *RemoveParticipantController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &RemoveParticipantController{UC: usecase.NewRemoveParticipantUseCase(repo), router: router}
+}
+
+func (h *RemoveParticipantController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		userID := c.Param("userId")
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		msg, err := h.UC.Execute(ctx, usecase.RemoveParticipantInput{
+			ConversationID: c.Param("chatId"),
+			ActorID:        principal.UserID,
+			UserID:         userID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		// Out of the rooms right away: the outbox relay only announces the change, once it gets to it
+		h.router.RemoveUser(msg.ConversationID, userID)
+
+		c.JSON(http.StatusOK, gin.H{"message": toPayload(*msg)})
+	}
+}
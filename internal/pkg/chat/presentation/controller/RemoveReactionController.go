@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RemoveReactionController handles taking back one's own emoji reaction (one controller per endpoint)
+type RemoveReactionController struct {
+	UC            *usecase.RemoveReactionUseCase
+	listBlockedUC *usecase.ListBlockedUseCase
+	router        *realtime.Router
+}
+
+func NewRemoveReactionController(pool *pgxpool.Pool, router *realtime.Router) *RemoveReactionController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &RemoveReactionController{
+		UC:            usecase.NewRemoveReactionUseCase(repo),
+		listBlockedUC: usecase.NewListBlockedUseCase(repo),
+		router:        router,
+	}
+}
+
+func (h *RemoveReactionController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		// gin already unescapes path parameters, so percent-encoded emoji arrive decoded
+		result, err := h.UC.Execute(ctx, usecase.RemoveReactionInput{
+			ConversationID: c.Param("chatId"),
+			MessageID:      c.Param("messageId"),
+			UserID:         principal.UserID,
+			Emoji:          c.Param("emoji"),
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		if result.Removed {
+			// The removal is stored; a failed broadcast only delays peers until their next fetch
+			_ = broadcastReaction(ctx, h.router, h.listBlockedUC, "reaction_removed", result.Message, result.Reaction, result.Count, result.Seq)
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	qport "go-chatty/internal/infrastructure/queue/port"
+	"go-chatty/internal/pkg/chat/application/task"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReplayWebhookDeliveryController sends a dead or delivered webhook delivery again (one controller per endpoint)
+type ReplayWebhookDeliveryController struct {
+	UC    *usecase.ReplayWebhookDeliveryUseCase
+	queue qport.Client
+}
+
+func NewReplayWebhookDeliveryController(pool *pgxpool.Pool, client qport.Client) *ReplayWebhookDeliveryController {
+	webhooks := adapter.NewPgWebhookRepository(pool)
+	return &ReplayWebhookDeliveryController{UC: usecase.NewReplayWebhookDeliveryUseCase(webhooks), queue: client}
+}
+
+func (h *ReplayWebhookDeliveryController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		d, err := h.UC.Execute(ctx, usecase.ReplayWebhookDeliveryInput{
+			TenantID:   principal.TenantID,
+			WebhookID:  c.Param("webhookId"),
+			DeliveryID: c.Param("deliveryId"),
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+		if err := task.EnqueueWebhookDelivery(ctx, h.queue, *d); err != nil {
+			// The delivery is pending again; once overdue it can be replayed anew
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to queue the delivery"})
+			return
+		}
+		c.JSON(http.StatusAccepted, deliveryItem(*d))
+	}
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevokeSessionController closes one of the caller's websocket sessions (one controller per endpoint)
+type RevokeSessionController struct {
+	router *realtime.Router
+}
+
+func NewRevokeSessionController(router *realtime.Router) *RevokeSessionController {
+	return &RevokeSessionController{router: router}
+}
+
+func (h *RevokeSessionController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		// Only sessions of the caller can be revoked; unknown ids are reported as missing
+		sessionID := c.Param("sessionId")
+		found := false
+		for _, s := range h.router.Sessions(ctx, principal.UserID) {
+			if s.ID == sessionID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+
+		h.router.RevokeSession(principal.UserID, sessionID)
+		c.Status(http.StatusNoContent)
+	}
+}
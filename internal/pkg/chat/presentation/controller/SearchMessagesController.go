@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SearchMessagesController runs full-text searches over the caller's conversations (one controller per endpoint)
+type SearchMessagesController struct {
+	UC *usecase.SearchMessagesUseCase
+}
+
+func NewSearchMessagesController(pool *pgxpool.Pool) *SearchMessagesController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &SearchMessagesController{UC: usecase.NewSearchMessagesUseCase(repo)}
+}
+
+// snippetMarkup turns the highlight markers of a snippet into <mark> elements once the text itself is escaped.
+var snippetMarkup = strings.NewReplacer(chat.HighlightStart, "<mark>", chat.HighlightStop, "</mark>")
+
+func (h *SearchMessagesController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		in := usecase.SearchMessagesInput{
+			RequesterID:    principal.UserID,
+			Query:          c.Query("q"),
+			ConversationID: c.Query("conversationId"),
+			SenderID:       c.Query("senderId"),
+			Before:         c.Query("before"),
+		}
+		if v := c.Query("limit"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				in.Limit = n
+			}
+		}
+		if v := c.Query("msgType"); v != "" {
+			n, err := strconv.ParseInt(v, 10, 16)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "msgType must be a number"})
+				return
+			}
+			msgType := chat.MessageType(n)
+			in.MsgType = &msgType
+		}
+		var err error
+		if in.From, err = timeQuery(c, "from"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if in.To, err = timeQuery(c, "to"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		result, err := h.UC.Execute(ctx, in)
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		items := make([]gin.H, 0, len(result.Hits))
+		for _, hit := range result.Hits {
+			msg := historyItem(hit.Message, nil)
+			delete(msg, "reactions")
+			items = append(items, gin.H{
+				"message": msg,
+				"snippet": snippetMarkup.Replace(html.EscapeString(hit.Snippet)),
+			})
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"results":    items,
+			"count":      len(items),
+			"nextCursor": result.NextCursor,
+		})
+	}
+}
+
+// timeQuery parses the optional RFC 3339 timestamp in query parameter name.
+func timeQuery(c *gin.Context, name string) (*time.Time, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+	}
+	t = t.UTC()
+	return &t, nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"go-chatty/internal/infrastructure/auth"
 	queueport "go-chatty/internal/infrastructure/queue/port"
 
 	"github.com/gin-gonic/gin"
@@ -24,18 +25,25 @@ func NewSendMessageController(pool *pgxpool.Pool, client queueport.Client) *Send
 }
 
 // sendMessageRequest is the DTO for the HTTP request body
+// The sender is always the authenticated principal, never a body field.
 type sendMessageRequest struct {
-	SenderID       string  `json:"senderId" binding:"required"`
-	Body           *string `json:"body"`
-	MsgType        *int16  `json:"msgType"`
-	AttachmentURL  *string `json:"attachmentUrl"`
-	AttachmentMeta *string `json:"attachmentMeta"`
-	DedupeKey      *string `json:"dedupeKey"`
+	Body         *string `json:"body"`
+	MsgType      *int16  `json:"msgType"`
+	AttachmentID *string `json:"attachmentId"`
+	DedupeKey    *string `json:"dedupeKey"`
+	ReplyToID    *string `json:"replyToId"`
+	ThreadRootID *string `json:"threadRootId"`
 }
 
 // Handle returns a gin handler that enqueues a background task to send a message
 func (h *SendMessageController) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
 		chatID := c.Param("chatId")
 		if chatID == "" {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "chatId is required"})
@@ -55,12 +63,13 @@ func (h *SendMessageController) Handle() gin.HandlerFunc {
 
 		payload := task.SendMessageTaskPayload{
 			ConversationID: chatID,
-			SenderID:       req.SenderID,
+			SenderID:       principal.UserID,
 			Body:           req.Body,
 			MsgType:        msgType,
-			AttachmentURL:  req.AttachmentURL,
-			AttachmentMeta: req.AttachmentMeta,
+			AttachmentID:   req.AttachmentID,
 			DedupeKey:      req.DedupeKey,
+			ReplyToID:      req.ReplyToID,
+			ThreadRootID:   req.ThreadRootID,
 		}
 		b, err := json.Marshal(payload)
 		if err != nil {
@@ -83,7 +92,7 @@ func (h *SendMessageController) Handle() gin.HandlerFunc {
 			"status":   "queued",
 			"taskId":   id,
 			"chatId":   chatID,
-			"senderId": req.SenderID,
+			"senderId": principal.UserID,
 		})
 	}
 }
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SetNotificationLevelController handles the caller's notification level in a conversation (one controller per endpoint)
+type SetNotificationLevelController struct {
+	UC     *usecase.SetNotificationLevelUseCase
+	router *realtime.Router
+}
+
+func NewSetNotificationLevelController(pool *pgxpool.Pool, router *realtime.Router) *SetNotificationLevelController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &SetNotificationLevelController{UC: usecase.NewSetNotificationLevelUseCase(repo), router: router}
+}
+
+type setNotificationLevelRequest struct {
+	Level string `json:"level" binding:"required"` // "all", "mentions" or "none"
+}
+
+func (h *SetNotificationLevelController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req setNotificationLevelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		level, err := chat.ParseNotificationLevel(req.Level)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		p, err := h.UC.Execute(ctx, usecase.SetNotificationLevelInput{
+			ConversationID: c.Param("chatId"),
+			UserID:         principal.UserID,
+			Level:          level,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		settings := toNotificationSettings(*p)
+		notifySettings(h.router, p.UserID, settings)
+		c.JSON(http.StatusOK, settings)
+	}
+}
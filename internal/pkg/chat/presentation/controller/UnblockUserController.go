@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UnblockUserController handles lifting a block (one controller per endpoint)
+type UnblockUserController struct {
+	UC     *usecase.UnblockUserUseCase
+	router *realtime.Router
+}
+
+func NewUnblockUserController(pool *pgxpool.Pool, router *realtime.Router) *UnblockUserController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &UnblockUserController{UC: usecase.NewUnblockUserUseCase(repo), router: router}
+}
+
+func (h *UnblockUserController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		blockedID := c.Param("userId")
+		err := h.UC.Execute(ctx, usecase.UnblockUserInput{
+			BlockerID: principal.UserID,
+			BlockedID: blockedID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		// Open sessions filter ephemeral frames with in-memory block lists; keep them current
+		h.router.UpdateBlocked(principal.UserID, blockedID, false)
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/infrastructure/realtime"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UnmuteConversationController handles lifting the caller's mute of a conversation (one controller per endpoint)
+type UnmuteConversationController struct {
+	UC     *usecase.UnmuteConversationUseCase
+	router *realtime.Router
+}
+
+func NewUnmuteConversationController(pool *pgxpool.Pool, router *realtime.Router) *UnmuteConversationController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &UnmuteConversationController{UC: usecase.NewUnmuteConversationUseCase(repo), router: router}
+}
+
+func (h *UnmuteConversationController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		p, err := h.UC.Execute(ctx, usecase.UnmuteConversationInput{
+			ConversationID: c.Param("chatId"),
+			UserID:         principal.UserID,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		settings := toNotificationSettings(*p)
+		notifySettings(h.router, p.UserID, settings)
+		c.JSON(http.StatusOK, settings)
+	}
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UnregisterDeviceController stops pushes to one of the caller's devices, e.g. on logout (one controller per endpoint)
+type UnregisterDeviceController struct {
+	UC *usecase.UnregisterDeviceUseCase
+}
+
+func NewUnregisterDeviceController(pool *pgxpool.Pool) *UnregisterDeviceController {
+	devices := adapter.NewPgDeviceRepository(pool)
+	return &UnregisterDeviceController{UC: usecase.NewUnregisterDeviceUseCase(devices)}
+}
+
+func (h *UnregisterDeviceController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		err := h.UC.Execute(ctx, usecase.UnregisterDeviceInput{UserID: principal.UserID, Token: c.Param("token")})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go-chatty/internal/infrastructure/auth"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	"go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UpdateConversationController handles renaming a group or changing its avatar (one controller per endpoint)
+type UpdateConversationController struct {
+	UC *usecase.UpdateConversationUseCase
+}
+
+func NewUpdateConversationController(pool *pgxpool.Pool) *UpdateConversationController {
+	repo := adapter.NewPgChatRepository(pool)
+	return &UpdateConversationController{UC: usecase.NewUpdateConversationUseCase(repo)}
+}
+
+type updateConversationRequest struct {
+	Title     *string `json:"title"`
+	AvatarURL *string `json:"avatarUrl"`
+}
+
+func (h *UpdateConversationController) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+
+		var req updateConversationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
+		defer cancel()
+
+		msg, err := h.UC.Execute(ctx, usecase.UpdateConversationInput{
+			ConversationID: c.Param("chatId"),
+			ActorID:        principal.UserID,
+			Title:          req.Title,
+			AvatarURL:      req.AvatarURL,
+		})
+		if err != nil {
+			c.JSON(statusForError(err), gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": toPayload(*msg)})
+	}
+}
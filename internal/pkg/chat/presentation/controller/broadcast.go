@@ -0,0 +1,298 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-chatty/internal/infrastructure/realtime"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/task"
+	"go-chatty/internal/pkg/chat/application/usecase"
+	repoAdapter "go-chatty/internal/pkg/chat/persistence/repository/adapter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MessageBroadcaster announces new messages, published by the outbox relay, and message changes made outside
+// of a request, e.g. by queue tasks, the way the controllers announce their own: to the room of the message
+// and to every session of its sender.
+type MessageBroadcaster struct {
+	router        *realtime.Router
+	listBlockedUC *usecase.ListBlockedUseCase
+}
+
+func NewMessageBroadcaster(pool *pgxpool.Pool, router *realtime.Router) *MessageBroadcaster {
+	repo := repoAdapter.NewPgChatRepository(pool)
+	return &MessageBroadcaster{router: router, listBlockedUC: usecase.NewListBlockedUseCase(repo)}
+}
+
+// Ensure interface compliance at compile time
+var (
+	_ task.MessagePublisher      = (*MessageBroadcaster)(nil)
+	_ task.MessageUpdateNotifier = (*MessageBroadcaster)(nil)
+)
+
+// MessageCreated pushes a "message" frame carrying the new msg. Users blocked by the sender never receive
+// it. System messages go through broadcastMembershipChange.
+func (b *MessageBroadcaster) MessageCreated(ctx context.Context, msg chat.Message, threadRoot *chat.Message) error {
+	if msg.MsgType == chat.MessageTypeSystem {
+		broadcastMembershipChange(b.router, msg)
+		return nil
+	}
+	payload, err := encodeMessageFrame(msg)
+	if err != nil {
+		return err
+	}
+	excluded, err := blockedRecipients(ctx, b.listBlockedUC, msg.SenderID)
+	if err != nil {
+		return err
+	}
+
+	// Router fans out to members on this node and relays to peer nodes through the cluster bus;
+	// every node records a delivered receipt for the recipients it reached. Thread replies only
+	// reach the thread's followers, while the conversation hears about the root's new counters.
+	if threadRoot != nil {
+		b.router.BroadcastThreadMessage(msg.ConversationID, threadRoot.ID, msg.ID, msg.SenderID, payload, append(excluded, msg.SenderID)...)
+		broadcastThreadUpdate(b.router, *threadRoot, excluded)
+	} else {
+		b.router.BroadcastMessage(msg.ConversationID, msg.ID, msg.SenderID, payload, append(excluded, msg.SenderID)...)
+	}
+	b.router.NotifyUser(msg.SenderID, payload)
+	return nil
+}
+
+// MessageUpdated pushes a "message_updated" frame carrying msg.
+func (b *MessageBroadcaster) MessageUpdated(ctx context.Context, msg chat.Message) error {
+	return broadcastMessageChange(ctx, b.router, b.listBlockedUC, "message_updated", msg, msg.SenderID)
+}
+
+// encodeMessageFrame wraps a persisted message in the websocket "message" frame.
+func encodeMessageFrame(msg chat.Message) ([]byte, error) {
+	return encodeMessageChange("message", msg)
+}
+
+// encodeMessageChange wraps msg in a frame of frameType stamped with the sequence number of msg's latest change.
+func encodeMessageChange(frameType string, msg chat.Message) ([]byte, error) {
+	return json.Marshal(outboundMessage{
+		Type:           frameType,
+		ConversationID: msg.ConversationID,
+		Seq:            msg.ChangeSeq,
+		Message:        toPayload(msg),
+	})
+}
+
+// encodeReplayChange wraps a change replayed to a client that has seen the conversation up to afterSeq. Read
+// watermarks come as "read"; messages it never saw come as "message" (tombstones as "message_deleted"), others
+// as "message_updated", each with its current reactions.
+func encodeReplayChange(change usecase.ReplayChange, afterSeq int64, reactions map[string][]chat.ReactionCount) ([]byte, error) {
+	if change.Read != nil {
+		return encodeReadFrame(*change.Read)
+	}
+	msg := *change.Message
+	frameType := "message_updated"
+	switch {
+	case msg.IsDeleted():
+		frameType = "message_deleted"
+	case msg.Seq > afterSeq:
+		frameType = "message"
+	}
+	return json.Marshal(replayedMessage{
+		outboundMessage: outboundMessage{
+			Type:           frameType,
+			ConversationID: msg.ConversationID,
+			Seq:            msg.ChangeSeq,
+			Message:        toPayload(msg),
+		},
+		Reactions: toReactionPayloads(reactions[msg.ID]),
+	})
+}
+
+// messageRoom is the realtime room that hears about msg: its thread's room for thread replies,
+// the conversation room otherwise.
+func messageRoom(msg chat.Message) string {
+	if msg.ThreadRootID != nil {
+		return realtime.ThreadRoom(msg.ConversationID, *msg.ThreadRootID)
+	}
+	return msg.ConversationID
+}
+
+// broadcastMessageChange pushes a "message_updated" or "message_deleted" frame carrying msg to the
+// room of msg and to every session of the actor. Users blocked by the message's sender never
+// received the message, so they do not hear about its changes either.
+func broadcastMessageChange(ctx context.Context, router *realtime.Router, uc *usecase.ListBlockedUseCase, frameType string, msg chat.Message, actorID string) error {
+	payload, err := encodeMessageChange(frameType, msg)
+	if err != nil {
+		return err
+	}
+	excluded, err := blockedRecipients(ctx, uc, msg.SenderID)
+	if err != nil {
+		return err
+	}
+	router.Broadcast(messageRoom(msg), payload, append(excluded, actorID)...)
+	router.NotifyUser(actorID, payload)
+	return nil
+}
+
+// broadcastDeletion pushes a "message_deleted" frame carrying the tombstone of a deletion made by actorID, followed,
+// for thread replies, by a "thread_updated" frame carrying the root's new counters.
+func broadcastDeletion(ctx context.Context, router *realtime.Router, uc *usecase.ListBlockedUseCase, out usecase.DeleteMessageOutput, actorID string) error {
+	if err := broadcastMessageChange(ctx, router, uc, "message_deleted", out.Message, actorID); err != nil {
+		return err
+	}
+	if out.ThreadRoot == nil {
+		return nil
+	}
+	excluded, err := blockedRecipients(ctx, uc, out.Message.SenderID)
+	if err != nil {
+		return err
+	}
+	broadcastThreadUpdate(router, *out.ThreadRoot, excluded)
+	return nil
+}
+
+// broadcastThreadUpdate pushes a "thread_updated" frame carrying the thread root, with its new reply count and
+// last-reply time, to the conversation room. excluded are the users blocked by the author of the new or deleted reply.
+func broadcastThreadUpdate(router *realtime.Router, root chat.Message, excluded []string) {
+	payload, err := encodeMessageChange("thread_updated", root)
+	if err != nil {
+		return
+	}
+	router.Broadcast(root.ConversationID, payload, excluded...)
+}
+
+// encodeReadFrame wraps a read receipt in the websocket "read" frame.
+func encodeReadFrame(r chat.ReadReceipt) ([]byte, error) {
+	return json.Marshal(readEventFrame{
+		Type:           "read",
+		ConversationID: r.ConversationID,
+		Seq:            r.Seq,
+		UserID:         r.UserID,
+		MessageID:      r.MessageID,
+		At:             r.At,
+	})
+}
+
+// blockedRecipients lists the users who must not receive realtime events caused by actorID,
+// i.e. everyone actorID has blocked.
+func blockedRecipients(ctx context.Context, uc *usecase.ListBlockedUseCase, actorID string) ([]string, error) {
+	blocks, err := uc.Execute(ctx, usecase.ListBlockedInput{BlockerID: actorID})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		ids = append(ids, b.BlockedId)
+	}
+	return ids, nil
+}
+
+// broadcastReceipt pushes a read frame to the conversation room, hiding it from users the reader blocked.
+func broadcastReceipt(ctx context.Context, router *realtime.Router, uc *usecase.ListBlockedUseCase, r chat.ReadReceipt) error {
+	payload, err := encodeReadFrame(r)
+	if err != nil {
+		return err
+	}
+	excluded, err := blockedRecipients(ctx, uc, r.UserID)
+	if err != nil {
+		return err
+	}
+	router.Broadcast(r.ConversationID, payload, excluded...)
+	return nil
+}
+
+// broadcastReaction pushes a "reaction_added" or "reaction_removed" frame about msg to the room of msg and to
+// every session of the reactor; count is the number of users left reacting with the emoji and seq the number
+// of the change. Users blocked by the reactor do not hear about it.
+func broadcastReaction(ctx context.Context, router *realtime.Router, uc *usecase.ListBlockedUseCase, frameType string, msg chat.Message, r chat.Reaction, count int, seq int64) error {
+	at := r.CreatedAt
+	if at.IsZero() {
+		// Removals carry no timestamp of their own
+		at = time.Now().UTC()
+	}
+	payload, err := json.Marshal(reactionEventFrame{
+		Type:           frameType,
+		ConversationID: msg.ConversationID,
+		Seq:            seq,
+		MessageID:      r.MessageID,
+		UserID:         r.UserID,
+		Emoji:          r.Emoji,
+		Count:          count,
+		At:             at,
+	})
+	if err != nil {
+		return err
+	}
+	excluded, err := blockedRecipients(ctx, uc, r.UserID)
+	if err != nil {
+		return err
+	}
+	router.Broadcast(messageRoom(msg), payload, append(excluded, r.UserID)...)
+	router.NotifyUser(r.UserID, payload)
+	return nil
+}
+
+// toReactionPayloads converts aggregated reaction counts; it never returns nil so clients always get a list.
+func toReactionPayloads(counts []chat.ReactionCount) []reactionPayload {
+	out := make([]reactionPayload, 0, len(counts))
+	for _, rc := range counts {
+		out = append(out, reactionPayload{Emoji: rc.Emoji, Count: rc.Count, Reacted: rc.Reacted})
+	}
+	return out
+}
+
+// broadcastMembershipChange pushes a system message to the conversation room.
+// The user affected by a membership change is notified directly because they may not have joined
+// the room (e.g., they were just added) or were already dropped from it (they left or were removed).
+func broadcastMembershipChange(router *realtime.Router, msg chat.Message) {
+	payload, err := encodeMessageFrame(msg)
+	if err != nil {
+		return
+	}
+	e, _ := chat.SystemEventOf(msg)
+	router.Broadcast(msg.ConversationID, payload, e.UserID)
+	if e.UserID == "" {
+		return
+	}
+	router.NotifyUser(e.UserID, payload)
+}
+
+// notificationSettings is a participant's notification state in a conversation. MutedUntil is null while
+// unmuted and when muted indefinitely.
+type notificationSettings struct {
+	ConversationID string     `json:"conversationId"`
+	Muted          bool       `json:"muted"`
+	MutedUntil     *time.Time `json:"mutedUntil"`
+	Level          string     `json:"level"`
+}
+
+type notificationSettingsFrame struct {
+	Type string `json:"type"`
+	notificationSettings
+}
+
+func toNotificationSettings(p chat.Participant) notificationSettings {
+	return notificationSettings{
+		ConversationID: p.ConversationID,
+		Muted:          p.IsMutedAt(time.Now()),
+		MutedUntil:     mutedUntil(p),
+		Level:          p.NotifyLevel.String(),
+	}
+}
+
+// mutedUntil is the end of p's mute as exposed by the API: nil when unmuted or muted indefinitely.
+func mutedUntil(p chat.Participant) *time.Time {
+	if p.IsMutedIndefinitely() {
+		return nil
+	}
+	return p.MutedUntil
+}
+
+// notifySettings pushes a "notification_settings" frame to every session of userID so their other devices follow.
+func notifySettings(router *realtime.Router, userID string, settings notificationSettings) {
+	payload, err := json.Marshal(notificationSettingsFrame{Type: "notification_settings", notificationSettings: settings})
+	if err != nil {
+		return
+	}
+	router.NotifyUser(userID, payload)
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	storageport "go-chatty/internal/infrastructure/storage/port"
+	chat "go-chatty/internal/pkg/chat/application/domain"
+	"go-chatty/internal/pkg/chat/application/usecase"
+)
+
+// statusForError maps use case and domain errors to HTTP status codes.
+func statusForError(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrPersistence), errors.Is(err, usecase.ErrStorage):
+		return http.StatusInternalServerError
+	case errors.Is(err, chat.ErrNotParticipant), errors.Is(err, chat.ErrForbidden), errors.Is(err, chat.ErrUserBlocked):
+		return http.StatusForbidden
+	case errors.Is(err, chat.ErrMessageNotFound), errors.Is(err, chat.ErrAttachmentNotFound), errors.Is(err, chat.ErrDeviceNotFound),
+		errors.Is(err, chat.ErrWebhookNotFound), errors.Is(err, chat.ErrDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, chat.ErrNotGroup), errors.Is(err, chat.ErrAlreadyParticipant), errors.Is(err, chat.ErrLastOwner),
+		errors.Is(err, chat.ErrMessageDeleted), errors.Is(err, chat.ErrReactionLimit), errors.Is(err, chat.ErrInvalidThread),
+		errors.Is(err, chat.ErrAttachmentMissing), errors.Is(err, chat.ErrAttachmentInUse), errors.Is(err, chat.ErrDeliveryPending):
+		return http.StatusConflict
+	case errors.Is(err, storageport.ErrNotAllowed), errors.Is(err, chat.ErrAttachmentRejected):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusBadRequest
+	}
+}
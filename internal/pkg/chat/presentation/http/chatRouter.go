@@ -1,8 +1,10 @@
 package http
 
 import (
+	"go-chatty/internal/infrastructure/auth"
 	qport "go-chatty/internal/infrastructure/queue/port"
 	"go-chatty/internal/infrastructure/realtime"
+	storageport "go-chatty/internal/infrastructure/storage/port"
 	"go-chatty/internal/pkg/chat/presentation/controller"
 
 	"github.com/gin-gonic/gin"
@@ -11,11 +13,49 @@ import (
 
 // RegisterRoutes registers chat-related HTTP endpoints under the given router group
 // It constructs per-endpoint controllers and binds them directly to routes.
-func RegisterRoutes(g *gin.RouterGroup, pool *pgxpool.Pool, client qport.Client, router *realtime.Router) {
+// Tenant settings such as webhooks are reserved to the principals isAdmin accepts.
+func RegisterRoutes(g *gin.RouterGroup, pool *pgxpool.Pool, client qport.Client, router *realtime.Router, checkOrigin auth.OriginChecker,
+	isAdmin auth.AdminChecker, storage storageport.Storage, policy storageport.Policy) {
 	createCtl := controller.NewCreateChatController(pool)
 	sendMsgCtl := controller.NewSendMessageController(pool, client)
 	getMsgCtl := controller.NewGetMessageController(pool)
-	socketCtl := controller.NewChatSocketController(pool, router)
+	socketCtl := controller.NewChatSocketController(pool, router, checkOrigin, storage, policy)
+	updateChatCtl := controller.NewUpdateConversationController(pool)
+	addParticipantCtl := controller.NewAddParticipantController(pool)
+	removeParticipantCtl := controller.NewRemoveParticipantController(pool, router)
+	changeRoleCtl := controller.NewChangeParticipantRoleController(pool)
+	leaveCtl := controller.NewLeaveConversationController(pool, router)
+	blockCtl := controller.NewBlockUserController(pool, router)
+	unblockCtl := controller.NewUnblockUserController(pool, router)
+	listBlockedCtl := controller.NewListBlockedController(pool)
+	markReadCtl := controller.NewMarkReadController(pool, router)
+	readStateCtl := controller.NewGetReadStateController(pool)
+	unreadCtl := controller.NewListUnreadCountsController(pool)
+	receiptsCtl := controller.NewListReceiptsController(pool)
+	listSessionsCtl := controller.NewListSessionsController(router)
+	revokeSessionCtl := controller.NewRevokeSessionController(router)
+	presenceCtl := controller.NewGetPresenceController(pool, router)
+	editMsgCtl := controller.NewEditMessageController(pool, router)
+	deleteMsgCtl := controller.NewDeleteMessageController(pool, router)
+	listEditsCtl := controller.NewListMessageEditsController(pool)
+	threadCtl := controller.NewGetThreadController(pool)
+	addReactionCtl := controller.NewAddReactionController(pool, router)
+	removeReactionCtl := controller.NewRemoveReactionController(pool, router)
+	createAttachmentCtl := controller.NewCreateAttachmentController(pool, storage, policy)
+	completeAttachmentCtl := controller.NewCompleteAttachmentController(pool, client, storage)
+	getAttachmentCtl := controller.NewGetAttachmentController(pool, storage)
+	searchCtl := controller.NewSearchMessagesController(pool)
+	listConversationsCtl := controller.NewListConversationsController(pool)
+	muteCtl := controller.NewMuteConversationController(pool, router)
+	unmuteCtl := controller.NewUnmuteConversationController(pool, router)
+	notifyLevelCtl := controller.NewSetNotificationLevelController(pool, router)
+	registerDeviceCtl := controller.NewRegisterDeviceController(pool)
+	unregisterDeviceCtl := controller.NewUnregisterDeviceController(pool)
+	createWebhookCtl := controller.NewCreateWebhookController(pool)
+	listWebhooksCtl := controller.NewListWebhooksController(pool)
+	deleteWebhookCtl := controller.NewDeleteWebhookController(pool)
+	listDeliveriesCtl := controller.NewListWebhookDeliveriesController(pool)
+	replayDeliveryCtl := controller.NewReplayWebhookDeliveryController(pool, client)
 
 	// POST /api/v1/chat -> create a chat
 	g.POST("/chat", createCtl.Handle())
@@ -23,9 +63,119 @@ func RegisterRoutes(g *gin.RouterGroup, pool *pgxpool.Pool, client qport.Client,
 	// POST /api/v1/chat/:chatId -> send a message into a chat
 	g.POST("/chat/:chatId", sendMsgCtl.Handle())
 
+	// PATCH /api/v1/chat/:chatId -> rename a group or change its avatar
+	g.PATCH("/chat/:chatId", updateChatCtl.Handle())
+
+	// POST /api/v1/chat/:chatId/participants -> add a member to a group
+	g.POST("/chat/:chatId/participants", addParticipantCtl.Handle())
+
+	// PATCH /api/v1/chat/:chatId/participants/:userId -> change a member's role
+	g.PATCH("/chat/:chatId/participants/:userId", changeRoleCtl.Handle())
+
+	// DELETE /api/v1/chat/:chatId/participants/:userId -> remove a member from a group
+	g.DELETE("/chat/:chatId/participants/:userId", removeParticipantCtl.Handle())
+
+	// POST /api/v1/chat/:chatId/leave -> leave a group
+	g.POST("/chat/:chatId/leave", leaveCtl.Handle())
+
 	// GET /api/v1/chat/:chatId/messages -> fetch messages by chat id
 	g.GET("/chat/:chatId/messages", getMsgCtl.Handle())
 
+	// PATCH /api/v1/chat/:chatId/messages/:messageId -> edit a message (sender only)
+	g.PATCH("/chat/:chatId/messages/:messageId", editMsgCtl.Handle())
+
+	// DELETE /api/v1/chat/:chatId/messages/:messageId -> delete a message for everyone (sender or group admin)
+	g.DELETE("/chat/:chatId/messages/:messageId", deleteMsgCtl.Handle())
+
+	// GET /api/v1/chat/:chatId/messages/:messageId/edits -> previous bodies of an edited message
+	g.GET("/chat/:chatId/messages/:messageId/edits", listEditsCtl.Handle())
+
+	// GET /api/v1/chat/:chatId/messages/:messageId/thread -> page through the replies of a thread
+	g.GET("/chat/:chatId/messages/:messageId/thread", threadCtl.Handle())
+
+	// GET /api/v1/chat/:chatId/messages/:messageId/receipts -> per-recipient delivery receipts of a message
+	g.GET("/chat/:chatId/messages/:messageId/receipts", receiptsCtl.Handle())
+
+	// POST /api/v1/chat/:chatId/messages/:messageId/reactions -> react to a message with an emoji
+	g.POST("/chat/:chatId/messages/:messageId/reactions", addReactionCtl.Handle())
+
+	// DELETE /api/v1/chat/:chatId/messages/:messageId/reactions/:emoji -> remove the caller's reaction (emoji URL-encoded)
+	g.DELETE("/chat/:chatId/messages/:messageId/reactions/:emoji", removeReactionCtl.Handle())
+
+	// POST /api/v1/chat/:chatId/read -> advance the caller's read watermark
+	g.POST("/chat/:chatId/read", markReadCtl.Handle())
+
+	// POST /api/v1/chat/:chatId/mute -> mute the conversation's notifications for the caller, timed or indefinitely
+	g.POST("/chat/:chatId/mute", muteCtl.Handle())
+
+	// DELETE /api/v1/chat/:chatId/mute -> lift the caller's mute
+	g.DELETE("/chat/:chatId/mute", unmuteCtl.Handle())
+
+	// PUT /api/v1/chat/:chatId/notifications -> notify the caller of all messages, mentions only or none
+	g.PUT("/chat/:chatId/notifications", notifyLevelCtl.Handle())
+
+	// GET /api/v1/chat/:chatId/read -> caller's unread count and every member's read position
+	g.GET("/chat/:chatId/read", readStateCtl.Handle())
+
+	// GET /api/v1/conversations -> caller's inbox: conversations by last activity with preview, unread count and mute state
+	g.GET("/conversations", listConversationsCtl.Handle())
+
+	// GET /api/v1/unread -> caller's unread count per conversation
+	g.GET("/unread", unreadCtl.Handle())
+
+	// POST /api/v1/attachments -> register an upload and get its presigned PUT request
+	g.POST("/attachments", createAttachmentCtl.Handle())
+
+	// POST /api/v1/attachments/:attachmentId/complete -> confirm the upload landed and start processing it
+	g.POST("/attachments/:attachmentId/complete", completeAttachmentCtl.Handle())
+
+	// GET /api/v1/attachments/:attachmentId -> presigned download for the owner or participants who can see its message
+	g.GET("/attachments/:attachmentId", getAttachmentCtl.Handle())
+
+	// GET /api/v1/search/messages -> full-text search over the caller's conversations
+	g.GET("/search/messages", searchCtl.Handle())
+
+	// POST /api/v1/blocks -> block a user
+	g.POST("/blocks", blockCtl.Handle())
+
+	// GET /api/v1/blocks -> list users blocked by the caller
+	g.GET("/blocks", listBlockedCtl.Handle())
+
+	// DELETE /api/v1/blocks/:userId -> unblock a user
+	g.DELETE("/blocks/:userId", unblockCtl.Handle())
+
+	// GET /api/v1/sessions -> caller's active websocket sessions
+	g.GET("/sessions", listSessionsCtl.Handle())
+
+	// DELETE /api/v1/sessions/:sessionId -> close one of the caller's websocket sessions
+	g.DELETE("/sessions/:sessionId", revokeSessionCtl.Handle())
+
+	// POST /api/v1/devices -> register a push token of the caller's device
+	g.POST("/devices", registerDeviceCtl.Handle())
+
+	// DELETE /api/v1/devices/:token -> stop pushing to a device (token URL-encoded)
+	g.DELETE("/devices/:token", unregisterDeviceCtl.Handle())
+
+	// GET /api/v1/presence?userIds=a,b -> online/away/offline and last-seen of users sharing a conversation with the caller
+	g.GET("/presence", presenceCtl.Handle())
+
 	// GET /api/v1/chat/ws -> websocket endpoint for realtime chat
 	g.GET("/chat/ws", socketCtl.Handle())
+
+	admin := g.Group("", auth.RequireAdmin(isAdmin))
+
+	// POST /api/v1/webhooks -> subscribe an endpoint to the events of the caller's tenant
+	admin.POST("/webhooks", createWebhookCtl.Handle())
+
+	// GET /api/v1/webhooks -> webhook subscriptions of the caller's tenant
+	admin.GET("/webhooks", listWebhooksCtl.Handle())
+
+	// DELETE /api/v1/webhooks/:webhookId -> unsubscribe a webhook
+	admin.DELETE("/webhooks/:webhookId", deleteWebhookCtl.Handle())
+
+	// GET /api/v1/webhooks/:webhookId/deliveries?status=dead|pending|delivered|all -> deliveries, dead letters by default
+	admin.GET("/webhooks/:webhookId/deliveries", listDeliveriesCtl.Handle())
+
+	// POST /api/v1/webhooks/:webhookId/deliveries/:deliveryId/replay -> send a delivery again
+	admin.POST("/webhooks/:webhookId/deliveries/:deliveryId/replay", replayDeliveryCtl.Handle())
 }